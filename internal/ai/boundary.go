@@ -0,0 +1,99 @@
+package ai
+
+import (
+	"path"
+	"slices"
+	"strings"
+)
+
+// MatchAction reports whether an IAM action pattern such as "s3:Get*" matches the action.
+// Actions are case-insensitive. A pattern also matches a narrower pattern, "s3:*" matches "s3:Get*".
+func MatchAction(pattern, action string) bool {
+	matched, err := path.Match(strings.ToLower(pattern), strings.ToLower(action))
+	return err == nil && matched
+}
+
+// actions returns the actions of an Action or NotAction element, "*" for a wildcard.
+func actions(ar *IAMActionResource) []string {
+	if ar == nil {
+		return nil
+	}
+	if ar.IsWildcard {
+		return []string{"*"}
+	}
+	return ar.Resources
+}
+
+// covers reports whether the statement applies to the action.
+func (s IAMStatement) covers(action string) bool {
+	if s.Action != nil {
+		return slices.ContainsFunc(actions(s.Action), func(pattern string) bool {
+			return MatchAction(pattern, action)
+		})
+	}
+	if s.NotAction != nil {
+		// The statement applies to everything except the listed actions, which may overlap the action
+		return !slices.ContainsFunc(actions(s.NotAction), func(pattern string) bool {
+			return MatchAction(pattern, action) || MatchAction(action, pattern)
+		})
+	}
+	return false
+}
+
+// ActionsOutside returns the actions the policy allows that the boundary does not allow or denies.
+// Resources and conditions are not compared, so the result is a warning rather than a verdict.
+// Allow statements with NotAction are reported as "NotAction: ..." since they cannot be listed.
+func (p IAMPolicy) ActionsOutside(boundary IAMPolicy) []string {
+	var outside []string
+	for _, statement := range p.Statement {
+		if statement.Effect != "Allow" {
+			continue
+		}
+
+		if statement.NotAction != nil {
+			outside = append(outside, "NotAction: "+strings.Join(actions(statement.NotAction), ", "))
+			continue
+		}
+
+		for _, action := range actions(statement.Action) {
+			if slices.Contains(outside, action) {
+				continue
+			}
+
+			allowed, denied := false, false
+			for _, b := range boundary.Statement {
+				switch b.Effect {
+				case "Allow":
+					allowed = allowed || b.covers(action)
+				case "Deny":
+					// A denied part of a wildcard action is outside the boundary too
+					denied = denied || b.covers(action) || slices.ContainsFunc(actions(b.Action), func(pattern string) bool {
+						return MatchAction(action, pattern)
+					})
+				}
+			}
+			if !allowed || denied {
+				outside = append(outside, action)
+			}
+		}
+	}
+	return outside
+}
+
+// SimulatableActions returns the actions the Allow statements of the policy list, without duplicates.
+// Actions with wildcards are left out, the policy simulator only evaluates actual actions.
+func (p IAMPolicy) SimulatableActions() []string {
+	var simulatable []string
+	for _, statement := range p.Statement {
+		if statement.Effect != "Allow" {
+			continue
+		}
+		for _, action := range actions(statement.Action) {
+			if strings.ContainsAny(action, "*?") || slices.Contains(simulatable, action) {
+				continue
+			}
+			simulatable = append(simulatable, action)
+		}
+	}
+	return simulatable
+}
@@ -0,0 +1,89 @@
+package ai
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestActionsOutside(t *testing.T) {
+	boundary := `{"Version":"2012-10-17","Statement":[
+		{"Effect":"Allow","Action":["s3:*","dynamodb:Get*"],"Resource":"*"},
+		{"Effect":"Deny","Action":"s3:DeleteBucket","Resource":"*"}
+	]}`
+
+	tests := []struct {
+		name     string
+		policy   string
+		boundary string
+		want     []string
+	}{
+		{
+			name:     "allowed by the boundary",
+			policy:   `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["s3:GetObject","dynamodb:GetItem"],"Resource":"*"}]}`,
+			boundary: boundary,
+		},
+		{
+			name:     "not allowed by the boundary",
+			policy:   `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["dynamodb:PutItem","ec2:RunInstances"],"Resource":"*"}]}`,
+			boundary: boundary,
+			want:     []string{"dynamodb:PutItem", "ec2:RunInstances"},
+		},
+		{
+			name:     "denied by the boundary",
+			policy:   `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:DeleteBucket","Resource":"*"}]}`,
+			boundary: boundary,
+			want:     []string{"s3:DeleteBucket"},
+		},
+		{
+			name:     "wildcard partly denied by the boundary",
+			policy:   `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:Delete*","Resource":"*"}]}`,
+			boundary: boundary,
+			want:     []string{"s3:Delete*"},
+		},
+		{
+			name:     "actions are case-insensitive",
+			policy:   `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"S3:getobject","Resource":"*"}]}`,
+			boundary: boundary,
+		},
+		{
+			name:     "deny statements of the policy are ignored",
+			policy:   `{"Version":"2012-10-17","Statement":[{"Effect":"Deny","Action":"ec2:*","Resource":"*"}]}`,
+			boundary: boundary,
+		},
+		{
+			name:     "not action cannot be listed",
+			policy:   `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","NotAction":["iam:*","organizations:*"],"Resource":"*"}]}`,
+			boundary: boundary,
+			want:     []string{"NotAction: iam:*, organizations:*"},
+		},
+		{
+			name:     "boundary with not action",
+			policy:   `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["s3:GetObject","iam:CreateUser"],"Resource":"*"}]}`,
+			boundary: `{"Version":"2012-10-17","Statement":{"Effect":"Allow","NotAction":"iam:*","Resource":"*"}}`,
+			want:     []string{"iam:CreateUser"},
+		},
+		{
+			name:     "duplicates are reported once",
+			policy:   `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"ec2:RunInstances","Resource":"*"},{"Effect":"Allow","Action":"ec2:RunInstances","Resource":"*"}]}`,
+			boundary: boundary,
+			want:     []string{"ec2:RunInstances"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := ParsePolicy(tt.policy)
+			if err != nil {
+				t.Fatal(err)
+			}
+			boundary, err := ParsePolicy(tt.boundary)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := policy.ActionsOutside(boundary); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ActionsOutside() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,468 @@
+package ai
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Entities are the names of the account and the managed policies ParseIntent matches a request
+// against. Any of them may be empty, names are then taken from the request as written.
+type Entities struct {
+	Users  []string
+	Groups []string
+	Roles  []string
+	// Policies are the ARNs of the AWS managed policies.
+	Policies []string
+}
+
+// intentService is a service a request may mention, with the resource type of its custom policies
+// and the prefix of its managed policies, e.g. "AmazonS3" for AmazonS3ReadOnlyAccess.
+type intentService struct {
+	keywords     []string
+	resourceType string
+	policyPrefix string
+}
+
+var intentServices = []intentService{
+	{keywords: []string{"s3"}, resourceType: "AWS::S3::Bucket", policyPrefix: "AmazonS3"},
+	{keywords: []string{"ec2"}, resourceType: "AWS::EC2::Instance", policyPrefix: "AmazonEC2"},
+	{keywords: []string{"dynamodb", "dynamo"}, resourceType: "AWS::DynamoDB::Table", policyPrefix: "AmazonDynamoDB"},
+	{keywords: []string{"lambda"}, resourceType: "AWS::Lambda::Function", policyPrefix: "AWSLambda_"},
+	{keywords: []string{"rds"}, resourceType: "AWS::RDS::DBInstance", policyPrefix: "AmazonRDS"},
+	{keywords: []string{"sqs"}, resourceType: "AWS::SQS::Queue", policyPrefix: "AmazonSQS"},
+	{keywords: []string{"sns"}, resourceType: "AWS::SNS::Topic", policyPrefix: "AmazonSNS"},
+	{keywords: []string{"iam"}, resourceType: "AWS::IAM::Role", policyPrefix: "IAM"},
+	{keywords: []string{"cloudwatch"}, policyPrefix: "CloudWatch"},
+	{keywords: []string{"logs"}, resourceType: "AWS::Logs::LogGroup", policyPrefix: "CloudWatchLogs"},
+	{keywords: []string{"ecr"}, resourceType: "AWS::ECR::Repository", policyPrefix: "AmazonEC2ContainerRegistry"},
+	{keywords: []string{"cloudformation"}, resourceType: "AWS::CloudFormation::Stack", policyPrefix: "AWSCloudFormation"},
+	{keywords: []string{"route53"}, resourceType: "AWS::Route53::HostedZone", policyPrefix: "AmazonRoute53"},
+}
+
+// intentResources maps the words that name a resource to its type, "bucket reports" is an S3 bucket.
+var intentResources = map[string]string{
+	"bucket":     "AWS::S3::Bucket",
+	"table":      "AWS::DynamoDB::Table",
+	"function":   "AWS::Lambda::Function",
+	"queue":      "AWS::SQS::Queue",
+	"topic":      "AWS::SNS::Topic",
+	"secret":     "AWS::SecretsManager::Secret",
+	"instance":   "AWS::EC2::Instance",
+	"database":   "AWS::RDS::DBInstance",
+	"repository": "AWS::ECR::Repository",
+	"stack":      "AWS::CloudFormation::Stack",
+}
+
+// intentKinds maps the words that name a principal to its kind.
+var intentKinds = map[string]string{
+	"user": "users", "users": "users",
+	"group": "groups", "groups": "groups",
+	"role": "roles", "roles": "roles",
+}
+
+// Access levels of the managed policies a request may ask for.
+var (
+	intentReadWords  = []string{"read", "read-only", "readonly", "view", "viewer", "list"}
+	intentFullWords  = []string{"full", "write", "admin", "administrator", "manage", "all"}
+	intentStopWords  = []string{"a", "an", "the", "to", "from", "for", "on", "in", "into", "of", "and", "with", "access", "named", "called", "policy", "permissions", "permission"}
+	intentGrantVerbs = []string{"give", "grant", "attach", "allow", "add", "assign", "let"}
+	intentDenyVerbs  = []string{"remove", "revoke", "detach", "delete", "take", "drop", "deny"}
+)
+
+// mention is a principal a request names, "user omer" or a name found in Entities.
+type mention struct {
+	kind string
+	name string
+}
+
+// ParseIntent turns a request such as "give S3 read-only access to user omer" into a response
+// without a language model. It recognizes the verb, the principal and a managed policy, service or
+// resource the request mentions. Error is set, and Message says why, when the operation, the
+// principal or what the operation needs, the group or the policy, is not recognized.
+// Confidence rates the policy: 10 for a policy named in the request, 8 for a managed policy found
+// for the service and 5 for a guessed one.
+func ParseIntent(prompt string, entities Entities) GPTResponse {
+	words := intentWords(prompt)
+	lower := make([]string, len(words))
+	for i, word := range words {
+		lower[i] = strings.ToLower(word)
+	}
+
+	has := func(candidates ...string) bool {
+		return slices.ContainsFunc(lower, func(word string) bool {
+			return slices.Contains(candidates, word)
+		})
+	}
+
+	response := GPTResponse{Principal: map[string]string{}}
+
+	// Principals named with their kind, "user omer", and the words that name a resource or policy
+	var mentions []mention
+	used := map[int]bool{}
+	// Kinds mentioned without a name, "add user omer to group"
+	unnamed := map[string]bool{}
+	for i := 0; i < len(words); i++ {
+		kind, ok := intentKinds[lower[i]]
+		if !ok {
+			continue
+		}
+		// The name follows the kind, "group developers", unless it comes before it, "the developers group"
+		j := next(lower, i+1)
+		if (j < 0 || j > i+1) && i > 0 && !used[i-1] && !isKeyword(lower[i-1]) && !looksLikePolicy(words[i-1]) {
+			j = i - 1
+		}
+		if j < 0 {
+			unnamed[kind] = true
+			continue
+		}
+		mentions = append(mentions, mention{kind: kind, name: resolveName(words[j], entityNames(entities, kind))})
+		used[i], used[j] = true, true
+		i = max(i, j)
+	}
+
+	for i := range words {
+		if used[i] {
+			continue
+		}
+		if resourceType, ok := intentResources[lower[i]]; ok && response.RequestedResource == "" {
+			if j := next(lower, i+1); j >= 0 && !isKeyword(lower[j]) {
+				response.RequestedResourceType, response.RequestedResource = resourceType, words[j]
+				used[i], used[j] = true, true
+			}
+		}
+		if strings.HasPrefix(lower[i], "arn:") && response.RequestedResource == "" {
+			response.RequestedResource = words[i]
+			response.RequestedResourceType = resourceTypeOfArn(lower[i])
+			used[i] = true
+		}
+		if lower[i] == "policy" && i > 0 && lower[i-1] == "inline" && response.Policy == "" {
+			if j := next(lower, i+1); j >= 0 && !isKeyword(lower[j]) {
+				response.Policy = words[j]
+				used[j] = true
+			}
+		}
+	}
+
+	// Names of the account mentioned without their kind, "give omer ..."
+	for i, word := range words {
+		if used[i] || isKeyword(lower[i]) {
+			continue
+		}
+		for _, kind := range []string{"users", "groups", "roles"} {
+			if name := matchName(word, entityNames(entities, kind)); name != "" {
+				mentions = append(mentions, mention{kind: kind, name: name})
+				used[i] = true
+				break
+			}
+		}
+	}
+
+	// Names the account does not know, the principal of "give omer ..." is the word after "to" or
+	// "from", or else the first one that is not part of the phrasing. Without a verb the words are
+	// not a request, "hello there" names no one.
+	var unknown []int
+	request := has(intentGrantVerbs...) || has(intentDenyVerbs...) || has("put")
+	for i := range words {
+		if request && !used[i] && !isKeyword(lower[i]) && !looksLikePolicy(words[i]) && namedPolicy(words[i:i+1], nil, entities.Policies) == "" {
+			unknown = append(unknown, i)
+		}
+	}
+	follows := func(i int, candidates ...string) bool {
+		// Articles are skipped, "from the developers"
+		for i > 0 && slices.Contains([]string{"a", "an", "the"}, lower[i-1]) {
+			i--
+		}
+		return i > 0 && slices.Contains(candidates, lower[i-1])
+	}
+	switch {
+	case len(unknown) >= 2 && has("add", "put", "remove") && follows(unknown[1], "to", "into", "in", "from") &&
+		len(mentions) == 0:
+		// "add omer to developers" names the user, then the group
+		mentions = append(mentions, mention{kind: "users", name: words[unknown[0]]}, mention{kind: "groups", name: words[unknown[1]]})
+	case len(unknown) > 0 && !slices.ContainsFunc(mentions, isUser) && !slices.ContainsFunc(mentions, isRole) &&
+		(len(mentions) == 0 || has("add", "put", "remove", "from")):
+		name := words[unknown[0]]
+		for _, i := range unknown {
+			if follows(i, "to", "from", "for") {
+				name = words[i]
+				break
+			}
+		}
+		mentions = append(mentions, mention{kind: "users", name: name})
+	}
+
+	// Verbs, a group mentioned without its name still makes the request a membership change
+	group := slices.ContainsFunc(mentions, isGroup) || unnamed["groups"]
+	boundary := has("boundary", "permissions-boundary")
+	inline := has("inline")
+	removing := has(intentDenyVerbs...)
+	switch {
+	case boundary && removing:
+		response.Action = "remove_permissions_boundary"
+	case boundary:
+		response.Action = "set_permissions_boundary"
+	case inline && removing:
+		response.Action = "remove_inline_policy"
+	case inline:
+		response.Action = "attach_inline_policy"
+	case removing && group && slices.ContainsFunc(mentions, isUser) && has("from"):
+		response.Action = "remove_from_group"
+	case has("add", "put") && group && slices.ContainsFunc(mentions, isUser) && has("to", "in", "into"):
+		response.Action = "add_to_group"
+	case removing:
+		response.Action = "detach_policy"
+	case has(intentGrantVerbs...):
+		response.Action = "attach_policy"
+	}
+
+	// Principal, a user added to or removed from a group is the principal of the group
+	if response.Action == "add_to_group" || response.Action == "remove_from_group" {
+		for _, m := range mentions {
+			if m.kind == "groups" && response.Group == "" {
+				response.Group = m.name
+			} else if m.kind == "users" && response.Principal["name"] == "" {
+				response.Principal["type"], response.Principal["name"] = m.kind, m.name
+			}
+		}
+	} else if len(mentions) > 0 {
+		response.Principal["type"], response.Principal["name"] = mentions[0].kind, mentions[0].name
+	}
+
+	// Policy, a managed policy named in the request or one of the mentioned service
+	if response.Action != "add_to_group" && response.Action != "remove_from_group" && !inline {
+		if arn := namedPolicy(words, used, entities.Policies); arn != "" {
+			response.Policy, response.IsManagedPolicy, response.Confidence = arn, true, 10
+		} else if name := guessedPolicy(words, used); name != "" && len(entities.Policies) == 0 {
+			response.Policy, response.IsManagedPolicy, response.Confidence = "arn:aws:iam::aws:policy/"+name, true, 5
+		} else if response.RequestedResource != "" && response.Action == "attach_policy" {
+			// Access to a single resource needs a policy of its own
+			response.Action = "attach_custom_policy"
+		} else if arn, confidence := servicePolicy(lower, entities.Policies, has(intentFullWords...)); arn != "" {
+			response.Policy, response.IsManagedPolicy, response.Confidence = arn, true, confidence
+		}
+	}
+
+	if response.RequestedResourceType == "" {
+		if service := mentionedService(lower); service != nil {
+			response.RequestedResourceType = service.resourceType
+		}
+	}
+
+	// The operation needs a principal and what it is applied to
+	switch response.Action {
+	case "":
+		response.Message = "no operation was recognized"
+	case "add_to_group", "remove_from_group":
+		if response.Group == "" {
+			response.Message = "no group was recognized"
+		}
+	case "attach_policy", "detach_policy", "set_permissions_boundary":
+		if response.Policy == "" {
+			response.Message = "no policy was recognized"
+		}
+	}
+	if response.Message == "" && response.Principal["name"] == "" {
+		response.Message = "no user, group or role was recognized"
+	}
+
+	response.Error = response.Message != ""
+	return response
+}
+
+// Phrases that are read as a single word.
+var (
+	readOnlyPattern = regexp.MustCompile(`(?i)\bread only\b`)
+	boundaryPattern = regexp.MustCompile(`(?i)\bpermissions? boundary\b`)
+)
+
+// intentWords splits the request into words without the punctuation around them.
+func intentWords(prompt string) []string {
+	prompt = readOnlyPattern.ReplaceAllString(prompt, "read-only")
+	prompt = boundaryPattern.ReplaceAllString(prompt, "permissions-boundary")
+
+	var words []string
+	for _, field := range strings.Fields(prompt) {
+		if word := strings.Trim(field, ",.;!?\"'()"); word != "" {
+			words = append(words, word)
+		}
+	}
+	return words
+}
+
+// next returns the index of the first word from i that is not a stop word, -1 when there is none.
+func next(words []string, i int) int {
+	for ; i < len(words); i++ {
+		if !slices.Contains(intentStopWords, words[i]) {
+			return i
+		}
+	}
+	return -1
+}
+
+// isKeyword reports whether the word is part of the phrasing rather than a name.
+func isKeyword(word string) bool {
+	if _, ok := intentKinds[word]; ok {
+		return true
+	}
+	if _, ok := intentResources[word]; ok {
+		return true
+	}
+	return slices.Contains(intentStopWords, word) || slices.Contains(intentGrantVerbs, word) || slices.Contains(intentDenyVerbs, word) ||
+		slices.Contains(intentReadWords, word) || slices.Contains(intentFullWords, word) || mentionedService([]string{word}) != nil ||
+		slices.Contains([]string{"put", "inline", "boundary", "permissions-boundary", "me", "him", "her", "them"}, word)
+}
+
+func isUser(m mention) bool  { return m.kind == "users" }
+func isGroup(m mention) bool { return m.kind == "groups" }
+func isRole(m mention) bool  { return m.kind == "roles" }
+
+// entityNames returns the names of the principals of the kind.
+func entityNames(entities Entities, kind string) []string {
+	switch kind {
+	case "users":
+		return entities.Users
+	case "groups":
+		return entities.Groups
+	case "roles":
+		return entities.Roles
+	}
+	return nil
+}
+
+// matchName returns the name the word refers to, the name itself ignoring case, or the name of
+// which the word is a part, "omer" for "Omer.Yilmaz". It returns "" when the word matches no name
+// or a part of several.
+func matchName(word string, names []string) string {
+	var partial []string
+	for _, name := range names {
+		if strings.EqualFold(name, word) {
+			return name
+		}
+		parts := strings.FieldsFunc(name, func(r rune) bool {
+			return strings.ContainsRune("._-@+=,", r)
+		})
+		if slices.ContainsFunc(parts, func(part string) bool { return strings.EqualFold(part, word) }) {
+			partial = append(partial, name)
+		}
+	}
+	if len(partial) == 1 {
+		return partial[0]
+	}
+	return ""
+}
+
+// resolveName returns the name the word refers to, or the word when it matches no name.
+func resolveName(word string, names []string) string {
+	if name := matchName(word, names); name != "" {
+		return name
+	}
+	return word
+}
+
+// namedPolicy returns the ARN of a managed policy named in the request, e.g. "AdministratorAccess".
+func namedPolicy(words []string, used map[int]bool, policies []string) string {
+	for i, word := range words {
+		if used[i] {
+			continue
+		}
+		for _, arn := range policies {
+			if strings.EqualFold(arn, word) || strings.EqualFold(policyName(arn), word) {
+				return arn
+			}
+		}
+	}
+	return ""
+}
+
+// looksLikePolicy reports whether the word reads as the name of a managed policy, such as
+// "AmazonS3FullAccess", rather than the name of a principal.
+func looksLikePolicy(word string) bool {
+	lower := strings.ToLower(word)
+	return strings.HasPrefix(lower, "arn:aws:iam::aws:policy/") ||
+		strings.HasSuffix(lower, "access") && len(lower) > len("access") && word[0] >= 'A' && word[0] <= 'Z'
+}
+
+// guessedPolicy returns the first word that looks like a managed policy, for when the managed
+// policies are not known.
+func guessedPolicy(words []string, used map[int]bool) string {
+	for i, word := range words {
+		if !used[i] && looksLikePolicy(word) {
+			return policyName(word)
+		}
+	}
+	return ""
+}
+
+// servicePolicy returns the read-only or full access managed policy of the service the request
+// mentions, or AdministratorAccess and ReadOnlyAccess when it mentions none. The policy is only
+// guessed when the managed policies are not known.
+func servicePolicy(words []string, policies []string, full bool) (string, int) {
+	suffix := "ReadOnlyAccess"
+	if full {
+		suffix = "FullAccess"
+	}
+
+	service := mentionedService(words)
+	name := suffix
+	switch {
+	case service != nil:
+		name = service.policyPrefix + suffix
+	case full && slices.ContainsFunc(words, func(word string) bool { return word == "admin" || word == "administrator" }):
+		name = "AdministratorAccess"
+	case !slices.ContainsFunc(words, func(word string) bool { return slices.Contains(intentReadWords, word) }):
+		// Without a service or an access level there is nothing to go by
+		return "", 0
+	}
+
+	if len(policies) == 0 {
+		return "arn:aws:iam::aws:policy/" + name, 5
+	}
+
+	// The exact name, otherwise the shortest policy of the service with the access level
+	var best string
+	for _, arn := range policies {
+		candidate := policyName(arn)
+		if strings.EqualFold(candidate, name) {
+			return arn, 8
+		}
+		if service != nil && strings.HasPrefix(candidate, service.policyPrefix) && strings.HasSuffix(candidate, suffix) &&
+			(best == "" || len(candidate) < len(policyName(best))) {
+			best = arn
+		}
+	}
+	if best != "" {
+		return best, 8
+	}
+	return "", 0
+}
+
+// mentionedService returns the first service the words mention.
+func mentionedService(words []string) *intentService {
+	for _, word := range words {
+		for i, service := range intentServices {
+			if slices.Contains(service.keywords, word) {
+				return &intentServices[i]
+			}
+		}
+	}
+	return nil
+}
+
+// resourceTypeOfArn returns the resource type of the service of the ARN, "" when it is not known.
+func resourceTypeOfArn(arn string) string {
+	parts := strings.SplitN(arn, ":", 4)
+	if len(parts) < 3 {
+		return ""
+	}
+	if service := mentionedService([]string{parts[2]}); service != nil {
+		return service.resourceType
+	}
+	return ""
+}
+
+// policyName returns the name of a policy ARN, "ReadOnlyAccess" for
+// "arn:aws:iam::aws:policy/ReadOnlyAccess".
+func policyName(arn string) string {
+	return arn[strings.LastIndex(arn, "/")+1:]
+}
@@ -0,0 +1,236 @@
+package ai
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseIntent(t *testing.T) {
+	account := Entities{
+		Users:  []string{"Omer.Yilmaz", "alice"},
+		Groups: []string{"developers", "ops"},
+		Roles:  []string{"deploy"},
+		Policies: []string{
+			"arn:aws:iam::aws:policy/AdministratorAccess",
+			"arn:aws:iam::aws:policy/ReadOnlyAccess",
+			"arn:aws:iam::aws:policy/AmazonS3ReadOnlyAccess",
+			"arn:aws:iam::aws:policy/AmazonS3FullAccess",
+			"arn:aws:iam::aws:policy/AmazonDynamoDBFullAccess",
+		},
+	}
+
+	tests := []struct {
+		name     string
+		prompt   string
+		entities Entities
+		want     GPTResponse
+	}{
+		{
+			name:   "service read-only access offline",
+			prompt: "give S3 read-only access to user Omer",
+			want: GPTResponse{
+				Action:                "attach_policy",
+				Principal:             map[string]string{"type": "users", "name": "Omer"},
+				RequestedResourceType: "AWS::S3::Bucket",
+				Policy:                "arn:aws:iam::aws:policy/AmazonS3ReadOnlyAccess",
+				IsManagedPolicy:       true,
+				Confidence:            5,
+			},
+		},
+		{
+			name:     "service read only access against the account",
+			prompt:   "give S3 read only access to user omer",
+			entities: account,
+			want: GPTResponse{
+				Action:                "attach_policy",
+				Principal:             map[string]string{"type": "users", "name": "Omer.Yilmaz"},
+				RequestedResourceType: "AWS::S3::Bucket",
+				Policy:                "arn:aws:iam::aws:policy/AmazonS3ReadOnlyAccess",
+				IsManagedPolicy:       true,
+				Confidence:            8,
+			},
+		},
+		{
+			name:     "principal without its kind",
+			prompt:   "give omer full access to dynamodb",
+			entities: account,
+			want: GPTResponse{
+				Action:                "attach_policy",
+				Principal:             map[string]string{"type": "users", "name": "Omer.Yilmaz"},
+				RequestedResourceType: "AWS::DynamoDB::Table",
+				Policy:                "arn:aws:iam::aws:policy/AmazonDynamoDBFullAccess",
+				IsManagedPolicy:       true,
+				Confidence:            8,
+			},
+		},
+		{
+			name:     "named managed policy",
+			prompt:   "attach AdministratorAccess to role deploy",
+			entities: account,
+			want: GPTResponse{
+				Action:          "attach_policy",
+				Principal:       map[string]string{"type": "roles", "name": "deploy"},
+				Policy:          "arn:aws:iam::aws:policy/AdministratorAccess",
+				IsManagedPolicy: true,
+				Confidence:      10,
+			},
+		},
+		{
+			name:   "guessed managed policy",
+			prompt: "detach AmazonS3FullAccess from group developers",
+			want: GPTResponse{
+				Action:          "detach_policy",
+				Principal:       map[string]string{"type": "groups", "name": "developers"},
+				Policy:          "arn:aws:iam::aws:policy/AmazonS3FullAccess",
+				IsManagedPolicy: true,
+				Confidence:      5,
+			},
+		},
+		{
+			name:   "access to a resource",
+			prompt: "grant role deploy access to bucket reports",
+			want: GPTResponse{
+				Action:                "attach_custom_policy",
+				Principal:             map[string]string{"type": "roles", "name": "deploy"},
+				RequestedResourceType: "AWS::S3::Bucket",
+				RequestedResource:     "reports",
+			},
+		},
+		{
+			name:   "add to group with kinds",
+			prompt: "add user omer to group developers",
+			want: GPTResponse{
+				Action:    "add_to_group",
+				Principal: map[string]string{"type": "users", "name": "omer"},
+				Group:     "developers",
+			},
+		},
+		{
+			name:   "add to group without kinds",
+			prompt: "add omer to developers",
+			want: GPTResponse{
+				Action:    "add_to_group",
+				Principal: map[string]string{"type": "users", "name": "omer"},
+				Group:     "developers",
+			},
+		},
+		{
+			name:   "remove from group named before its kind",
+			prompt: "remove alice from the developers group",
+			want: GPTResponse{
+				Action:    "remove_from_group",
+				Principal: map[string]string{"type": "users", "name": "alice"},
+				Group:     "developers",
+			},
+		},
+		{
+			name:     "remove from group against the account",
+			prompt:   "remove alice from ops",
+			entities: account,
+			want: GPTResponse{
+				Action:    "remove_from_group",
+				Principal: map[string]string{"type": "users", "name": "alice"},
+				Group:     "ops",
+			},
+		},
+		{
+			name:   "set permissions boundary",
+			prompt: "set permissions boundary PowerUserAccess on user omer",
+			want: GPTResponse{
+				Action:          "set_permissions_boundary",
+				Principal:       map[string]string{"type": "users", "name": "omer"},
+				Policy:          "arn:aws:iam::aws:policy/PowerUserAccess",
+				IsManagedPolicy: true,
+				Confidence:      5,
+			},
+		},
+		{
+			name:   "remove permissions boundary",
+			prompt: "remove the permissions boundary of role deploy",
+			want: GPTResponse{
+				Action:    "remove_permissions_boundary",
+				Principal: map[string]string{"type": "roles", "name": "deploy"},
+			},
+		},
+		{
+			name:   "attach inline policy",
+			prompt: "attach inline policy s3-logs to user omer",
+			want: GPTResponse{
+				Action:    "attach_inline_policy",
+				Principal: map[string]string{"type": "users", "name": "omer"},
+				Policy:    "s3-logs",
+			},
+		},
+		{
+			name:   "remove inline policy",
+			prompt: "remove inline policy s3-logs from user omer",
+			want: GPTResponse{
+				Action:    "remove_inline_policy",
+				Principal: map[string]string{"type": "users", "name": "omer"},
+				Policy:    "s3-logs",
+			},
+		},
+		{
+			name:   "no operation",
+			prompt: "hello there",
+			want: GPTResponse{
+				Principal: map[string]string{},
+				Error:     true,
+				Message:   "no operation was recognized",
+			},
+		},
+		{
+			name:   "no principal",
+			prompt: "give S3 read-only access",
+			want: GPTResponse{
+				Action:                "attach_policy",
+				Principal:             map[string]string{},
+				RequestedResourceType: "AWS::S3::Bucket",
+				Policy:                "arn:aws:iam::aws:policy/AmazonS3ReadOnlyAccess",
+				IsManagedPolicy:       true,
+				Confidence:            5,
+				Error:                 true,
+				Message:               "no user, group or role was recognized",
+			},
+		},
+		{
+			name:   "no policy to detach",
+			prompt: "remove alice",
+			want: GPTResponse{
+				Action:    "detach_policy",
+				Principal: map[string]string{"type": "users", "name": "alice"},
+				Error:     true,
+				Message:   "no policy was recognized",
+			},
+		},
+		{
+			name:   "removed from a group without a name",
+			prompt: "remove user omer from the group",
+			want: GPTResponse{
+				Action:    "remove_from_group",
+				Principal: map[string]string{"type": "users", "name": "omer"},
+				Error:     true,
+				Message:   "no group was recognized",
+			},
+		},
+		{
+			name:   "group without a name",
+			prompt: "add user omer to group",
+			want: GPTResponse{
+				Action:    "add_to_group",
+				Principal: map[string]string{"type": "users", "name": "omer"},
+				Error:     true,
+				Message:   "no group was recognized",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseIntent(tt.prompt, tt.entities)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseIntent(%q) = %+v, want %+v", tt.prompt, got, tt.want)
+			}
+		})
+	}
+}
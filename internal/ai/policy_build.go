@@ -0,0 +1,82 @@
+package ai
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ConditionOperators lists the condition operators of IAM policies. An operator may be prefixed
+// with "ForAnyValue:" or "ForAllValues:" and, except for Null, suffixed with "IfExists".
+var ConditionOperators = []string{
+	"StringEquals", "StringNotEquals", "StringEqualsIgnoreCase", "StringNotEqualsIgnoreCase", "StringLike", "StringNotLike",
+	"NumericEquals", "NumericNotEquals", "NumericLessThan", "NumericLessThanEquals", "NumericGreaterThan", "NumericGreaterThanEquals",
+	"DateEquals", "DateNotEquals", "DateLessThan", "DateLessThanEquals", "DateGreaterThan", "DateGreaterThanEquals",
+	"Bool", "BinaryEquals", "IpAddress", "NotIpAddress", "ArnEquals", "ArnLike", "ArnNotEquals", "ArnNotLike", "Null",
+}
+
+// ParseConditions parses conditions written one per line as "<operator> <key> <value>[,<value>...]",
+// e.g. "StringEquals aws:RequestedRegion eu-central-1". Empty lines are skipped.
+func ParseConditions(text string) (map[string]interface{}, error) {
+	conditions := map[string]interface{}{}
+	for _, line := range strings.Split(text, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("invalid condition '%s', expected: <operator> <key> <value>[,<value>...]", strings.TrimSpace(line))
+		}
+
+		operator, key := fields[0], fields[1]
+		base := strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(operator, "ForAnyValue:"), "ForAllValues:"), "IfExists")
+		if !slices.Contains(ConditionOperators, base) {
+			return nil, fmt.Errorf("unknown condition operator '%s'", operator)
+		}
+		if !strings.Contains(key, ":") {
+			return nil, fmt.Errorf("invalid condition key '%s', expected service:key", key)
+		}
+
+		block, ok := conditions[operator].(map[string]interface{})
+		if !ok {
+			block = map[string]interface{}{}
+			conditions[operator] = block
+		}
+
+		values := strings.Split(fields[2], ",")
+		if len(values) == 1 {
+			block[key] = values[0]
+		} else {
+			block[key] = values
+		}
+	}
+
+	if len(conditions) == 0 {
+		return nil, nil
+	}
+	return conditions, nil
+}
+
+// BuildPolicy builds a policy that allows the actions on the resources. Unscoped actions, which do
+// not apply to a resource type, are allowed on "*" in a statement of their own. The condition
+// applies to every statement.
+func BuildPolicy(id string, actions, resources, unscoped []string, condition map[string]interface{}) IAMPolicy {
+	policy := IAMPolicy{Version: "2012-10-17", Id: id}
+	if len(actions) > 0 {
+		policy.Statement = append(policy.Statement, IAMStatement{
+			Effect:    "Allow",
+			Action:    &IAMActionResource{Resources: actions},
+			Resource:  &IAMActionResource{Resources: resources},
+			Condition: condition,
+		})
+	}
+	if len(unscoped) > 0 {
+		policy.Statement = append(policy.Statement, IAMStatement{
+			Effect:    "Allow",
+			Action:    &IAMActionResource{Resources: unscoped},
+			Resource:  &IAMActionResource{IsWildcard: true},
+			Condition: condition,
+		})
+	}
+	return policy
+}
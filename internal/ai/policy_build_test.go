@@ -0,0 +1,122 @@
+package ai
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseConditions(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		want    map[string]interface{}
+		wantErr bool
+	}{
+		{
+			name: "no conditions",
+			text: "\n  \n",
+		},
+		{
+			name: "single value",
+			text: "StringEquals aws:RequestedRegion eu-central-1",
+			want: map[string]interface{}{
+				"StringEquals": map[string]interface{}{"aws:RequestedRegion": "eu-central-1"},
+			},
+		},
+		{
+			name: "several values and keys of one operator",
+			text: "StringEquals aws:RequestedRegion eu-central-1,eu-west-1\nStringEquals aws:PrincipalTag/team platform\n\nBool aws:SecureTransport true",
+			want: map[string]interface{}{
+				"StringEquals": map[string]interface{}{
+					"aws:RequestedRegion":   []string{"eu-central-1", "eu-west-1"},
+					"aws:PrincipalTag/team": "platform",
+				},
+				"Bool": map[string]interface{}{"aws:SecureTransport": "true"},
+			},
+		},
+		{
+			name: "set operator with IfExists",
+			text: "ForAnyValue:StringLikeIfExists aws:TagKeys team*",
+			want: map[string]interface{}{
+				"ForAnyValue:StringLikeIfExists": map[string]interface{}{"aws:TagKeys": "team*"},
+			},
+		},
+		{
+			name:    "unknown operator",
+			text:    "StringMatches aws:RequestedRegion eu-central-1",
+			wantErr: true,
+		},
+		{
+			name:    "key without a service",
+			text:    "StringEquals RequestedRegion eu-central-1",
+			wantErr: true,
+		},
+		{
+			name:    "missing value",
+			text:    "StringEquals aws:RequestedRegion",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseConditions(tt.text)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseConditions(%q) error = %v, want error %v", tt.text, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseConditions(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildPolicy(t *testing.T) {
+	condition := map[string]interface{}{"Bool": map[string]interface{}{"aws:SecureTransport": "true"}}
+
+	tests := []struct {
+		name      string
+		actions   []string
+		resources []string
+		unscoped  []string
+		condition map[string]interface{}
+		want      []IAMStatement
+	}{
+		{
+			name:      "scoped actions",
+			actions:   []string{"s3:GetObject"},
+			resources: []string{"arn:aws:s3:::reports/*", "arn:aws:s3:::logs/*"},
+			want: []IAMStatement{
+				{Effect: "Allow", Action: &IAMActionResource{Resources: []string{"s3:GetObject"}}, Resource: &IAMActionResource{Resources: []string{"arn:aws:s3:::reports/*", "arn:aws:s3:::logs/*"}}},
+			},
+		},
+		{
+			name:     "unscoped actions only",
+			unscoped: []string{"s3:ListAllMyBuckets"},
+			want: []IAMStatement{
+				{Effect: "Allow", Action: &IAMActionResource{Resources: []string{"s3:ListAllMyBuckets"}}, Resource: &IAMActionResource{IsWildcard: true}},
+			},
+		},
+		{
+			name:      "both with a condition",
+			actions:   []string{"s3:GetObject"},
+			resources: []string{"arn:aws:s3:::reports/*"},
+			unscoped:  []string{"s3:ListAllMyBuckets"},
+			condition: condition,
+			want: []IAMStatement{
+				{Effect: "Allow", Action: &IAMActionResource{Resources: []string{"s3:GetObject"}}, Resource: &IAMActionResource{Resources: []string{"arn:aws:s3:::reports/*"}}, Condition: condition},
+				{Effect: "Allow", Action: &IAMActionResource{Resources: []string{"s3:ListAllMyBuckets"}}, Resource: &IAMActionResource{IsWildcard: true}, Condition: condition},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := BuildPolicy("reports-read", tt.actions, tt.resources, tt.unscoped, tt.condition)
+			want := IAMPolicy{Version: "2012-10-17", Id: "reports-read", Statement: tt.want}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("BuildPolicy() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
@@ -1,12 +1,10 @@
 package ai
 
 import (
-	"bytes"
 	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
 	"strings"
+	"time"
 )
 
 type IAMPolicy struct {
@@ -211,16 +209,58 @@ func toStringSlice(v interface{}) []string {
 	}
 }
 
-func GeneratePolicy(apiKey, prompt string, serviceName, resourceArn *string) (IAMPolicy, error) {
-	url := "https://api.openai.com/v1/chat/completions"
+// ParsePolicy parses a policy document. IAM accepts a single statement object in place of
+// the statement list, which is read as a list of one.
+func ParsePolicy(document string) (IAMPolicy, error) {
+	var policy IAMPolicy
+	err := json.Unmarshal([]byte(document), &policy)
+	if err == nil {
+		return policy, nil
+	}
+
+	var single struct {
+		Version   string       `json:"Version"`
+		Id        string       `json:"Id,omitempty"`
+		Statement IAMStatement `json:"Statement"`
+	}
+	if json.Unmarshal([]byte(document), &single) != nil {
+		return IAMPolicy{}, err
+	}
+	return IAMPolicy{Version: single.Version, Id: single.Id, Statement: []IAMStatement{single.Statement}}, nil
+}
+
+// ExpireAt limits every Allow statement of the policy to requests made before t
+// with an aws:CurrentTime DateLessThan condition.
+func (p *IAMPolicy) ExpireAt(t time.Time) {
+	for i := range p.Statement {
+		statement := &p.Statement[i]
+		if statement.Effect != "Allow" {
+			continue
+		}
 
-	// Build detailed information for the service and resource.
+		if statement.Condition == nil {
+			statement.Condition = map[string]interface{}{}
+		}
+
+		dateLessThan, ok := statement.Condition["DateLessThan"].(map[string]interface{})
+		if !ok {
+			dateLessThan = map[string]interface{}{}
+		}
+		dateLessThan["aws:CurrentTime"] = t.UTC().Format(time.RFC3339)
+		statement.Condition["DateLessThan"] = dateLessThan
+	}
+}
+
+// GeneratePolicy asks the model for a policy described by the prompt, scoped to the service and
+// the resources when they are given.
+func GeneratePolicy(provider Provider, prompt string, serviceName *string, resourceArns []string) (IAMPolicy, error) {
+	// Build detailed information for the service and resources.
 	serviceAndResourceDetails := ""
-	if resourceArn != nil {
+	if len(resourceArns) > 0 {
 		if serviceName != nil {
-			serviceAndResourceDetails = fmt.Sprintf("The service name is: %s\nThe resource ARN is: %s", *serviceName, *resourceArn)
+			serviceAndResourceDetails = fmt.Sprintf("The service name is: %s\nThe resource ARNs are: %s", *serviceName, strings.Join(resourceArns, ", "))
 		} else {
-			serviceAndResourceDetails = fmt.Sprintf("The service name is: all services\nThe resource ARN is: %s", *resourceArn)
+			serviceAndResourceDetails = fmt.Sprintf("The service name is: all services\nThe resource ARNs are: %s", strings.Join(resourceArns, ", "))
 		}
 	} else {
 		if serviceName != nil {
@@ -230,63 +270,46 @@ func GeneratePolicy(apiKey, prompt string, serviceName, resourceArn *string) (IA
 		}
 	}
 
-	payload := map[string]interface{}{
-		"model":       "gpt-4o",
-		"temperature": 0.1,
-		"messages": []map[string]string{
-			{"role": "system", "content": "You are an assistant that produces IAM policies as JSON."},
-			{"role": "user", "content": fmt.Sprintf("%s%s", prompt, serviceAndResourceDetails)},
-		},
-		"response_format": map[string]interface{}{
-			"type":        "json_schema",
-			"json_schema": IAMPolicySchema,
-		},
-	}
+	return requestPolicy(provider, IAMPolicySchema, []map[string]string{
+		{"role": "system", "content": "You are an assistant that produces IAM policies as JSON."},
+		{"role": "user", "content": fmt.Sprintf("%s%s", prompt, serviceAndResourceDetails)},
+	})
+}
 
-	payloadBytes, err := json.Marshal(payload)
-	if err != nil {
-		return IAMPolicy{}, fmt.Errorf("failed to marshal payload: %w", err)
-	}
+// EditPolicy asks the model to apply a change described in natural language to an existing policy document.
+func EditPolicy(provider Provider, document, change string) (IAMPolicy, error) {
+	return requestPolicy(provider, IAMPolicySchema, []map[string]string{
+		{"role": "system", "content": "You are an assistant that edits IAM policies. Apply only the requested change, keep every other statement as it is, and return the complete policy as JSON."},
+		{"role": "user", "content": fmt.Sprintf("The current policy is:\n%s\n\nThe requested change is: %s", document, change)},
+	})
+}
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(payloadBytes))
-	if err != nil {
-		return IAMPolicy{}, fmt.Errorf("failed to create request: %w", err)
+// RefinePolicy asks the model to apply a follow-up to the current policy document of a conversation.
+// The history alternates the earlier requests of the user with the policies returned for them.
+func RefinePolicy(provider Provider, document string, history []map[string]string, change string) (IAMPolicy, error) {
+	messages := []map[string]string{
+		{"role": "system", "content": "You are an assistant that produces IAM policies as JSON. The user refines the policy over several turns. Apply only the requested change to the current policy, keep every other statement as it is, and return the complete policy as JSON."},
 	}
+	messages = append(messages, history...)
+	messages = append(messages, map[string]string{
+		"role":    "user",
+		"content": fmt.Sprintf("The current policy is:\n%s\n\nThe requested change is: %s", document, change),
+	})
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+apiKey)
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return IAMPolicy{}, fmt.Errorf("request error: %w", err)
-	}
-	defer resp.Body.Close()
+	return requestPolicy(provider, IAMPolicySchema, messages)
+}
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return IAMPolicy{}, fmt.Errorf("unexpected status code: %d, body: %s", resp.StatusCode, string(body))
+// requestPolicy asks the provider to complete the messages and parses the structured policy in the reply.
+func requestPolicy(provider Provider, schema map[string]interface{}, messages []map[string]string) (IAMPolicy, error) {
+	if provider == nil {
+		return IAMPolicy{}, errNoProvider
 	}
 
-	var intermediateResponse struct {
-		Choices []struct {
-			Message struct {
-				Content string `json:"content"`
-			} `json:"message"`
-		} `json:"choices"`
-	}
-	bodyBytes, _ := io.ReadAll(resp.Body)
-	err = json.Unmarshal(bodyBytes, &intermediateResponse)
+	content, err := provider.Complete(schema, messages)
 	if err != nil {
-		return IAMPolicy{}, fmt.Errorf("failed to parse intermediate response: %w", err)
+		return IAMPolicy{}, err
 	}
 
-	if len(intermediateResponse.Choices) == 0 || intermediateResponse.Choices[0].Message.Content == "" {
-		return IAMPolicy{}, fmt.Errorf("no content found in response")
-	}
-
-	content := strings.TrimSpace(intermediateResponse.Choices[0].Message.Content)
-
 	var policy IAMPolicy
 	err = json.Unmarshal([]byte(content), &policy)
 	if err != nil {
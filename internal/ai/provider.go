@@ -0,0 +1,364 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+
+	internalaws "github.com/Permify/targe/internal/aws"
+	"github.com/Permify/targe/internal/config"
+)
+
+// Providers of the [ai] table of the configuration.
+const (
+	ProviderOpenAI     = "openai"
+	ProviderAzure      = "azure"
+	ProviderAnthropic  = "anthropic"
+	ProviderBedrock    = "bedrock"
+	ProviderCompatible = "openai-compatible"
+)
+
+// Providers lists the supported providers.
+var Providers = []string{ProviderOpenAI, ProviderAzure, ProviderAnthropic, ProviderBedrock, ProviderCompatible}
+
+// Defaults of the providers and the generation settings they share. Azure names the model by its
+// deployment and OpenAI-compatible servers have no default model.
+const (
+	defaultOpenAIModel    = "gpt-4o"
+	defaultAnthropicModel = "claude-3-5-sonnet-latest"
+	defaultBedrockModel   = "anthropic.claude-3-5-sonnet-20240620-v1:0"
+	defaultAzureVersion   = "2024-10-21"
+	anthropicVersion      = "2023-06-01"
+	maxTokens             = 4096
+	temperature           = 0.1
+)
+
+// errNoProvider is returned when neither a provider nor an OpenAI API key is configured.
+var errNoProvider = errors.New("no AI provider is configured, set one in the [ai] table of ~/.targe/config.toml or set openai_api_key")
+
+// Provider completes a conversation with a JSON document that follows a schema. The schema has
+// the structured output format of the chat completions API, a "name" and a JSON "schema".
+type Provider interface {
+	Complete(schema map[string]interface{}, messages []map[string]string) (string, error)
+}
+
+// NewProvider returns the provider the configuration selects. Without a provider, OpenAI is used
+// when openai_api_key is set, otherwise nil is returned and the AI features are disabled.
+func NewProvider(cfg *config.Config) (Provider, error) {
+	settings := cfg.AI
+
+	switch settings.Provider {
+	case "", ProviderOpenAI:
+		apiKey := settings.ApiKey
+		if apiKey == "" {
+			apiKey = cfg.OpenaiApiKey
+		}
+		if apiKey == "" {
+			if settings.Provider == "" {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("the openai provider requires ai.api_key or openai_api_key")
+		}
+		return &openAI{
+			url:    strings.TrimSuffix(orDefault(settings.BaseUrl, "https://api.openai.com/v1"), "/") + "/chat/completions",
+			model:  orDefault(settings.Model, defaultOpenAIModel),
+			header: http.Header{"Authorization": {"Bearer " + apiKey}},
+		}, nil
+
+	case ProviderCompatible:
+		if settings.BaseUrl == "" || settings.Model == "" {
+			return nil, fmt.Errorf("the %s provider requires ai.base_url and ai.model", ProviderCompatible)
+		}
+		header := http.Header{}
+		if settings.ApiKey != "" {
+			header.Set("Authorization", "Bearer "+settings.ApiKey)
+		}
+		return &openAI{
+			url:    strings.TrimSuffix(settings.BaseUrl, "/") + "/chat/completions",
+			model:  settings.Model,
+			header: header,
+		}, nil
+
+	case ProviderAzure:
+		if settings.BaseUrl == "" || settings.Deployment == "" || settings.ApiKey == "" {
+			return nil, fmt.Errorf("the %s provider requires ai.base_url, ai.deployment and ai.api_key", ProviderAzure)
+		}
+		return &openAI{
+			url: fmt.Sprintf("%s/openai/deployments/%s/chat/completions?api-version=%s",
+				strings.TrimSuffix(settings.BaseUrl, "/"), url.PathEscape(settings.Deployment), url.QueryEscape(orDefault(settings.ApiVersion, defaultAzureVersion))),
+			header: http.Header{"Api-Key": {settings.ApiKey}},
+		}, nil
+
+	case ProviderAnthropic:
+		if settings.ApiKey == "" {
+			return nil, fmt.Errorf("the %s provider requires ai.api_key", ProviderAnthropic)
+		}
+		return &anthropic{
+			url:    strings.TrimSuffix(orDefault(settings.BaseUrl, "https://api.anthropic.com"), "/") + "/v1/messages",
+			model:  orDefault(settings.Model, defaultAnthropicModel),
+			apiKey: settings.ApiKey,
+		}, nil
+
+	case ProviderBedrock:
+		awsCfg, err := internalaws.LoadConfig(context.Background(), internalaws.ConfigOptions{Profile: settings.Profile, Region: settings.Region})
+		if err != nil {
+			return nil, err
+		}
+		if awsCfg.Region == "" {
+			return nil, fmt.Errorf("the %s provider requires ai.region or a region in the AWS configuration", ProviderBedrock)
+		}
+		return &bedrock{
+			url:    strings.TrimSuffix(orDefault(settings.BaseUrl, "https://bedrock-runtime."+awsCfg.Region+".amazonaws.com"), "/"),
+			model:  orDefault(settings.Model, defaultBedrockModel),
+			region: awsCfg.Region,
+			config: awsCfg,
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown ai provider '%s', expected one of: %s", settings.Provider, strings.Join(Providers, ", "))
+	}
+}
+
+// openAI completes with the chat completions API of OpenAI, Azure OpenAI or an OpenAI-compatible
+// server such as Ollama or vLLM.
+type openAI struct {
+	url string
+	// model is empty on Azure, where the deployment selects it.
+	model  string
+	header http.Header
+}
+
+func (p *openAI) Complete(schema map[string]interface{}, messages []map[string]string) (string, error) {
+	payload := map[string]interface{}{
+		"temperature": temperature,
+		"messages":    messages,
+		"response_format": map[string]interface{}{
+			"type":        "json_schema",
+			"json_schema": schema,
+		},
+	}
+	if p.model != "" {
+		payload["model"] = p.model
+	}
+
+	var response struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+	}
+	if err := post(p.url, p.header, payload, nil, &response); err != nil {
+		return "", err
+	}
+
+	if len(response.Choices) == 0 || response.Choices[0].Message.Content == "" {
+		return "", fmt.Errorf("no content found in response")
+	}
+	return strings.TrimSpace(response.Choices[0].Message.Content), nil
+}
+
+// anthropic completes with the Messages API. The schema is enforced by forcing the model to call
+// a tool whose input has the schema.
+type anthropic struct {
+	url    string
+	model  string
+	apiKey string
+}
+
+func (p *anthropic) Complete(schema map[string]interface{}, messages []map[string]string) (string, error) {
+	system, conversation := splitSystem(messages)
+
+	var turns []map[string]interface{}
+	for _, message := range conversation {
+		turns = append(turns, map[string]interface{}{"role": message["role"], "content": message["content"]})
+	}
+
+	payload := map[string]interface{}{
+		"model":       p.model,
+		"max_tokens":  maxTokens,
+		"temperature": temperature,
+		"messages":    turns,
+		"tools": []map[string]interface{}{{
+			"name":         schema["name"],
+			"description":  "Returns the result as JSON.",
+			"input_schema": schema["schema"],
+		}},
+		"tool_choice": map[string]interface{}{"type": "tool", "name": schema["name"]},
+	}
+	if system != "" {
+		payload["system"] = system
+	}
+
+	header := http.Header{"X-Api-Key": {p.apiKey}, "Anthropic-Version": {anthropicVersion}}
+
+	var response struct {
+		Content []struct {
+			Type  string          `json:"type"`
+			Input json.RawMessage `json:"input"`
+		} `json:"content"`
+	}
+	if err := post(p.url, header, payload, nil, &response); err != nil {
+		return "", err
+	}
+
+	for _, content := range response.Content {
+		if content.Type == "tool_use" {
+			return string(content.Input), nil
+		}
+	}
+	return "", fmt.Errorf("no content found in response")
+}
+
+// bedrock completes with the Converse API of Amazon Bedrock, with the same forced tool call as
+// anthropic. Requests are signed with the credentials of the AWS configuration.
+type bedrock struct {
+	url    string
+	model  string
+	region string
+	config aws.Config
+}
+
+func (p *bedrock) Complete(schema map[string]interface{}, messages []map[string]string) (string, error) {
+	system, conversation := splitSystem(messages)
+
+	var turns []map[string]interface{}
+	for _, message := range conversation {
+		turns = append(turns, map[string]interface{}{
+			"role":    message["role"],
+			"content": []map[string]string{{"text": message["content"]}},
+		})
+	}
+
+	payload := map[string]interface{}{
+		"messages": turns,
+		"inferenceConfig": map[string]interface{}{
+			"maxTokens":   maxTokens,
+			"temperature": temperature,
+		},
+		"toolConfig": map[string]interface{}{
+			"tools": []map[string]interface{}{{
+				"toolSpec": map[string]interface{}{
+					"name":        schema["name"],
+					"description": "Returns the result as JSON.",
+					"inputSchema": map[string]interface{}{"json": schema["schema"]},
+				},
+			}},
+			"toolChoice": map[string]interface{}{"tool": map[string]interface{}{"name": schema["name"]}},
+		},
+	}
+	if system != "" {
+		payload["system"] = []map[string]string{{"text": system}}
+	}
+
+	sign := func(req *http.Request, body []byte) error {
+		ctx := context.Background()
+		credentials, err := p.config.Credentials.Retrieve(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve AWS credentials: %w", err)
+		}
+		hash := sha256.Sum256(body)
+		return v4.NewSigner().SignHTTP(ctx, credentials, req, hex.EncodeToString(hash[:]), "bedrock", p.region, time.Now())
+	}
+
+	// The model ID is a single path segment, its colons are escaped too
+	endpoint := p.url + "/model/" + strings.ReplaceAll(url.PathEscape(p.model), ":", "%3A") + "/converse"
+
+	var response struct {
+		Output struct {
+			Message struct {
+				Content []struct {
+					ToolUse *struct {
+						Input json.RawMessage `json:"input"`
+					} `json:"toolUse"`
+				} `json:"content"`
+			} `json:"message"`
+		} `json:"output"`
+	}
+	if err := post(endpoint, nil, payload, sign, &response); err != nil {
+		return "", err
+	}
+
+	for _, content := range response.Output.Message.Content {
+		if content.ToolUse != nil {
+			return string(content.ToolUse.Input), nil
+		}
+	}
+	return "", fmt.Errorf("no content found in response")
+}
+
+// post sends the payload as JSON and decodes the JSON response into v. The request is signed
+// with sign when one is given.
+func post(endpoint string, header http.Header, payload interface{}, sign func(*http.Request, []byte) error, v interface{}) error {
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", endpoint, bytes.NewBuffer(payloadBytes))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if sign != nil {
+		if err := sign(req, payloadBytes); err != nil {
+			return fmt.Errorf("failed to sign request: %w", err)
+		}
+	}
+
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code: %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("failed to parse intermediate response: %w", err)
+	}
+	return nil
+}
+
+// splitSystem separates the system messages, which the Anthropic and Bedrock APIs take apart
+// from the conversation.
+func splitSystem(messages []map[string]string) (string, []map[string]string) {
+	var system []string
+	var conversation []map[string]string
+	for _, message := range messages {
+		if message["role"] == "system" {
+			system = append(system, strings.TrimSpace(message["content"]))
+			continue
+		}
+		conversation = append(conversation, message)
+	}
+	return strings.Join(system, "\n\n"), conversation
+}
+
+func orDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
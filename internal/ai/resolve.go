@@ -0,0 +1,215 @@
+package ai
+
+import (
+	"sort"
+	"strings"
+)
+
+// Fields of a response Resolve may ask to choose a name for.
+const (
+	FieldPrincipal = "principal"
+	FieldGroup     = "group"
+	FieldPolicy    = "policy"
+)
+
+// MinConfidence is the confidence below which the policy of a response is always chosen by hand.
+const MinConfidence = 8
+
+// maxCandidates limits the names offered for a choice.
+const maxCandidates = 10
+
+// minScore is the score below which a name is not offered.
+const minScore = 0.4
+
+// Candidate is a name offered for a field, ranked by its score from 0 to 1.
+type Candidate struct {
+	// Value is the name, the ARN for policies.
+	Value string
+	// Label is the name shown for the candidate.
+	Label string
+	Score float64
+}
+
+// Resolution is a name of a response that does not match the account exactly, with the names it
+// may refer to from the most to the least likely.
+type Resolution struct {
+	Field string
+	// Kind is users, groups, roles or policies.
+	Kind       string
+	Query      string
+	Candidates []Candidate
+}
+
+// Resolve matches the principal, group and managed policy of the response against the entities.
+// A name that matches exactly is kept, otherwise the names it may refer to are returned to choose
+// from. The policy is also chosen when its confidence is below MinConfidence. Fields whose entities
+// are not known are kept as they are.
+func Resolve(response GPTResponse, entities Entities) []Resolution {
+	var resolutions []Resolution
+
+	if kind, name := response.Principal["type"], response.Principal["name"]; name != "" {
+		if resolution, ok := resolveField(FieldPrincipal, kind, name, entityNames(entities, kind)); ok {
+			resolutions = append(resolutions, resolution)
+		}
+	}
+
+	if response.Group != "" {
+		if resolution, ok := resolveField(FieldGroup, "groups", response.Group, entities.Groups); ok {
+			resolutions = append(resolutions, resolution)
+		}
+	}
+
+	if managedPolicy(response) && len(entities.Policies) > 0 {
+		exact := false
+		for _, arn := range entities.Policies {
+			if arn == response.Policy {
+				exact = true
+				break
+			}
+		}
+		if !exact || response.Confidence < MinConfidence {
+			var candidates []Candidate
+			for _, arn := range entities.Policies {
+				candidates = append(candidates, Candidate{Value: arn, Label: policyName(arn)})
+			}
+			resolutions = append(resolutions, Resolution{
+				Field:      FieldPolicy,
+				Kind:       "policies",
+				Query:      response.Policy,
+				Candidates: Rank(policyName(response.Policy), candidates),
+			})
+		}
+	}
+
+	return resolutions
+}
+
+// Apply sets the field of the response to the chosen name.
+func (r Resolution) Apply(response *GPTResponse, value string) {
+	switch r.Field {
+	case FieldPrincipal:
+		response.Principal["name"] = value
+	case FieldGroup:
+		response.Group = value
+	case FieldPolicy:
+		response.Policy, response.IsManagedPolicy = value, true
+	}
+}
+
+// resolveField returns the names of the kind the name may refer to, unless it is one of them.
+// IAM names are unique regardless of case, so a name that only differs in case is taken as is.
+func resolveField(field, kind, name string, names []string) (Resolution, bool) {
+	if len(names) == 0 {
+		return Resolution{}, false
+	}
+	for _, candidate := range names {
+		if strings.EqualFold(candidate, name) {
+			return Resolution{}, false
+		}
+	}
+
+	candidates := make([]Candidate, 0, len(names))
+	for _, candidate := range names {
+		candidates = append(candidates, Candidate{Value: candidate, Label: candidate})
+	}
+	return Resolution{Field: field, Kind: kind, Query: name, Candidates: Rank(name, candidates)}, true
+}
+
+// managedPolicy reports whether the policy of the response is an AWS managed policy, which is
+// looked up in the managed policy catalog.
+func managedPolicy(response GPTResponse) bool {
+	switch response.Action {
+	case "attach_policy", "detach_policy", "set_permissions_boundary":
+	default:
+		return false
+	}
+	return response.Policy != "" && (response.IsManagedPolicy || strings.HasPrefix(response.Policy, "arn:aws:iam::aws:policy/"))
+}
+
+// Rank scores the labels of the candidates against the query and returns the best ones from the
+// highest score. See Score.
+func Rank(query string, candidates []Candidate) []Candidate {
+	var ranked []Candidate
+	for _, candidate := range candidates {
+		candidate.Score = Score(query, candidate.Label)
+		if candidate.Score >= minScore {
+			ranked = append(ranked, candidate)
+		}
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].Score != ranked[j].Score {
+			return ranked[i].Score > ranked[j].Score
+		}
+		return len(ranked[i].Label) < len(ranked[j].Label)
+	})
+
+	if len(ranked) > maxCandidates {
+		ranked = ranked[:maxCandidates]
+	}
+	return ranked
+}
+
+// Score rates from 0 to 1 how likely the query refers to the name, ignoring case and separators:
+// 1 for the name itself, 0.9 when the query is a part of the name such as "omer" of "Omer.Yilmaz",
+// 0.8 when the name contains it, and otherwise the similarity of their spelling.
+func Score(query, name string) float64 {
+	q, n := strings.ToLower(query), strings.ToLower(name)
+	if q == "" {
+		return 0
+	}
+	if q == n {
+		return 1
+	}
+
+	parts := strings.FieldsFunc(n, func(r rune) bool {
+		return strings.ContainsRune("._-@+=, /", r)
+	})
+	for _, part := range parts {
+		if part == q {
+			return 0.9
+		}
+	}
+
+	compact := func(s string) string {
+		return strings.Map(func(r rune) rune {
+			if strings.ContainsRune("._-@+=, /", r) {
+				return -1
+			}
+			return r
+		}, s)
+	}
+	if compact(q) == "" {
+		return 0
+	}
+	if strings.Contains(compact(n), compact(q)) {
+		return 0.8
+	}
+
+	// Similarity of the spelling, one minus the share of edits needed to turn one into the other
+	q, n = compact(q), compact(n)
+	longest := max(len([]rune(q)), len([]rune(n)))
+	return 0.75 * (1 - float64(distance(q, n))/float64(longest))
+}
+
+// distance returns the Levenshtein distance of a and b.
+func distance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(rb)]
+}
@@ -0,0 +1,164 @@
+package ai
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Principal types of a trust policy statement.
+const (
+	PrincipalAWS       = "AWS"
+	PrincipalService   = "Service"
+	PrincipalFederated = "Federated"
+)
+
+// PrincipalTypes lists the principal types a trust policy can be changed for.
+var PrincipalTypes = []string{PrincipalAWS, PrincipalService, PrincipalFederated}
+
+// TrustPolicySchema is the structured output schema for role trust policies. Unlike
+// IAMPolicySchema, statements carry a Principal and conditions instead of a Resource.
+var TrustPolicySchema = map[string]interface{}{
+	"name": "TrustPolicy",
+	"schema": map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"Version": map[string]interface{}{
+				"type": "string",
+				"enum": []string{"2012-10-17"},
+			},
+			"Statement": map[string]interface{}{
+				"type": "array",
+				"items": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"Sid": map[string]interface{}{
+							"type": "string",
+						},
+						"Effect": map[string]interface{}{
+							"type": "string",
+							"enum": []string{"Allow", "Deny"},
+						},
+						"Principal": map[string]interface{}{
+							"type": "object",
+							"properties": map[string]interface{}{
+								"AWS":       map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+								"Service":   map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+								"Federated": map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+							},
+						},
+						"Action": map[string]interface{}{
+							"type":  "array",
+							"items": map[string]interface{}{"type": "string"},
+						},
+						"Condition": map[string]interface{}{
+							"type": "object",
+						},
+					},
+					"required": []string{"Effect", "Principal", "Action"},
+				},
+			},
+		},
+		"required": []string{"Version", "Statement"},
+	},
+}
+
+// PrincipalType resolves a principal type case-insensitively, e.g. "service" to Service.
+func PrincipalType(name string) (string, error) {
+	for _, t := range PrincipalTypes {
+		if strings.EqualFold(t, name) {
+			return t, nil
+		}
+	}
+	return "", fmt.Errorf("unknown principal type '%s', use one of %s", name, strings.Join(PrincipalTypes, ", "))
+}
+
+// assumeAction returns the STS action a principal uses to assume a role.
+func assumeAction(principalType, principal string) string {
+	if principalType != PrincipalFederated {
+		return "sts:AssumeRole"
+	}
+	if strings.Contains(principal, ":saml-provider/") {
+		return "sts:AssumeRoleWithSAML"
+	}
+	return "sts:AssumeRoleWithWebIdentity"
+}
+
+// principals returns the principals of the given type.
+func (p *IAMPrincipal) principals(principalType string) *[]string {
+	switch principalType {
+	case PrincipalService:
+		return &p.Service
+	case PrincipalFederated:
+		return &p.Federated
+	default:
+		return &p.AWS
+	}
+}
+
+// AddTrustPrincipal allows the principal to assume the role. It joins an existing unconditional
+// Allow statement with the same action, or adds a new statement. It reports false when the
+// principal is already trusted by such a statement.
+func (p *IAMPolicy) AddTrustPrincipal(principalType, principal string) bool {
+	action := assumeAction(principalType, principal)
+
+	for i := range p.Statement {
+		statement := &p.Statement[i]
+		if statement.Effect != "Allow" || statement.Principal == nil || statement.Principal.Star || len(statement.Condition) > 0 ||
+			statement.Action == nil || len(statement.Action.Resources) != 1 || statement.Action.Resources[0] != action {
+			continue
+		}
+
+		principals := statement.Principal.principals(principalType)
+		if slices.Contains(*principals, principal) {
+			return false
+		}
+		*principals = append(*principals, principal)
+		return true
+	}
+
+	statement := IAMStatement{
+		Effect:    "Allow",
+		Principal: &IAMPrincipal{},
+		Action:    &IAMActionResource{Resources: []string{action}},
+	}
+	*statement.Principal.principals(principalType) = []string{principal}
+	p.Statement = append(p.Statement, statement)
+	return true
+}
+
+// RemoveTrustPrincipal removes the principal from every statement. Statements left without a
+// principal are dropped. It reports false when the principal was not found.
+func (p *IAMPolicy) RemoveTrustPrincipal(principalType, principal string) bool {
+	removed := false
+	var statements []IAMStatement
+	for _, statement := range p.Statement {
+		if statement.Principal == nil || statement.Principal.Star {
+			statements = append(statements, statement)
+			continue
+		}
+
+		principals := statement.Principal.principals(principalType)
+		if i := slices.Index(*principals, principal); i >= 0 {
+			*principals = slices.Delete(*principals, i, i+1)
+			removed = true
+		}
+
+		left := statement.Principal
+		if len(left.AWS)+len(left.Service)+len(left.Federated)+len(left.CanonicalUser) == 0 {
+			continue
+		}
+		statements = append(statements, statement)
+	}
+
+	p.Statement = statements
+	return removed
+}
+
+// GenerateTrustPolicy asks the model to change a role trust policy as described in natural language.
+func GenerateTrustPolicy(provider Provider, document, change string) (IAMPolicy, error) {
+	return requestPolicy(provider, TrustPolicySchema, []map[string]string{
+		{"role": "system", "content": "You are an assistant that edits IAM role trust policies. Apply only the requested change, keep every other statement as it is, and return the complete trust policy as JSON. Use sts:AssumeRoleWithWebIdentity with aud and sub conditions for OIDC providers such as GitHub Actions."},
+		{"role": "user", "content": fmt.Sprintf("The current trust policy is:\n%s\n\nThe requested change is: %s", document, change)},
+	})
+}
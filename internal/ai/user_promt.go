@@ -1,12 +1,8 @@
 package ai
 
 import (
-	"bytes"
 	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
-	"strings"
 )
 
 type GPTResponse struct {
@@ -16,8 +12,11 @@ type GPTResponse struct {
 	RequestedResource     string            `json:"requested_resource"`
 	IsManagedPolicy       bool              `json:"is_managed_policy"`
 	Policy                string            `json:"policy"`
+	Group                 string            `json:"group"`
 	Error                 bool              `json:"error"`
 	Confidence            int               `json:"confidence"`
+	// Message tells what ParseIntent did not recognize when Error is set.
+	Message string `json:"-"`
 }
 
 var UserPromptSchema = map[string]interface{}{
@@ -34,6 +33,10 @@ var UserPromptSchema = map[string]interface{}{
 					"add_to_group",
 					"remove_from_group",
 					"attach_custom_policy",
+					"attach_inline_policy",
+					"remove_inline_policy",
+					"set_permissions_boundary",
+					"remove_permissions_boundary",
 				},
 			},
 			"error": map[string]interface{}{
@@ -72,6 +75,10 @@ var UserPromptSchema = map[string]interface{}{
 				"type":        []string{"string", "null"},
 				"description": "The name of the policy. If it's too vague, return null. If the user input does not provide any meaningful context, the model must not guess a policy. For Managed policies, use the arn foe example: arn:aws:iam::aws:policy/AdministratorAccess.",
 			},
+			"group": map[string]interface{}{
+				"type":        []string{"string", "null"},
+				"description": "The name of the group a user is added to or removed from, for add_to_group and remove_from_group.",
+			},
 			"confidence": map[string]interface{}{
 				"type":        "integer",
 				"description": "Confidence level from 1 to 10 about the policy name.",
@@ -84,14 +91,13 @@ var UserPromptSchema = map[string]interface{}{
 	},
 }
 
-func UserPrompt(apiKey, prompt string) (GPTResponse, error) {
-	url := "https://api.openai.com/v1/chat/completions"
-	schema := UserPromptSchema
-	payload := map[string]interface{}{
-		"model":       "gpt-4o",
-		"temperature": 0.1,
-		"messages": []map[string]string{
-			{"role": "system", "content": `
+func UserPrompt(provider Provider, prompt string) (GPTResponse, error) {
+	if provider == nil {
+		return GPTResponse{}, errNoProvider
+	}
+
+	content, err := provider.Complete(UserPromptSchema, []map[string]string{
+		{"role": "system", "content": `
 				You are an assistant designed to interpret IAM-related requests and convert them into structured JSON objects.
 	
 				Your task is to:
@@ -104,58 +110,11 @@ func UserPrompt(apiKey, prompt string) (GPTResponse, error) {
 				7. If the identified policy name has low confidence, set confidence < 5."
 				8. If the user input does not provide any meaningful context, the model must not guess a policy.
 			`},
-			{"role": "user", "content": prompt},
-		},
-		"response_format": map[string]interface{}{
-			"type":        "json_schema",
-			"json_schema": schema,
-		},
-	}
-
-	payloadBytes, err := json.Marshal(payload)
-	if err != nil {
-		return GPTResponse{}, fmt.Errorf("failed to marshal payload: %w", err)
-	}
-
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(payloadBytes))
-	if err != nil {
-		return GPTResponse{}, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+apiKey)
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
+		{"role": "user", "content": prompt},
+	})
 	if err != nil {
-		return GPTResponse{}, fmt.Errorf("request error: %w", err)
+		return GPTResponse{}, err
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return GPTResponse{}, fmt.Errorf("unexpected status code: %d, body: %s", resp.StatusCode, string(body))
-	}
-
-	var intermediateResponse struct {
-		Choices []struct {
-			Message struct {
-				Content string `json:"content"`
-			} `json:"message"`
-		} `json:"choices"`
-	}
-	bodyBytes, _ := io.ReadAll(resp.Body)
-	err = json.Unmarshal(bodyBytes, &intermediateResponse)
-	if err != nil {
-		return GPTResponse{}, fmt.Errorf("failed to parse intermediate response: %w", err)
-	}
-
-	if len(intermediateResponse.Choices) == 0 || intermediateResponse.Choices[0].Message.Content == "" {
-		return GPTResponse{}, fmt.Errorf("no content found in response")
-	}
-
-	content := intermediateResponse.Choices[0].Message.Content
-	content = strings.TrimSpace(content)
 
 	var gptResponse GPTResponse
 	err = json.Unmarshal([]byte(content), &gptResponse)
@@ -165,45 +124,3 @@ func UserPrompt(apiKey, prompt string) (GPTResponse, error) {
 
 	return gptResponse, nil
 }
-
-func GenerateCLICommand(response GPTResponse) string {
-	var flags []string
-
-	if response.Principal != nil {
-		if val, ok := response.Principal["type"]; ok && val != "" {
-			flags = append(flags, val)
-			if val == "users" {
-				flags = append(flags, "--user")
-			} else if val == "groups" {
-				flags = append(flags, "--group")
-			} else if val == "roles" {
-				flags = append(flags, "--role")
-			}
-		}
-		if val, ok := response.Principal["name"]; ok && val != "" {
-			flags = append(flags, val)
-		}
-	}
-
-	if response.Action != "" {
-		flags = append(flags, fmt.Sprintf("--operation %s", response.Action))
-	}
-
-	if response.Policy != "" {
-		flags = append(flags, fmt.Sprintf("--policy %s", response.Policy))
-	}
-
-	if response.RequestedResource != "" {
-		flags = append(flags, fmt.Sprintf("--resource %s", response.RequestedResource))
-	}
-
-	if response.RequestedResourceType != "" {
-		flags = append(flags, fmt.Sprintf("--service %s", response.RequestedResourceType))
-	}
-
-	if len(flags) == 0 {
-		return "No valid flags generated from GPT response."
-	}
-
-	return fmt.Sprintf("aws %s", strings.Join(flags, " "))
-}
@@ -0,0 +1,122 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Permify/targe/internal/aws"
+	"github.com/Permify/targe/internal/config"
+)
+
+// FileName is the name of the audit log inside the config folder.
+var FileName = "audit.json"
+
+// Outcomes of an audited operation.
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+)
+
+// Entry is a single record of the audit log.
+type Entry struct {
+	Time           time.Time  `json:"time"`
+	Caller         string     `json:"caller"`
+	Account        string     `json:"account"`
+	Flow           string     `json:"flow"`
+	Operation      string     `json:"operation"`
+	Principal      string     `json:"principal"`
+	Group          string     `json:"group,omitempty"`
+	PolicyArn      string     `json:"policy_arn,omitempty"`
+	PolicyDocument string     `json:"policy_document,omitempty"`
+	Calls          []aws.Call `json:"calls,omitempty"`
+	Outcome        string     `json:"outcome"`
+	Error          string     `json:"error,omitempty"`
+}
+
+// Filter selects audit entries. Zero values match everything.
+type Filter struct {
+	Principal string
+	Operation string
+	Since     time.Time
+	Until     time.Time
+}
+
+// Match reports whether the entry is selected by the filter.
+func (f Filter) Match(entry Entry) bool {
+	if f.Principal != "" && f.Principal != entry.Principal {
+		return false
+	}
+	if f.Operation != "" && f.Operation != entry.Operation {
+		return false
+	}
+	if !f.Since.IsZero() && entry.Time.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && !entry.Time.Before(f.Until) {
+		return false
+	}
+	return true
+}
+
+func path() string {
+	return filepath.Join(config.Folder, FileName)
+}
+
+// Record appends the entry to the audit log. Existing entries are never modified.
+func Record(entry Entry) error {
+	if entry.Time.IsZero() {
+		entry.Time = time.Now().UTC()
+	}
+
+	if err := os.MkdirAll(config.Folder, 0o755); err != nil {
+		return fmt.Errorf("failed to create audit directory: %w", err)
+	}
+
+	file, err := os.OpenFile(path(), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer file.Close()
+
+	if err := json.NewEncoder(file).Encode(entry); err != nil {
+		return fmt.Errorf("failed to write audit entry: %w", err)
+	}
+
+	return nil
+}
+
+// List reads the audit entries selected by the filter, oldest first.
+func List(filter Filter) ([]Entry, error) {
+	file, err := os.Open(path())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer file.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("failed to parse audit entry: %w", err)
+		}
+
+		if filter.Match(entry) {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries, scanner.Err()
+}
@@ -0,0 +1,123 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/organizations"
+	orgtypes "github.com/aws/aws-sdk-go-v2/service/organizations/types"
+)
+
+// AllAccounts selects every active account of the organization.
+const AllAccounts = "all"
+
+// DefaultAccountRole is the role AWS Organizations creates in member accounts.
+const DefaultAccountRole = "OrganizationAccountAccessRole"
+
+// Account is an account a flow is applied to.
+type Account struct {
+	Id   string
+	Name string
+	// RoleArn is the cross-account role assumed in the account, empty for the caller's own account.
+	RoleArn string
+	Api     *Api
+}
+
+// String returns the account id followed by its name, if known.
+func (a Account) String() string {
+	if a.Name == "" {
+		return a.Id
+	}
+	return a.Id + " (" + a.Name + ")"
+}
+
+// AccountResult is the outcome of a flow in a single account.
+type AccountResult struct {
+	Account Account
+	Err     error
+}
+
+// Accounts resolves account ids, or AllAccounts, to accounts with an Api that assumes roleName
+// in each of them. The caller's own account is used directly without assuming a role.
+func (op *Api) Accounts(ctx context.Context, ids []string, roleName string, opts ConfigOptions) ([]Account, error) {
+	callerArn, callerAccount, err := op.CallerIdentity(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve the caller identity: %w", err)
+	}
+
+	names := map[string]string{}
+	if len(ids) == 1 && strings.EqualFold(ids[0], AllAccounts) {
+		ids = nil
+		p := organizations.NewListAccountsPaginator(organizations.NewFromConfig(op.config), &organizations.ListAccountsInput{})
+		for p.HasMorePages() {
+			output, err := p.NextPage(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list organization accounts: %w", err)
+			}
+			for _, account := range output.Accounts {
+				if account.Status != orgtypes.AccountStatusActive {
+					continue
+				}
+				ids = append(ids, aws.ToString(account.Id))
+				names[aws.ToString(account.Id)] = aws.ToString(account.Name)
+			}
+		}
+	}
+
+	if roleName == "" {
+		roleName = DefaultAccountRole
+	}
+
+	partition := ArnPartition(callerArn)
+	if partition == "" {
+		partition = "aws"
+	}
+
+	var accounts []Account
+	seen := map[string]bool{}
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		account := Account{Id: id, Name: names[id]}
+		if id == callerAccount {
+			account.Api = op
+		} else {
+			account.RoleArn = fmt.Sprintf("arn:%s:iam::%s:role/%s", partition, id, roleName)
+			account.Api = NewApi(AssumeRole(op.config, account.RoleArn, opts.ExternalId, opts.SessionName))
+		}
+		accounts = append(accounts, account)
+	}
+
+	if len(accounts) == 0 {
+		return nil, fmt.Errorf("no accounts selected")
+	}
+
+	return accounts, nil
+}
+
+// ArnPartition returns the partition of the ARN, "aws", "aws-cn" or "aws-us-gov", or an empty string
+// when it is not an ARN.
+func ArnPartition(arn string) string {
+	parts := strings.SplitN(arn, ":", 3)
+	if len(parts) != 3 || parts[0] != "arn" {
+		return ""
+	}
+	return parts[1]
+}
+
+// RebaseArn moves an ARN that belongs to a specific account to the given account. AWS managed
+// ARNs and ARNs without an account are returned unchanged, as is every ARN when account is empty.
+func RebaseArn(arn, account string) string {
+	parts := strings.SplitN(arn, ":", 6)
+	if account == "" || len(parts) != 6 || parts[4] == "" || parts[4] == "aws" {
+		return arn
+	}
+	parts[4] = account
+	return strings.Join(parts, ":")
+}
@@ -0,0 +1,43 @@
+package aws
+
+import "testing"
+
+func TestRebaseArn(t *testing.T) {
+	tests := []struct {
+		name    string
+		arn     string
+		account string
+		want    string
+	}{
+		{name: "customer managed policy", arn: "arn:aws:iam::111111111111:policy/reports", account: "222222222222", want: "arn:aws:iam::222222222222:policy/reports"},
+		{name: "policy with a path", arn: "arn:aws:iam::111111111111:policy/team/reports", account: "222222222222", want: "arn:aws:iam::222222222222:policy/team/reports"},
+		{name: "other partition", arn: "arn:aws-cn:iam::111111111111:role/deploy", account: "222222222222", want: "arn:aws-cn:iam::222222222222:role/deploy"},
+		{name: "AWS managed policy", arn: "arn:aws:iam::aws:policy/ReadOnlyAccess", account: "222222222222", want: "arn:aws:iam::aws:policy/ReadOnlyAccess"},
+		{name: "ARN without an account", arn: "arn:aws:s3:::reports/*", account: "222222222222", want: "arn:aws:s3:::reports/*"},
+		{name: "current account", arn: "arn:aws:iam::111111111111:policy/reports", want: "arn:aws:iam::111111111111:policy/reports"},
+		{name: "not an ARN", arn: "reports", account: "222222222222", want: "reports"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RebaseArn(tt.arn, tt.account); got != tt.want {
+				t.Errorf("RebaseArn(%s, %s) = %s, want %s", tt.arn, tt.account, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestArnPartition(t *testing.T) {
+	tests := map[string]string{
+		"arn:aws:iam::111111111111:user/omer":             "aws",
+		"arn:aws-us-gov:sts::111111111111:assumed-role/a": "aws-us-gov",
+		"arn:aws-cn:s3:::reports":                         "aws-cn",
+		"reports":                                         "",
+	}
+
+	for arn, want := range tests {
+		if got := ArnPartition(arn); got != want {
+			t.Errorf("ArnPartition(%s) = %q, want %q", arn, got, want)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package aws
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+)
+
+// GetUserPermissionsBoundary returns the ARN of the permissions boundary of the user, or an
+// empty string if it has none. ListUsers does not return boundaries, so the user is read with GetUser.
+func (op *Api) GetUserPermissionsBoundary(ctx context.Context, username string) (string, error) {
+	output, err := op.client.GetUser(ctx, &iam.GetUserInput{
+		UserName: aws.String(username),
+	})
+	if err != nil {
+		return "", err
+	}
+	if output.User.PermissionsBoundary == nil {
+		return "", nil
+	}
+	return aws.ToString(output.User.PermissionsBoundary.PermissionsBoundaryArn), nil
+}
+
+// GetRolePermissionsBoundary returns the ARN of the permissions boundary of the role, or an
+// empty string if it has none.
+func (op *Api) GetRolePermissionsBoundary(ctx context.Context, rolename string) (string, error) {
+	output, err := op.client.GetRole(ctx, &iam.GetRoleInput{
+		RoleName: aws.String(rolename),
+	})
+	if err != nil {
+		return "", err
+	}
+	if output.Role.PermissionsBoundary == nil {
+		return "", nil
+	}
+	return aws.ToString(output.Role.PermissionsBoundary.PermissionsBoundaryArn), nil
+}
+
+func (op *Api) PutUserPermissionsBoundary(ctx context.Context, username, boundaryArn string) error {
+	_, err := op.client.PutUserPermissionsBoundary(ctx, &iam.PutUserPermissionsBoundaryInput{
+		UserName:            aws.String(username),
+		PermissionsBoundary: aws.String(boundaryArn),
+	})
+	return err
+}
+
+func (op *Api) DeleteUserPermissionsBoundary(ctx context.Context, username string) error {
+	_, err := op.client.DeleteUserPermissionsBoundary(ctx, &iam.DeleteUserPermissionsBoundaryInput{
+		UserName: aws.String(username),
+	})
+	return err
+}
+
+func (op *Api) PutRolePermissionsBoundary(ctx context.Context, rolename, boundaryArn string) error {
+	_, err := op.client.PutRolePermissionsBoundary(ctx, &iam.PutRolePermissionsBoundaryInput{
+		RoleName:            aws.String(rolename),
+		PermissionsBoundary: aws.String(boundaryArn),
+	})
+	return err
+}
+
+func (op *Api) DeleteRolePermissionsBoundary(ctx context.Context, rolename string) error {
+	_, err := op.client.DeleteRolePermissionsBoundary(ctx, &iam.DeleteRolePermissionsBoundaryInput{
+		RoleName: aws.String(rolename),
+	})
+	return err
+}
+
+// GetDefaultPolicyDocument returns the decoded document of the default version of a managed policy.
+func (op *Api) GetDefaultPolicyDocument(ctx context.Context, policyArn string) (string, error) {
+	policy, err := op.GetPolicy(ctx, policyArn)
+	if err != nil {
+		return "", err
+	}
+	return op.GetPolicyDocument(ctx, policyArn, aws.ToString(policy.DefaultVersionId))
+}
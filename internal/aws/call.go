@@ -0,0 +1,271 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// IAM API actions that targe can perform.
+const (
+	CreatePolicyAction        = "CreatePolicy"
+	DeletePolicyAction        = "DeletePolicy"
+	DeletePolicyVersionAction = "DeletePolicyVersion"
+	CreatePolicyVersionAction = "CreatePolicyVersion"
+	SetDefaultVersionAction   = "SetDefaultPolicyVersion"
+	AttachUserPolicyAction    = "AttachUserPolicy"
+	DetachUserPolicyAction    = "DetachUserPolicy"
+	AttachGroupPolicyAction   = "AttachGroupPolicy"
+	DetachGroupPolicyAction   = "DetachGroupPolicy"
+	AttachRolePolicyAction    = "AttachRolePolicy"
+	DetachRolePolicyAction    = "DetachRolePolicy"
+	AddUserToGroupAction      = "AddUserToGroup"
+	RemoveUserFromGroupAction = "RemoveUserFromGroup"
+	PutUserPolicyAction       = "PutUserPolicy"
+	DeleteUserPolicyAction    = "DeleteUserPolicy"
+	PutGroupPolicyAction      = "PutGroupPolicy"
+	DeleteGroupPolicyAction   = "DeleteGroupPolicy"
+	PutRolePolicyAction       = "PutRolePolicy"
+	DeleteRolePolicyAction    = "DeleteRolePolicy"
+	UpdateAssumeRoleAction    = "UpdateAssumeRolePolicy"
+	CreateUserAction          = "CreateUser"
+	DeleteUserAction          = "DeleteUser"
+	CreateGroupAction         = "CreateGroup"
+	DeleteGroupAction         = "DeleteGroup"
+	CreateRoleAction          = "CreateRole"
+	DeleteRoleAction          = "DeleteRole"
+	PutUserBoundaryAction     = "PutUserPermissionsBoundary"
+	DeleteUserBoundaryAction  = "DeleteUserPermissionsBoundary"
+	PutRoleBoundaryAction     = "PutRolePermissionsBoundary"
+	DeleteRoleBoundaryAction  = "DeleteRolePermissionsBoundary"
+)
+
+// CreatedPolicyArn is the placeholder used in a plan for the ARN of a policy that has not been created yet.
+const CreatedPolicyArn = "<arn of the created policy>"
+
+// Param is a single named parameter of an IAM API call.
+type Param struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Call describes a single IAM API call and its parameters.
+type Call struct {
+	Action string  `json:"action"`
+	Params []Param `json:"params"`
+}
+
+// NewCall creates a call from alternating parameter names and values.
+func NewCall(action string, params ...string) Call {
+	call := Call{Action: action}
+	for i := 0; i+1 < len(params); i += 2 {
+		call.Params = append(call.Params, Param{Name: params[i], Value: params[i+1]})
+	}
+	return call
+}
+
+// Param returns the value of the named parameter, or an empty string if it is not set.
+func (c Call) Param(name string) string {
+	for _, p := range c.Params {
+		if p.Name == name {
+			return p.Value
+		}
+	}
+	return ""
+}
+
+// String renders the call in a human readable form. Policy documents are printed in full.
+func (c Call) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "iam:%s", c.Action)
+	for _, p := range c.Params {
+		if strings.Contains(p.Value, "\n") {
+			fmt.Fprintf(&b, "\n  %s:\n    %s", p.Name, strings.ReplaceAll(p.Value, "\n", "\n    "))
+			continue
+		}
+		fmt.Fprintf(&b, "\n  %s: %s", p.Name, p.Value)
+	}
+	return b.String()
+}
+
+// FormatPlan renders a list of calls as a numbered plan.
+func FormatPlan(calls []Call) string {
+	var lines []string
+	for i, call := range calls {
+		lines = append(lines, fmt.Sprintf("%d. %s", i+1, strings.ReplaceAll(call.String(), "\n", "\n   ")))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// AccountPlan is the plan of an operation in a single account. Account is empty when the operation
+// runs in the current account only.
+type AccountPlan struct {
+	Account string `json:"account,omitempty"`
+	Calls   []Call `json:"calls"`
+}
+
+// FormatAccountPlans renders the plans as numbered plans, each labeled with its account.
+func FormatAccountPlans(plans []AccountPlan) string {
+	var blocks []string
+	for _, plan := range plans {
+		if plan.Account == "" {
+			blocks = append(blocks, FormatPlan(plan.Calls))
+			continue
+		}
+		blocks = append(blocks, fmt.Sprintf("Account %s:\n%s", plan.Account, FormatPlan(plan.Calls)))
+	}
+	return strings.Join(blocks, "\n\n")
+}
+
+// inverseActions maps each reversible action to the action that reverts it with the same parameters.
+var inverseActions = map[string]string{
+	AttachUserPolicyAction:    DetachUserPolicyAction,
+	DetachUserPolicyAction:    AttachUserPolicyAction,
+	AttachGroupPolicyAction:   DetachGroupPolicyAction,
+	DetachGroupPolicyAction:   AttachGroupPolicyAction,
+	AttachRolePolicyAction:    DetachRolePolicyAction,
+	DetachRolePolicyAction:    AttachRolePolicyAction,
+	AddUserToGroupAction:      RemoveUserFromGroupAction,
+	RemoveUserFromGroupAction: AddUserToGroupAction,
+	PutUserPolicyAction:       DeleteUserPolicyAction,
+	DeleteUserPolicyAction:    PutUserPolicyAction,
+	PutGroupPolicyAction:      DeleteGroupPolicyAction,
+	DeleteGroupPolicyAction:   PutGroupPolicyAction,
+	PutRolePolicyAction:       DeleteRolePolicyAction,
+	DeleteRolePolicyAction:    PutRolePolicyAction,
+	CreateUserAction:          DeleteUserAction,
+	CreateGroupAction:         DeleteGroupAction,
+	CreateRoleAction:          DeleteRoleAction,
+	DeleteUserBoundaryAction:  PutUserBoundaryAction,
+	DeleteRoleBoundaryAction:  PutRoleBoundaryAction,
+}
+
+// Inverse returns the calls that revert the given applied calls, in reverse order.
+// A CreatePolicy call must carry the PolicyArn of the created policy to be reverted, a CreatePolicyVersion
+// call the VersionId it created and the PreviousVersionId that was the default before. A deleted inline
+// policy can only be put back when its call carries the PolicyDocument, a trust policy update when it
+// carries the PreviousPolicyDocument, and a deleted managed policy is created again from the PolicyName and
+// PolicyDocument of its call. A permissions boundary is restored to the PreviousPermissionsBoundary
+// of its call, or deleted when there was none.
+func Inverse(calls []Call) []Call {
+	var inverse []Call
+	for i := len(calls) - 1; i >= 0; i-- {
+		call := calls[i]
+		if call.Action == CreatePolicyAction {
+			inverse = append(inverse, NewCall(DeletePolicyAction, "PolicyArn", call.Param("PolicyArn")))
+			continue
+		}
+		if call.Action == DeletePolicyAction && call.Param("PolicyDocument") != "" {
+			inverse = append(inverse, NewCall(CreatePolicyAction, "PolicyName", call.Param("PolicyName"), "PolicyArn", call.Param("PolicyArn"), "PolicyDocument", call.Param("PolicyDocument")))
+			continue
+		}
+		if call.Action == CreatePolicyVersionAction {
+			inverse = append(inverse,
+				NewCall(SetDefaultVersionAction, "PolicyArn", call.Param("PolicyArn"), "VersionId", call.Param("PreviousVersionId")),
+				NewCall(DeletePolicyVersionAction, "PolicyArn", call.Param("PolicyArn"), "VersionId", call.Param("VersionId")),
+			)
+			continue
+		}
+		if call.Action == UpdateAssumeRoleAction {
+			inverse = append(inverse, NewCall(UpdateAssumeRoleAction, "RoleName", call.Param("RoleName"),
+				"PolicyDocument", call.Param("PreviousPolicyDocument"), "PreviousPolicyDocument", call.Param("PolicyDocument")))
+			continue
+		}
+		if call.Action == PutUserBoundaryAction || call.Action == PutRoleBoundaryAction {
+			inverse = append(inverse, inverseBoundary(call))
+			continue
+		}
+		if action, ok := inverseActions[call.Action]; ok {
+			inverse = append(inverse, Call{Action: action, Params: call.Params})
+		}
+	}
+	return inverse
+}
+
+// inverseBoundary returns the call that restores the permissions boundary a Put call replaced.
+func inverseBoundary(call Call) Call {
+	principal, action := "UserName", DeleteUserBoundaryAction
+	if call.Action == PutRoleBoundaryAction {
+		principal, action = "RoleName", DeleteRoleBoundaryAction
+	}
+
+	previous := call.Param("PreviousPermissionsBoundary")
+	if previous == "" {
+		return NewCall(action, principal, call.Param(principal), "PermissionsBoundary", call.Param("PermissionsBoundary"))
+	}
+	return NewCall(call.Action, principal, call.Param(principal), "PermissionsBoundary", previous, "PreviousPermissionsBoundary", call.Param("PermissionsBoundary"))
+}
+
+// Execute performs a call through the matching Api method.
+// CreatePolicy is only supported with the PolicyArn it is expected to create, as when a deleted policy is
+// created again, because the calls that follow it need the ARN.
+func (op *Api) Execute(ctx context.Context, call Call) error {
+	switch call.Action {
+	case CreatePolicyAction:
+		if call.Param("PolicyArn") == "" {
+			return fmt.Errorf("action '%s' cannot be executed without the PolicyArn it creates", call.Action)
+		}
+		output, err := op.CreatePolicy(ctx, call.Param("PolicyName"), call.Param("PolicyDocument"))
+		if err != nil {
+			return err
+		}
+		if arn := aws.ToString(output.Policy.Arn); arn != call.Param("PolicyArn") {
+			return fmt.Errorf("policy was created as %s instead of %s", arn, call.Param("PolicyArn"))
+		}
+		return nil
+	case DeletePolicyAction:
+		return op.DeletePolicy(ctx, call.Param("PolicyArn"))
+	case DeletePolicyVersionAction:
+		return op.DeletePolicyVersion(ctx, call.Param("PolicyArn"), call.Param("VersionId"))
+	case SetDefaultVersionAction:
+		return op.SetDefaultPolicyVersion(ctx, call.Param("PolicyArn"), call.Param("VersionId"))
+	case AttachUserPolicyAction:
+		return op.AttachPolicyToUser(ctx, call.Param("PolicyArn"), call.Param("UserName"))
+	case DetachUserPolicyAction:
+		return op.DetachPolicyFromUser(ctx, call.Param("PolicyArn"), call.Param("UserName"))
+	case AttachGroupPolicyAction:
+		return op.AttachPolicyToGroup(ctx, call.Param("PolicyArn"), call.Param("GroupName"))
+	case DetachGroupPolicyAction:
+		return op.DetachPolicyFromGroup(ctx, call.Param("PolicyArn"), call.Param("GroupName"))
+	case AttachRolePolicyAction:
+		return op.AttachPolicyToRole(ctx, call.Param("PolicyArn"), call.Param("RoleName"))
+	case DetachRolePolicyAction:
+		return op.DetachPolicyFromRole(ctx, call.Param("PolicyArn"), call.Param("RoleName"))
+	case AddUserToGroupAction:
+		return op.AddUserToGroup(ctx, call.Param("UserName"), call.Param("GroupName"))
+	case RemoveUserFromGroupAction:
+		return op.RemoveUserFromGroup(ctx, call.Param("UserName"), call.Param("GroupName"))
+	case PutUserPolicyAction:
+		return op.PutInlinePolicyToUser(ctx, call.Param("PolicyName"), call.Param("PolicyDocument"), call.Param("UserName"))
+	case DeleteUserPolicyAction:
+		return op.DeleteInlinePolicyFromUser(ctx, call.Param("PolicyName"), call.Param("UserName"))
+	case PutGroupPolicyAction:
+		return op.PutInlinePolicyToGroup(ctx, call.Param("PolicyName"), call.Param("PolicyDocument"), call.Param("GroupName"))
+	case DeleteGroupPolicyAction:
+		return op.DeleteInlinePolicyFromGroup(ctx, call.Param("PolicyName"), call.Param("GroupName"))
+	case PutRolePolicyAction:
+		return op.PutInlinePolicyToRole(ctx, call.Param("PolicyName"), call.Param("PolicyDocument"), call.Param("RoleName"))
+	case DeleteRolePolicyAction:
+		return op.DeleteInlinePolicyFromRole(ctx, call.Param("PolicyName"), call.Param("RoleName"))
+	case DeleteUserAction:
+		return op.DeleteUser(ctx, call.Param("UserName"))
+	case DeleteGroupAction:
+		return op.DeleteGroup(ctx, call.Param("GroupName"))
+	case DeleteRoleAction:
+		return op.DeleteRole(ctx, call.Param("RoleName"))
+	case PutUserBoundaryAction:
+		return op.PutUserPermissionsBoundary(ctx, call.Param("UserName"), call.Param("PermissionsBoundary"))
+	case DeleteUserBoundaryAction:
+		return op.DeleteUserPermissionsBoundary(ctx, call.Param("UserName"))
+	case PutRoleBoundaryAction:
+		return op.PutRolePermissionsBoundary(ctx, call.Param("RoleName"), call.Param("PermissionsBoundary"))
+	case DeleteRoleBoundaryAction:
+		return op.DeleteRolePermissionsBoundary(ctx, call.Param("RoleName"))
+	case UpdateAssumeRoleAction:
+		return op.UpdateAssumeRolePolicy(ctx, call.Param("RoleName"), call.Param("PolicyDocument"))
+	default:
+		return fmt.Errorf("action '%s' cannot be executed", call.Action)
+	}
+}
@@ -0,0 +1,112 @@
+package aws
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInverse(t *testing.T) {
+	const (
+		policyArn   = "arn:aws:iam::123456789012:policy/reports"
+		boundaryArn = "arn:aws:iam::123456789012:policy/boundary"
+		document    = `{"Version":"2012-10-17","Statement":[]}`
+		previousDoc = `{"Version":"2012-10-17","Statement":[{"Effect":"Allow"}]}`
+	)
+
+	tests := []struct {
+		name  string
+		calls []Call
+		want  []Call
+	}{
+		{
+			name:  "attach is detached",
+			calls: []Call{NewCall(AttachUserPolicyAction, "PolicyArn", policyArn, "UserName", "omer")},
+			want:  []Call{NewCall(DetachUserPolicyAction, "PolicyArn", policyArn, "UserName", "omer")},
+		},
+		{
+			name:  "group membership",
+			calls: []Call{NewCall(RemoveUserFromGroupAction, "GroupName", "developers", "UserName", "omer")},
+			want:  []Call{NewCall(AddUserToGroupAction, "GroupName", "developers", "UserName", "omer")},
+		},
+		{
+			name: "calls are reverted in reverse order",
+			calls: []Call{
+				NewCall(CreatePolicyAction, "PolicyName", "reports", "PolicyArn", policyArn),
+				NewCall(AttachRolePolicyAction, "PolicyArn", policyArn, "RoleName", "deploy"),
+			},
+			want: []Call{
+				NewCall(DetachRolePolicyAction, "PolicyArn", policyArn, "RoleName", "deploy"),
+				NewCall(DeletePolicyAction, "PolicyArn", policyArn),
+			},
+		},
+		{
+			name:  "new policy version",
+			calls: []Call{NewCall(CreatePolicyVersionAction, "PolicyArn", policyArn, "SetAsDefault", "true", "VersionId", "v3", "PreviousVersionId", "v2")},
+			want: []Call{
+				NewCall(SetDefaultVersionAction, "PolicyArn", policyArn, "VersionId", "v2"),
+				NewCall(DeletePolicyVersionAction, "PolicyArn", policyArn, "VersionId", "v3"),
+			},
+		},
+		{
+			name:  "trust policy update",
+			calls: []Call{NewCall(UpdateAssumeRoleAction, "RoleName", "deploy", "PolicyDocument", document, "PreviousPolicyDocument", previousDoc)},
+			want:  []Call{NewCall(UpdateAssumeRoleAction, "RoleName", "deploy", "PolicyDocument", previousDoc, "PreviousPolicyDocument", document)},
+		},
+		{
+			name:  "boundary without a previous one",
+			calls: []Call{NewCall(PutUserBoundaryAction, "UserName", "omer", "PermissionsBoundary", boundaryArn, "PreviousPermissionsBoundary", "")},
+			want:  []Call{NewCall(DeleteUserBoundaryAction, "UserName", "omer", "PermissionsBoundary", boundaryArn)},
+		},
+		{
+			name:  "boundary that replaced another",
+			calls: []Call{NewCall(PutRoleBoundaryAction, "RoleName", "deploy", "PermissionsBoundary", boundaryArn, "PreviousPermissionsBoundary", policyArn)},
+			want:  []Call{NewCall(PutRoleBoundaryAction, "RoleName", "deploy", "PermissionsBoundary", policyArn, "PreviousPermissionsBoundary", boundaryArn)},
+		},
+		{
+			name:  "removed boundary",
+			calls: []Call{NewCall(DeleteRoleBoundaryAction, "RoleName", "deploy", "PermissionsBoundary", boundaryArn)},
+			want:  []Call{NewCall(PutRoleBoundaryAction, "RoleName", "deploy", "PermissionsBoundary", boundaryArn)},
+		},
+		{
+			name:  "deleted inline policy",
+			calls: []Call{NewCall(DeleteGroupPolicyAction, "PolicyName", "logs", "PolicyDocument", document, "GroupName", "ops")},
+			want:  []Call{NewCall(PutGroupPolicyAction, "PolicyName", "logs", "PolicyDocument", document, "GroupName", "ops")},
+		},
+		{
+			name:  "deleted policy with its document",
+			calls: []Call{NewCall(DeletePolicyAction, "PolicyArn", policyArn, "PolicyName", "reports", "PolicyDocument", document)},
+			want:  []Call{NewCall(CreatePolicyAction, "PolicyName", "reports", "PolicyArn", policyArn, "PolicyDocument", document)},
+		},
+		{
+			name:  "deleted policy without its document",
+			calls: []Call{NewCall(DeletePolicyAction, "PolicyArn", policyArn)},
+		},
+		{
+			name:  "deleted policy version",
+			calls: []Call{NewCall(DeletePolicyVersionAction, "PolicyArn", policyArn, "VersionId", "v1")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Inverse(tt.calls); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Inverse() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatAccountPlans(t *testing.T) {
+	call := NewCall(DetachUserPolicyAction, "PolicyArn", "arn:aws:iam::aws:policy/ReadOnlyAccess", "UserName", "omer")
+
+	got := FormatAccountPlans([]AccountPlan{{Calls: []Call{call}}})
+	if want := FormatPlan([]Call{call}); got != want {
+		t.Errorf("FormatAccountPlans() of the current account = %q, want %q", got, want)
+	}
+
+	got = FormatAccountPlans([]AccountPlan{{Account: "111111111111", Calls: []Call{call}}, {Account: "222222222222", Calls: []Call{call}}})
+	want := "Account 111111111111:\n" + FormatPlan([]Call{call}) + "\n\nAccount 222222222222:\n" + FormatPlan([]Call{call})
+	if got != want {
+		t.Errorf("FormatAccountPlans() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,68 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// DefaultSessionName is the role session name used when none is given.
+const DefaultSessionName = "targe"
+
+// ConfigOptions selects the credentials and region the AWS APIs are called with.
+type ConfigOptions struct {
+	// Profile is the shared config profile, the default chain is used when empty.
+	Profile string
+	// Region overrides the region of the profile or environment.
+	Region string
+	// AssumeRoleArn is a role assumed on top of the loaded credentials.
+	AssumeRoleArn string
+	// ExternalId is passed to AssumeRole when the trust policy requires one.
+	ExternalId string
+	// SessionName is the role session name, DefaultSessionName when empty.
+	SessionName string
+}
+
+// LoadConfig loads the AWS configuration for the given options.
+func LoadConfig(ctx context.Context, opts ConfigOptions) (aws.Config, error) {
+	var optFns []func(*awsconfig.LoadOptions) error
+	if opts.Profile != "" {
+		optFns = append(optFns, awsconfig.WithSharedConfigProfile(opts.Profile))
+	}
+	if opts.Region != "" {
+		optFns = append(optFns, awsconfig.WithRegion(opts.Region))
+	}
+
+	cfg, err := awsconfig.LoadDefaultConfig(ctx, optFns...)
+	if err != nil {
+		return aws.Config{}, fmt.Errorf("failed to load AWS configuration: %w", err)
+	}
+
+	if opts.AssumeRoleArn != "" {
+		cfg = AssumeRole(cfg, opts.AssumeRoleArn, opts.ExternalId, opts.SessionName)
+	}
+
+	return cfg, nil
+}
+
+// AssumeRole returns a copy of the configuration whose credentials come from assuming the role.
+func AssumeRole(cfg aws.Config, roleArn, externalId, sessionName string) aws.Config {
+	if sessionName == "" {
+		sessionName = DefaultSessionName
+	}
+
+	provider := stscreds.NewAssumeRoleProvider(sts.NewFromConfig(cfg), roleArn, func(o *stscreds.AssumeRoleOptions) {
+		o.RoleSessionName = sessionName
+		if externalId != "" {
+			o.ExternalID = aws.String(externalId)
+		}
+	})
+
+	assumed := cfg.Copy()
+	assumed.Credentials = aws.NewCredentialsCache(provider)
+	return assumed
+}
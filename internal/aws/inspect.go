@@ -0,0 +1,177 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+)
+
+// Kinds of policy sources of a principal.
+const (
+	SourceAttached = "attached"
+	SourceInline   = "inline"
+	SourceBoundary = "boundary"
+)
+
+// PolicySource describes where a policy of a principal comes from.
+type PolicySource struct {
+	Kind   string `json:"kind"`
+	Policy string `json:"policy"`
+	// Group is the group the policy is inherited from, empty for policies of the principal itself.
+	Group string `json:"group,omitempty"`
+}
+
+// String renders the source, e.g. "group admins: attached AdministratorAccess".
+func (s PolicySource) String() string {
+	source := s.Kind + " " + s.Policy
+	if s.Group != "" {
+		source = "group " + s.Group + ": " + source
+	}
+	return source
+}
+
+// SourcedPolicy is a decoded policy document together with its source.
+type SourcedPolicy struct {
+	Source   PolicySource
+	Document string
+}
+
+// UserPolicies returns the documents of every policy that applies to the user: attached and inline
+// policies, the policies of each group the user belongs to, and the permissions boundary.
+func (op *Api) UserPolicies(ctx context.Context, username string) ([]SourcedPolicy, error) {
+	attached, err := op.attachedUserPolicies(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	policies, err := op.attachedDocuments(ctx, attached, "")
+	if err != nil {
+		return nil, err
+	}
+
+	inline, err := op.ListUserInlinePolicies(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range inline {
+		document, err := op.GetUserInlinePolicy(ctx, name, username)
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, SourcedPolicy{Source: PolicySource{Kind: SourceInline, Policy: name}, Document: document})
+	}
+
+	groups, err := op.ListGroupsForUser(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	for _, group := range groups {
+		inherited, err := op.groupPolicies(ctx, group, group)
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, inherited...)
+	}
+
+	boundary, err := op.GetUserPermissionsBoundary(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	return op.withBoundary(ctx, policies, boundary)
+}
+
+// GroupPolicies returns the documents of the attached and inline policies of the group.
+func (op *Api) GroupPolicies(ctx context.Context, groupname string) ([]SourcedPolicy, error) {
+	return op.groupPolicies(ctx, groupname, "")
+}
+
+// RolePolicies returns the documents of the attached and inline policies of the role and its
+// permissions boundary.
+func (op *Api) RolePolicies(ctx context.Context, rolename string) ([]SourcedPolicy, error) {
+	attached, err := op.attachedRolePolicies(ctx, rolename)
+	if err != nil {
+		return nil, err
+	}
+	policies, err := op.attachedDocuments(ctx, attached, "")
+	if err != nil {
+		return nil, err
+	}
+
+	inline, err := op.ListRoleInlinePolicies(ctx, rolename)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range inline {
+		document, err := op.GetRoleInlinePolicy(ctx, name, rolename)
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, SourcedPolicy{Source: PolicySource{Kind: SourceInline, Policy: name}, Document: document})
+	}
+
+	boundary, err := op.GetRolePermissionsBoundary(ctx, rolename)
+	if err != nil {
+		return nil, err
+	}
+	return op.withBoundary(ctx, policies, boundary)
+}
+
+// groupPolicies returns the documents of the policies of a group, marked as inherited from
+// inheritedFrom unless it is empty.
+func (op *Api) groupPolicies(ctx context.Context, groupname, inheritedFrom string) ([]SourcedPolicy, error) {
+	attached, err := op.attachedGroupPolicies(ctx, groupname)
+	if err != nil {
+		return nil, err
+	}
+	policies, err := op.attachedDocuments(ctx, attached, inheritedFrom)
+	if err != nil {
+		return nil, err
+	}
+
+	inline, err := op.ListGroupInlinePolicies(ctx, groupname)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range inline {
+		document, err := op.GetGroupInlinePolicy(ctx, name, groupname)
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, SourcedPolicy{Source: PolicySource{Kind: SourceInline, Policy: name, Group: inheritedFrom}, Document: document})
+	}
+
+	return policies, nil
+}
+
+// attachedDocuments reads the default version document of each attached policy.
+func (op *Api) attachedDocuments(ctx context.Context, attached []types.AttachedPolicy, group string) ([]SourcedPolicy, error) {
+	var policies []SourcedPolicy
+	for _, policy := range attached {
+		document, err := op.GetDefaultPolicyDocument(ctx, aws.ToString(policy.PolicyArn))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", aws.ToString(policy.PolicyName), err)
+		}
+		policies = append(policies, SourcedPolicy{
+			Source:   PolicySource{Kind: SourceAttached, Policy: aws.ToString(policy.PolicyName), Group: group},
+			Document: document,
+		})
+	}
+	return policies, nil
+}
+
+// withBoundary appends the document of the permissions boundary, if there is one.
+func (op *Api) withBoundary(ctx context.Context, policies []SourcedPolicy, boundaryArn string) ([]SourcedPolicy, error) {
+	if boundaryArn == "" {
+		return policies, nil
+	}
+
+	document, err := op.GetDefaultPolicyDocument(ctx, boundaryArn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the permissions boundary %s: %w", boundaryArn, err)
+	}
+	return append(policies, SourcedPolicy{
+		Source:   PolicySource{Kind: SourceBoundary, Policy: extractResourceName(boundaryArn)},
+		Document: document,
+	}), nil
+}
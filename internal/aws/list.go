@@ -1,211 +1,213 @@
 package aws
 
 import (
-	"bytes"
 	"context"
-	"crypto/sha256"
-	"encoding/hex"
-	"encoding/json"
-	"fmt"
-	"net/http"
 	"regexp"
-	"time"
+	"slices"
 
 	"github.com/aws/aws-sdk-go-v2/service/iam/types"
 
 	"github.com/aws/aws-sdk-go-v2/service/iam"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
-	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
 )
 
-// Resource represents a single AWS resource.
-type Resource struct {
-	Name string
-	Arn  string
+// extractResourceName extracts the last component of an ARN.
+func extractResourceName(arn string) string {
+	re := regexp.MustCompile(`([^/:]+)$`)
+	matches := re.FindStringSubmatch(arn)
+	if len(matches) > 1 {
+		return matches[1]
+	}
+	return arn // Fallback to the full ARN if no match
 }
 
-// ListResources fetches resources of a given type from the AWS Resource Explorer API.
-func (op *Api) ListResources(resourceType string) ([]Resource, error) {
-	const service = "resource-explorer"
-	const endpoint = "https://resource-explorer.%s.amazonaws.com/resources-list"
+// Pager fetches a listing one page at a time.
+type Pager[T any] struct {
+	hasMorePages func() bool
+	nextPage     func(ctx context.Context) ([]T, error)
+}
 
-	// Validate inputs
-	if resourceType == "" {
-		return nil, fmt.Errorf("resourceType cannot be empty")
-	}
+// HasMorePages reports whether there are more pages to fetch.
+func (p *Pager[T]) HasMorePages() bool {
+	return p.hasMorePages()
+}
 
-	// Format the endpoint with the region
-	url := fmt.Sprintf(endpoint, op.config.Region)
+// NextPage fetches the next page of items.
+func (p *Pager[T]) NextPage(ctx context.Context) ([]T, error) {
+	return p.nextPage(ctx)
+}
 
-	// Retrieve actual credentials
-	creds, err := op.config.Credentials.Retrieve(context.TODO())
-	if err != nil {
-		return nil, fmt.Errorf("failed to retrieve AWS credentials: %w", err)
+// All fetches every remaining page.
+func (p *Pager[T]) All(ctx context.Context) ([]T, error) {
+	var items []T
+	for p.HasMorePages() {
+		page, err := p.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, page...)
 	}
+	return items, nil
+}
 
-	// Prepare the request payload
-	payload := fmt.Sprintf(`{"ResourceType":"%s"}`, resourceType)
-
-	// Hash the payload
-	hash := sha256.Sum256([]byte(payload))
-	payloadHash := hex.EncodeToString(hash[:])
+// paginator is implemented by the IAM SDK paginators.
+type paginator[O any] interface {
+	HasMorePages() bool
+	NextPage(ctx context.Context, optFns ...func(*iam.Options)) (O, error)
+}
 
-	// Create the HTTP request
-	req, err := http.NewRequest("POST", url, bytes.NewReader([]byte(payload)))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+func newPager[T, O any](p paginator[O], items func(O) []T) *Pager[T] {
+	return &Pager[T]{
+		hasMorePages: p.HasMorePages,
+		nextPage: func(ctx context.Context) ([]T, error) {
+			output, err := p.NextPage(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return items(output), nil
+		},
 	}
+}
 
-	// Add required headers
-	addHeaders(req, payloadHash)
+// UserPager lists all IAM users page by page.
+func (op *Api) UserPager() *Pager[types.User] {
+	return newPager(iam.NewListUsersPaginator(op.client, &iam.ListUsersInput{}), func(o *iam.ListUsersOutput) []types.User {
+		return o.Users
+	})
+}
 
-	// Sign the request using AWS Signature Version 4
-	err = signRequest(req, payloadHash, creds, service, op.config.Region)
-	if err != nil {
-		return nil, fmt.Errorf("failed to sign request: %w", err)
-	}
+// GroupPager lists all IAM groups page by page.
+func (op *Api) GroupPager() *Pager[types.Group] {
+	return newPager(iam.NewListGroupsPaginator(op.client, &iam.ListGroupsInput{}), func(o *iam.ListGroupsOutput) []types.Group {
+		return o.Groups
+	})
+}
 
-	// Send the HTTP request
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send HTTP request: %w", err)
-	}
-	defer resp.Body.Close()
+// RolePager lists all IAM roles page by page.
+func (op *Api) RolePager() *Pager[types.Role] {
+	return newPager(iam.NewListRolesPaginator(op.client, &iam.ListRolesInput{}), func(o *iam.ListRolesOutput) []types.Role {
+		return o.Roles
+	})
+}
 
-	// Check for a successful response
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API call failed with status %s", resp.Status)
+// PolicyPager lists all customer managed policies page by page.
+func (op *Api) PolicyPager() *Pager[types.Policy] {
+	input := &iam.ListPoliciesInput{
+		Scope: types.PolicyScopeTypeLocal,
 	}
+	return newPager(iam.NewListPoliciesPaginator(op.client, input), func(o *iam.ListPoliciesOutput) []types.Policy {
+		return o.Policies
+	})
+}
 
-	// Parse the response body
-	var listResourcesResponse map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&listResourcesResponse); err != nil {
-		return nil, fmt.Errorf("failed to decode response body: %w", err)
+func (op *Api) ListGroupsForUser(ctx context.Context, username string) ([]string, error) {
+	input := &iam.ListGroupsForUserInput{
+		UserName: aws.String(username),
 	}
 
-	// Extract resources
-	resources, err := extractResources(listResourcesResponse)
+	groups, err := newPager(iam.NewListGroupsForUserPaginator(op.client, input), func(o *iam.ListGroupsForUserOutput) []string {
+		var names []string
+		for _, group := range o.Groups {
+			names = append(names, aws.ToString(group.GroupName))
+		}
+		return names
+	}).All(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to extract resources: %w", err)
+		return nil, err
 	}
 
-	return resources, nil
+	return groups, nil
 }
 
-// addHeaders adds required headers to the HTTP request.
-func addHeaders(req *http.Request, payloadHash string) {
-	req.Header.Add("accept", "*/*")
-	req.Header.Add("content-type", "application/json")
-	req.Header.Add("x-amz-content-sha256", payloadHash)
-	req.Header.Add("x-amz-date", time.Now().UTC().Format("20060102T150405Z"))
-	req.Header.Add("user-agent", "Custom-Client/1.0")
+func (op *Api) ListUsers(ctx context.Context) ([]types.User, error) {
+	return op.UserPager().All(ctx)
 }
 
-// signRequest signs the HTTP request using AWS Signature Version 4 and the loaded configuration.
-func signRequest(req *http.Request, payloadHash string, creds aws.Credentials, service, region string) error {
-	signer := v4.NewSigner()
-	return signer.SignHTTP(context.TODO(), creds, req, payloadHash, service, region, time.Now())
+func (op *Api) ListPolicies(ctx context.Context) ([]types.Policy, error) {
+	return op.PolicyPager().All(ctx)
 }
 
-// extractResources extracts resources from the API response.
-func extractResources(response map[string]interface{}) ([]Resource, error) {
-	resourceArns, ok := response["ResourceArns"].([]interface{})
-	if !ok {
-		return nil, fmt.Errorf("invalid response format: 'ResourceArns' missing or invalid")
-	}
-
-	var resources []Resource
-	for _, arnInterface := range resourceArns {
-		arn, ok := arnInterface.(string)
-		if !ok {
-			return nil, fmt.Errorf("invalid ARN format in response")
-		}
+func (op *Api) ListGroups(ctx context.Context) ([]types.Group, error) {
+	return op.GroupPager().All(ctx)
+}
 
-		resources = append(resources, Resource{
-			Name: extractResourceName(arn),
-			Arn:  arn,
-		})
-	}
-	return resources, nil
+func (op *Api) ListRoles(ctx context.Context) ([]types.Role, error) {
+	return op.RolePager().All(ctx)
 }
 
-// extractResourceName extracts the last component of an ARN.
-func extractResourceName(arn string) string {
-	re := regexp.MustCompile(`([^/:]+)$`)
-	matches := re.FindStringSubmatch(arn)
-	if len(matches) > 1 {
-		return matches[1]
+// attachedPolicyNames extracts the policy names of an attached policies page.
+func attachedPolicyNames(policies []types.AttachedPolicy) []string {
+	var names []string
+	for _, p := range policies {
+		names = append(names, aws.ToString(p.PolicyName))
 	}
-	return arn // Fallback to the full ARN if no match
+	return names
 }
 
-func (op *Api) ListGroupsForUser(ctx context.Context, username string) ([]string, error) {
-	var groups []string
-
-	input := &iam.ListGroupsForUserInput{
-		UserName: aws.String(username),
+// attached reports whether the policy is one of the attached policies.
+func attached(policies []types.AttachedPolicy, policyArn string) bool {
+	for _, p := range policies {
+		if aws.ToString(p.PolicyArn) == policyArn {
+			return true
+		}
 	}
+	return false
+}
 
-	resp, err := op.client.ListGroupsForUser(ctx, input)
+// IsPolicyAttachedToUser reports whether the managed policy is attached to the user.
+func (op *Api) IsPolicyAttachedToUser(ctx context.Context, policyArn, username string) (bool, error) {
+	policies, err := op.attachedUserPolicies(ctx, username)
 	if err != nil {
-		return nil, err
-	}
-
-	for _, group := range resp.Groups {
-		groups = append(groups, *group.GroupName)
+		return false, err
 	}
-
-	return groups, nil
-}
-
-func (op *Api) ListUsers(ctx context.Context) (*iam.ListUsersOutput, error) {
-	input := &iam.ListUsersInput{}
-	return op.client.ListUsers(ctx, input)
+	return attached(policies, policyArn), nil
 }
 
-func (op *Api) ListPolicies(ctx context.Context) (*iam.ListPoliciesOutput, error) {
-	input := &iam.ListPoliciesInput{
-		Scope:    types.PolicyScopeTypeLocal,
-		MaxItems: aws.Int32(500),
-	}
-
-	resp, err := op.client.ListPolicies(ctx, input)
+// IsPolicyAttachedToGroup reports whether the managed policy is attached to the group.
+func (op *Api) IsPolicyAttachedToGroup(ctx context.Context, policyArn, groupname string) (bool, error) {
+	policies, err := op.attachedGroupPolicies(ctx, groupname)
 	if err != nil {
-		return nil, err
+		return false, err
 	}
-
-	return resp, nil
+	return attached(policies, policyArn), nil
 }
 
-func (op *Api) ListGroups(ctx context.Context) (*iam.ListGroupsOutput, error) {
-	input := &iam.ListGroupsInput{}
-	return op.client.ListGroups(ctx, input)
+// IsPolicyAttachedToRole reports whether the managed policy is attached to the role.
+func (op *Api) IsPolicyAttachedToRole(ctx context.Context, policyArn, rolename string) (bool, error) {
+	policies, err := op.attachedRolePolicies(ctx, rolename)
+	if err != nil {
+		return false, err
+	}
+	return attached(policies, policyArn), nil
 }
 
-func (op *Api) ListRoles(ctx context.Context) (*iam.ListRolesOutput, error) {
-	input := &iam.ListRolesInput{}
-	return op.client.ListRoles(ctx, input)
+// IsUserInGroup reports whether the user is a member of the group.
+func (op *Api) IsUserInGroup(ctx context.Context, username, groupname string) (bool, error) {
+	groups, err := op.ListGroupsForUser(ctx, username)
+	if err != nil {
+		return false, err
+	}
+	return slices.Contains(groups, groupname), nil
 }
 
 func (op *Api) ListAttachedUserPolicies(ctx context.Context, username string) ([]string, error) {
-	var names []string
-
-	input := &iam.ListAttachedUserPoliciesInput{
-		UserName: aws.String(username),
-	}
-
-	resp, err := op.client.ListAttachedUserPolicies(ctx, input)
+	policies, err := op.attachedUserPolicies(ctx, username)
 	if err != nil {
 		return nil, err
 	}
+	return attachedPolicyNames(policies), nil
+}
 
-	for _, p := range resp.AttachedPolicies {
-		names = append(names, *p.PolicyName)
+// attachedUserPolicies lists the managed policies attached to the user with their ARNs.
+func (op *Api) attachedUserPolicies(ctx context.Context, username string) ([]types.AttachedPolicy, error) {
+	input := &iam.ListAttachedUserPoliciesInput{
+		UserName: aws.String(username),
 	}
 
-	return names, nil
+	return newPager(iam.NewListAttachedUserPoliciesPaginator(op.client, input), func(o *iam.ListAttachedUserPoliciesOutput) []types.AttachedPolicy {
+		return o.AttachedPolicies
+	}).All(ctx)
 }
 
 func (op *Api) ListUserInlinePolicies(ctx context.Context, username string) ([]string, error) {
@@ -213,12 +215,9 @@ func (op *Api) ListUserInlinePolicies(ctx context.Context, username string) ([]s
 		UserName: aws.String(username),
 	}
 
-	resp, err := op.client.ListUserPolicies(ctx, input)
-	if err != nil {
-		return nil, err
-	}
-
-	return resp.PolicyNames, nil
+	return newPager(iam.NewListUserPoliciesPaginator(op.client, input), func(o *iam.ListUserPoliciesOutput) []string {
+		return o.PolicyNames
+	}).All(ctx)
 }
 
 func (op *Api) ListGroupInlinePolicies(ctx context.Context, groupname string) ([]string, error) {
@@ -226,31 +225,28 @@ func (op *Api) ListGroupInlinePolicies(ctx context.Context, groupname string) ([
 		GroupName: aws.String(groupname),
 	}
 
-	resp, err := op.client.ListGroupPolicies(ctx, input)
-	if err != nil {
-		return nil, err
-	}
-
-	return resp.PolicyNames, nil
+	return newPager(iam.NewListGroupPoliciesPaginator(op.client, input), func(o *iam.ListGroupPoliciesOutput) []string {
+		return o.PolicyNames
+	}).All(ctx)
 }
 
 func (op *Api) ListAttachedGroupPolicies(ctx context.Context, groupname string) ([]string, error) {
-	var names []string
-
-	input := &iam.ListAttachedGroupPoliciesInput{
-		GroupName: aws.String(groupname),
-	}
-
-	resp, err := op.client.ListAttachedGroupPolicies(ctx, input)
+	policies, err := op.attachedGroupPolicies(ctx, groupname)
 	if err != nil {
 		return nil, err
 	}
+	return attachedPolicyNames(policies), nil
+}
 
-	for _, p := range resp.AttachedPolicies {
-		names = append(names, *p.PolicyName)
+// attachedGroupPolicies lists the managed policies attached to the group with their ARNs.
+func (op *Api) attachedGroupPolicies(ctx context.Context, groupname string) ([]types.AttachedPolicy, error) {
+	input := &iam.ListAttachedGroupPoliciesInput{
+		GroupName: aws.String(groupname),
 	}
 
-	return names, nil
+	return newPager(iam.NewListAttachedGroupPoliciesPaginator(op.client, input), func(o *iam.ListAttachedGroupPoliciesOutput) []types.AttachedPolicy {
+		return o.AttachedPolicies
+	}).All(ctx)
 }
 
 func (op *Api) ListRoleInlinePolicies(ctx context.Context, rolename string) ([]string, error) {
@@ -258,25 +254,26 @@ func (op *Api) ListRoleInlinePolicies(ctx context.Context, rolename string) ([]s
 		RoleName: aws.String(rolename),
 	}
 
-	resp, err := op.client.ListRolePolicies(ctx, input)
+	return newPager(iam.NewListRolePoliciesPaginator(op.client, input), func(o *iam.ListRolePoliciesOutput) []string {
+		return o.PolicyNames
+	}).All(ctx)
+}
+
+func (op *Api) ListAttachedRolePolicies(ctx context.Context, rolename string) ([]string, error) {
+	policies, err := op.attachedRolePolicies(ctx, rolename)
 	if err != nil {
 		return nil, err
 	}
-
-	return resp.PolicyNames, nil
+	return attachedPolicyNames(policies), nil
 }
 
-func (op *Api) ListAttachedRolePolicies(ctx context.Context, rolename string) ([]string, error) {
-	var names []string
+// attachedRolePolicies lists the managed policies attached to the role with their ARNs.
+func (op *Api) attachedRolePolicies(ctx context.Context, rolename string) ([]types.AttachedPolicy, error) {
 	input := &iam.ListAttachedRolePoliciesInput{
 		RoleName: aws.String(rolename),
 	}
-	resp, err := op.client.ListAttachedRolePolicies(ctx, input)
-	if err != nil {
-		return nil, err
-	}
-	for _, p := range resp.AttachedPolicies {
-		names = append(names, *p.PolicyName)
-	}
-	return names, nil
+
+	return newPager(iam.NewListAttachedRolePoliciesPaginator(op.client, input), func(o *iam.ListAttachedRolePoliciesOutput) []types.AttachedPolicy {
+		return o.AttachedPolicies
+	}).All(ctx)
 }
@@ -2,31 +2,87 @@ package aws
 
 import (
 	"context"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/aws/aws-sdk-go-v2/service/resourceexplorer2"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// PolicyDescription is the description set on every policy created by targe.
+const PolicyDescription = "created by targe"
+
+// Tags set on every policy, user and role created by targe.
+const (
+	CreatedByTagKey   = "created-by"
+	CreatedByTagValue = "targe"
+	RequesterTagKey   = "requester"
+	CreatedAtTagKey   = "created-at"
 )
 
 type Api struct {
-	client *iam.Client
-	config aws.Config
+	client    *iam.Client
+	stsClient *sts.Client
+	config    aws.Config
+	identity  *sts.GetCallerIdentityOutput
+
+	explorer      *resourceexplorer2.Client
+	explorerTypes map[string]bool
 }
 
 func NewApi(config aws.Config) *Api {
 	return &Api{
-		client: iam.NewFromConfig(config),
-		config: config,
+		client:    iam.NewFromConfig(config),
+		stsClient: sts.NewFromConfig(config),
+		config:    config,
+	}
+}
+
+// CallerIdentity returns the ARN and account id of the credentials in use. The result is cached.
+func (op *Api) CallerIdentity(ctx context.Context) (arn, account string, err error) {
+	if op.identity == nil {
+		op.identity, err = op.stsClient.GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+		if err != nil {
+			return "", "", err
+		}
 	}
+	return aws.ToString(op.identity.Arn), aws.ToString(op.identity.Account), nil
 }
 
+// CreatePolicy creates a customer managed policy tagged as created by targe, with the
+// requester and the creation time.
 func (op *Api) CreatePolicy(ctx context.Context, name, document string) (*iam.CreatePolicyOutput, error) {
 	return op.client.CreatePolicy(ctx, &iam.CreatePolicyInput{
-		Description:    aws.String("created by targe"),
+		Description:    aws.String(PolicyDescription),
 		PolicyName:     aws.String(name),
 		PolicyDocument: aws.String(document),
+		Tags:           op.targeTags(ctx),
 	})
 }
 
+// targeTags returns the tags that mark an IAM entity as created by targe.
+func (op *Api) targeTags(ctx context.Context) []types.Tag {
+	requester := "unknown"
+	if arn, _, err := op.CallerIdentity(ctx); err == nil {
+		requester = arn
+	}
+
+	return []types.Tag{
+		{Key: aws.String(CreatedByTagKey), Value: aws.String(CreatedByTagValue)},
+		{Key: aws.String(RequesterTagKey), Value: aws.String(requester)},
+		{Key: aws.String(CreatedAtTagKey), Value: aws.String(time.Now().UTC().Format(time.RFC3339))},
+	}
+}
+
+func (op *Api) DeletePolicy(ctx context.Context, policyArn string) error {
+	_, err := op.client.DeletePolicy(ctx, &iam.DeletePolicyInput{
+		PolicyArn: aws.String(policyArn),
+	})
+	return err
+}
+
 func (op *Api) AttachPolicyToUser(ctx context.Context, policyArn, username string) error {
 	_, err := op.client.AttachUserPolicy(ctx, &iam.AttachUserPolicyInput{
 		PolicyArn: aws.String(policyArn),
@@ -126,6 +182,59 @@ func (op *Api) DeleteInlinePolicyFromRole(ctx context.Context, policyname, rolen
 	return err
 }
 
+// GetUserInlinePolicy returns the decoded document of an inline policy of the user.
+func (op *Api) GetUserInlinePolicy(ctx context.Context, policyname, username string) (string, error) {
+	output, err := op.client.GetUserPolicy(ctx, &iam.GetUserPolicyInput{
+		PolicyName: aws.String(policyname),
+		UserName:   aws.String(username),
+	})
+	if err != nil {
+		return "", err
+	}
+	return decodePolicyDocument(aws.ToString(output.PolicyDocument))
+}
+
+// GetGroupInlinePolicy returns the decoded document of an inline policy of the group.
+func (op *Api) GetGroupInlinePolicy(ctx context.Context, policyname, groupname string) (string, error) {
+	output, err := op.client.GetGroupPolicy(ctx, &iam.GetGroupPolicyInput{
+		PolicyName: aws.String(policyname),
+		GroupName:  aws.String(groupname),
+	})
+	if err != nil {
+		return "", err
+	}
+	return decodePolicyDocument(aws.ToString(output.PolicyDocument))
+}
+
+// GetRoleInlinePolicy returns the decoded document of an inline policy of the role.
+func (op *Api) GetRoleInlinePolicy(ctx context.Context, policyname, rolename string) (string, error) {
+	output, err := op.client.GetRolePolicy(ctx, &iam.GetRolePolicyInput{
+		PolicyName: aws.String(policyname),
+		RoleName:   aws.String(rolename),
+	})
+	if err != nil {
+		return "", err
+	}
+	return decodePolicyDocument(aws.ToString(output.PolicyDocument))
+}
+
+// GetAssumeRolePolicy returns the decoded trust policy of the role.
+func (op *Api) GetAssumeRolePolicy(ctx context.Context, rolename string) (string, error) {
+	output, err := op.FindRole(ctx, rolename)
+	if err != nil {
+		return "", err
+	}
+	return decodePolicyDocument(aws.ToString(output.Role.AssumeRolePolicyDocument))
+}
+
+func (op *Api) UpdateAssumeRolePolicy(ctx context.Context, rolename, policyDocument string) error {
+	_, err := op.client.UpdateAssumeRolePolicy(ctx, &iam.UpdateAssumeRolePolicyInput{
+		RoleName:       aws.String(rolename),
+		PolicyDocument: aws.String(policyDocument),
+	})
+	return err
+}
+
 func (op *Api) AddUserToGroup(ctx context.Context, username, groupname string) error {
 	_, err := op.client.AddUserToGroup(ctx, &iam.AddUserToGroupInput{
 		GroupName: aws.String(groupname),
@@ -141,3 +250,20 @@ func (op *Api) RemoveUserFromGroup(ctx context.Context, username, groupname stri
 	})
 	return err
 }
+
+// AccountAlias returns the alias of the account, or an empty string when it has none.
+func (op *Api) AccountAlias(ctx context.Context) (string, error) {
+	output, err := op.client.ListAccountAliases(ctx, &iam.ListAccountAliasesInput{})
+	if err != nil {
+		return "", err
+	}
+	if len(output.AccountAliases) == 0 {
+		return "", nil
+	}
+	return output.AccountAliases[0], nil
+}
+
+// Region returns the region the API is configured for.
+func (op *Api) Region() string {
+	return op.config.Region
+}
@@ -0,0 +1,242 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+)
+
+// TargePolicy is a customer managed policy created by targe.
+type TargePolicy struct {
+	// Policy is the full policy as returned by GetPolicy, including its tags.
+	Policy   types.Policy
+	Versions []types.PolicyVersion
+}
+
+// Tag returns the value of the policy tag, or an empty string if it is not set.
+func (p TargePolicy) Tag(key string) string {
+	for _, tag := range p.Policy.Tags {
+		if aws.ToString(tag.Key) == key {
+			return aws.ToString(tag.Value)
+		}
+	}
+	return ""
+}
+
+// PolicyEntities are the principals that use a managed policy, as a permissions policy or as
+// their permissions boundary.
+type PolicyEntities struct {
+	Users  []string
+	Groups []string
+	Roles  []string
+}
+
+// CreatedByTarge reports whether targe created the policy, through its tag or, for policies
+// created before tagging, through its description.
+func CreatedByTarge(policy types.Policy) bool {
+	for _, tag := range policy.Tags {
+		if aws.ToString(tag.Key) == CreatedByTagKey && aws.ToString(tag.Value) == CreatedByTagValue {
+			return true
+		}
+	}
+	return aws.ToString(policy.Description) == PolicyDescription
+}
+
+// TargePolicyPager lists the customer managed policies created by targe page by page. ListPolicies
+// returns no descriptions, so the policies are read with GetAccountAuthorizationDetails, which returns
+// them together with their versions in a single call per page. Tags are not returned, they are read
+// with GetPolicy for the selected policy only.
+func (op *Api) TargePolicyPager() *Pager[TargePolicy] {
+	input := &iam.GetAccountAuthorizationDetailsInput{
+		Filter: []types.EntityType{types.EntityTypeLocalManagedPolicy},
+	}
+	return newPager(iam.NewGetAccountAuthorizationDetailsPaginator(op.client, input), func(o *iam.GetAccountAuthorizationDetailsOutput) []TargePolicy {
+		var items []TargePolicy
+		for _, p := range o.Policies {
+			if aws.ToString(p.Description) != PolicyDescription {
+				continue
+			}
+			items = append(items, TargePolicy{
+				Policy: types.Policy{
+					Arn:                           p.Arn,
+					PolicyId:                      p.PolicyId,
+					PolicyName:                    p.PolicyName,
+					Path:                          p.Path,
+					Description:                   p.Description,
+					AttachmentCount:               p.AttachmentCount,
+					PermissionsBoundaryUsageCount: p.PermissionsBoundaryUsageCount,
+					DefaultVersionId:              p.DefaultVersionId,
+					IsAttachable:                  p.IsAttachable,
+					CreateDate:                    p.CreateDate,
+					UpdateDate:                    p.UpdateDate,
+				},
+				Versions: p.PolicyVersionList,
+			})
+		}
+		return items
+	})
+}
+
+func (op *Api) GetPolicy(ctx context.Context, policyArn string) (*types.Policy, error) {
+	output, err := op.client.GetPolicy(ctx, &iam.GetPolicyInput{
+		PolicyArn: aws.String(policyArn),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return output.Policy, nil
+}
+
+// GetPolicyDocument returns the decoded document of a policy version.
+func (op *Api) GetPolicyDocument(ctx context.Context, policyArn, versionId string) (string, error) {
+	output, err := op.client.GetPolicyVersion(ctx, &iam.GetPolicyVersionInput{
+		PolicyArn: aws.String(policyArn),
+		VersionId: aws.String(versionId),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return decodePolicyDocument(aws.ToString(output.PolicyVersion.Document))
+}
+
+// decodePolicyDocument decodes a policy document, IAM returns them URL encoded.
+func decodePolicyDocument(encoded string) (string, error) {
+	document, err := url.QueryUnescape(encoded)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode policy document: %w", err)
+	}
+	return document, nil
+}
+
+func (op *Api) ListPolicyVersions(ctx context.Context, policyArn string) ([]types.PolicyVersion, error) {
+	input := &iam.ListPolicyVersionsInput{
+		PolicyArn: aws.String(policyArn),
+	}
+
+	return newPager(iam.NewListPolicyVersionsPaginator(op.client, input), func(o *iam.ListPolicyVersionsOutput) []types.PolicyVersion {
+		return o.Versions
+	}).All(ctx)
+}
+
+// ListEntitiesForPolicy lists the principals that use the policy the given way, as a permissions
+// policy attached to them or as their permissions boundary. Groups have no permissions boundary.
+func (op *Api) ListEntitiesForPolicy(ctx context.Context, policyArn string, usage types.PolicyUsageType) (PolicyEntities, error) {
+	input := &iam.ListEntitiesForPolicyInput{
+		PolicyArn:         aws.String(policyArn),
+		PolicyUsageFilter: usage,
+	}
+
+	var entities PolicyEntities
+	p := iam.NewListEntitiesForPolicyPaginator(op.client, input)
+	for p.HasMorePages() {
+		output, err := p.NextPage(ctx)
+		if err != nil {
+			return PolicyEntities{}, err
+		}
+		for _, user := range output.PolicyUsers {
+			entities.Users = append(entities.Users, aws.ToString(user.UserName))
+		}
+		for _, group := range output.PolicyGroups {
+			entities.Groups = append(entities.Groups, aws.ToString(group.GroupName))
+		}
+		for _, role := range output.PolicyRoles {
+			entities.Roles = append(entities.Roles, aws.ToString(role.RoleName))
+		}
+	}
+
+	return entities, nil
+}
+
+func (op *Api) DeletePolicyVersion(ctx context.Context, policyArn, versionId string) error {
+	_, err := op.client.DeletePolicyVersion(ctx, &iam.DeletePolicyVersionInput{
+		PolicyArn: aws.String(policyArn),
+		VersionId: aws.String(versionId),
+	})
+	return err
+}
+
+// MaxPolicyVersions is the number of versions IAM keeps for a managed policy.
+const MaxPolicyVersions = 5
+
+// DefaultPolicyVersion returns the id of the default version.
+func DefaultPolicyVersion(versions []types.PolicyVersion) string {
+	for _, version := range versions {
+		if version.IsDefaultVersion {
+			return aws.ToString(version.VersionId)
+		}
+	}
+	return ""
+}
+
+// OldestPolicyVersion returns the id of the oldest version that is not the default one.
+func OldestPolicyVersion(versions []types.PolicyVersion) string {
+	var oldest *types.PolicyVersion
+	for i, version := range versions {
+		if version.IsDefaultVersion || version.CreateDate == nil {
+			continue
+		}
+		if oldest == nil || version.CreateDate.Before(*oldest.CreateDate) {
+			oldest = &versions[i]
+		}
+	}
+	if oldest == nil {
+		return ""
+	}
+	return aws.ToString(oldest.VersionId)
+}
+
+// CreatePolicyVersion adds a version to the policy and returns its id.
+func (op *Api) CreatePolicyVersion(ctx context.Context, policyArn, document string, setAsDefault bool) (string, error) {
+	output, err := op.client.CreatePolicyVersion(ctx, &iam.CreatePolicyVersionInput{
+		PolicyArn:      aws.String(policyArn),
+		PolicyDocument: aws.String(document),
+		SetAsDefault:   setAsDefault,
+	})
+	if err != nil {
+		return "", err
+	}
+	return aws.ToString(output.PolicyVersion.VersionId), nil
+}
+
+func (op *Api) SetDefaultPolicyVersion(ctx context.Context, policyArn, versionId string) error {
+	_, err := op.client.SetDefaultPolicyVersion(ctx, &iam.SetDefaultPolicyVersionInput{
+		PolicyArn: aws.String(policyArn),
+		VersionId: aws.String(versionId),
+	})
+	return err
+}
+
+// UpdatePolicy makes the document the new default version of the policy and returns the applied calls.
+// When the policy already has MaxPolicyVersions versions, the oldest non-default version is deleted
+// first if prune is set, otherwise an error is returned.
+func (op *Api) UpdatePolicy(ctx context.Context, policyArn, document string, prune bool) ([]Call, error) {
+	versions, err := op.ListPolicyVersions(ctx, policyArn)
+	if err != nil {
+		return nil, err
+	}
+
+	var applied []Call
+	if len(versions) >= MaxPolicyVersions {
+		oldest := OldestPolicyVersion(versions)
+		if !prune || oldest == "" {
+			return nil, fmt.Errorf("policy %s has reached the limit of %d versions, the oldest version has to be pruned first", policyArn, MaxPolicyVersions)
+		}
+		if err := op.DeletePolicyVersion(ctx, policyArn, oldest); err != nil {
+			return nil, err
+		}
+		applied = append(applied, NewCall(DeletePolicyVersionAction, "PolicyArn", policyArn, "VersionId", oldest))
+	}
+
+	previous := DefaultPolicyVersion(versions)
+	versionId, err := op.CreatePolicyVersion(ctx, policyArn, document, true)
+	if err != nil {
+		return applied, err
+	}
+
+	return append(applied, NewCall(CreatePolicyVersionAction, "PolicyArn", policyArn, "SetAsDefault", "true", "VersionId", versionId, "PreviousVersionId", previous)), nil
+}
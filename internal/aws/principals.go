@@ -0,0 +1,115 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+)
+
+// DefaultPath is the IAM path of entities created without one.
+const DefaultPath = "/"
+
+// ParseTags parses comma separated key=value pairs, e.g. "team=data, env=prod".
+func ParseTags(text string) (map[string]string, error) {
+	tags := map[string]string{}
+	for _, pair := range strings.Split(text, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("invalid tag %q, expected key=value", pair)
+		}
+		tags[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	return tags, nil
+}
+
+// ValidatePath checks that an IAM path starts and ends with a slash.
+func ValidatePath(path string) error {
+	if path == "" || !strings.HasPrefix(path, "/") || !strings.HasSuffix(path, "/") {
+		return fmt.Errorf("path must start and end with /")
+	}
+	return nil
+}
+
+// tags returns the given tags followed by the tags that mark the entity as created by targe.
+func (op *Api) tags(ctx context.Context, tags map[string]string) []types.Tag {
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var result []types.Tag
+	for _, key := range keys {
+		result = append(result, types.Tag{Key: aws.String(key), Value: aws.String(tags[key])})
+	}
+	return append(result, op.targeTags(ctx)...)
+}
+
+// CreateUser creates a user tagged with the given tags and as created by targe.
+func (op *Api) CreateUser(ctx context.Context, username, path string, tags map[string]string) (*types.User, error) {
+	output, err := op.client.CreateUser(ctx, &iam.CreateUserInput{
+		UserName: aws.String(username),
+		Path:     aws.String(path),
+		Tags:     op.tags(ctx, tags),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return output.User, nil
+}
+
+func (op *Api) DeleteUser(ctx context.Context, username string) error {
+	_, err := op.client.DeleteUser(ctx, &iam.DeleteUserInput{
+		UserName: aws.String(username),
+	})
+	return err
+}
+
+// CreateGroup creates a group. IAM groups cannot be tagged.
+func (op *Api) CreateGroup(ctx context.Context, groupname, path string) (*types.Group, error) {
+	output, err := op.client.CreateGroup(ctx, &iam.CreateGroupInput{
+		GroupName: aws.String(groupname),
+		Path:      aws.String(path),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return output.Group, nil
+}
+
+func (op *Api) DeleteGroup(ctx context.Context, groupname string) error {
+	_, err := op.client.DeleteGroup(ctx, &iam.DeleteGroupInput{
+		GroupName: aws.String(groupname),
+	})
+	return err
+}
+
+// CreateRole creates a role with the trust policy, tagged with the given tags and as created by targe.
+func (op *Api) CreateRole(ctx context.Context, rolename, path, trustPolicy string, tags map[string]string) (*types.Role, error) {
+	output, err := op.client.CreateRole(ctx, &iam.CreateRoleInput{
+		RoleName:                 aws.String(rolename),
+		Path:                     aws.String(path),
+		AssumeRolePolicyDocument: aws.String(trustPolicy),
+		Tags:                     op.tags(ctx, tags),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return output.Role, nil
+}
+
+func (op *Api) DeleteRole(ctx context.Context, rolename string) error {
+	_, err := op.client.DeleteRole(ctx, &iam.DeleteRoleInput{
+		RoleName: aws.String(rolename),
+	})
+	return err
+}
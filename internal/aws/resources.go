@@ -0,0 +1,241 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/resourceexplorer2"
+	"github.com/aws/aws-sdk-go-v2/service/resourceexplorer2/types"
+)
+
+// Resource represents a single AWS resource.
+type Resource struct {
+	Name   string
+	Arn    string
+	Region string
+	Tags   map[string]string
+}
+
+// ResourceQuery narrows down the resources returned by Resource Explorer.
+type ResourceQuery struct {
+	// Type is the CloudFormation resource type, for example AWS::S3::Bucket.
+	Type string
+	// Text is free text matched against the resource names, ARNs and tags.
+	Text string
+	// Tags limits the result to resources carrying all of the given tags.
+	Tags map[string]string
+}
+
+// filter builds the Resource Explorer filter string of the query without the free text.
+func (q ResourceQuery) filter(resourceType string) string {
+	parts := []string{"resourcetype:" + resourceType}
+
+	keys := make([]string, 0, len(q.Tags))
+	for key := range q.Tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		parts = append(parts, "tag:"+quoteQueryValue(key)+"="+quoteQueryValue(q.Tags[key]))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// quoteQueryValue quotes a value of the Resource Explorer query syntax when it contains spaces.
+func quoteQueryValue(value string) string {
+	if strings.ContainsAny(value, " \t") {
+		return `"` + value + `"`
+	}
+	return value
+}
+
+// ResourcePager lists the resources matching the query page by page. When the account has an
+// aggregator index the query runs against it, so resources from every indexed region are returned.
+// Free text queries go through Search, which Resource Explorer caps at 1000 results.
+func (op *Api) ResourcePager(ctx context.Context, query ResourceQuery) (*Pager[Resource], error) {
+	if query.Type == "" {
+		return nil, fmt.Errorf("resource type cannot be empty")
+	}
+
+	client, err := op.explorerClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resourceType, err := op.explorerResourceType(ctx, client, query.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := query.filter(resourceType)
+
+	if text := strings.TrimSpace(query.Text); text != "" {
+		input := &resourceexplorer2.SearchInput{
+			QueryString: aws.String(filter + " " + text),
+		}
+		p := resourceexplorer2.NewSearchPaginator(client, input)
+		return explorerPager(p.HasMorePages, func(ctx context.Context) ([]types.Resource, error) {
+			output, err := p.NextPage(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return output.Resources, nil
+		}), nil
+	}
+
+	input := &resourceexplorer2.ListResourcesInput{
+		Filters: &types.SearchFilter{FilterString: aws.String(filter)},
+	}
+	p := resourceexplorer2.NewListResourcesPaginator(client, input)
+	return explorerPager(p.HasMorePages, func(ctx context.Context) ([]types.Resource, error) {
+		output, err := p.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return output.Resources, nil
+	}), nil
+}
+
+// ListResources fetches every resource matching the query from the AWS Resource Explorer API.
+func (op *Api) ListResources(ctx context.Context, query ResourceQuery) ([]Resource, error) {
+	pager, err := op.ResourcePager(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return pager.All(ctx)
+}
+
+func explorerPager(hasMorePages func() bool, nextPage func(ctx context.Context) ([]types.Resource, error)) *Pager[Resource] {
+	return &Pager[Resource]{
+		hasMorePages: hasMorePages,
+		nextPage: func(ctx context.Context) ([]Resource, error) {
+			page, err := nextPage(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list resources: %w", err)
+			}
+
+			resources := make([]Resource, 0, len(page))
+			for _, r := range page {
+				arn := aws.ToString(r.Arn)
+				resources = append(resources, Resource{
+					Name:   extractResourceName(arn),
+					Arn:    arn,
+					Region: aws.ToString(r.Region),
+					Tags:   extractResourceTags(r.Properties),
+				})
+			}
+			return resources, nil
+		},
+	}
+}
+
+// explorerClient returns a Resource Explorer client for the region of the aggregator index,
+// falling back to the configured region when the account has none. The client is cached.
+func (op *Api) explorerClient(ctx context.Context) (*resourceexplorer2.Client, error) {
+	if op.explorer != nil {
+		return op.explorer, nil
+	}
+
+	client := resourceexplorer2.NewFromConfig(op.config)
+
+	output, err := client.ListIndexes(ctx, &resourceexplorer2.ListIndexesInput{
+		Type: types.IndexTypeAggregator,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list resource explorer indexes: %w", err)
+	}
+
+	if len(output.Indexes) > 0 {
+		if region := aws.ToString(output.Indexes[0].Region); region != "" && region != op.config.Region {
+			client = resourceexplorer2.NewFromConfig(op.config, func(o *resourceexplorer2.Options) {
+				o.Region = region
+			})
+		}
+	}
+
+	op.explorer = client
+	return client, nil
+}
+
+// explorerResourceType converts a CloudFormation resource type such as AWS::S3::Bucket to the
+// Resource Explorer form s3:bucket. Names that do not match one to one, like AWS::RDS::DBCluster,
+// are resolved against the resource types Resource Explorer supports.
+func (op *Api) explorerResourceType(ctx context.Context, client *resourceexplorer2.Client, cfnType string) (string, error) {
+	parts := strings.Split(cfnType, "::")
+	if len(parts) != 3 {
+		// Already in the Resource Explorer form
+		return cfnType, nil
+	}
+
+	service := strings.ToLower(parts[1])
+	name := strings.ToLower(parts[2])
+	candidate := service + ":" + name
+
+	if op.explorerTypes == nil {
+		p := resourceexplorer2.NewListSupportedResourceTypesPaginator(client, &resourceexplorer2.ListSupportedResourceTypesInput{})
+		supported := map[string]bool{}
+		for p.HasMorePages() {
+			output, err := p.NextPage(ctx)
+			if err != nil {
+				return "", fmt.Errorf("failed to list supported resource types: %w", err)
+			}
+			for _, t := range output.ResourceTypes {
+				supported[aws.ToString(t.ResourceType)] = true
+			}
+		}
+		op.explorerTypes = supported
+	}
+
+	if op.explorerTypes[candidate] {
+		return candidate, nil
+	}
+
+	// Pick the longest supported type of the service that the CloudFormation name ends with,
+	// then the longest one it starts with, e.g. DBInstance matches rds:db
+	for _, matches := range []func(name, t string) bool{strings.HasSuffix, strings.HasPrefix} {
+		best := ""
+		for t := range op.explorerTypes {
+			short, ok := strings.CutPrefix(t, service+":")
+			if !ok || short == "" || !matches(name, short) {
+				continue
+			}
+			if len(t) > len(best) || (len(t) == len(best) && t < best) {
+				best = t
+			}
+		}
+		if best != "" {
+			return best, nil
+		}
+	}
+
+	return candidate, nil
+}
+
+// extractResourceTags reads the tags property reported by Resource Explorer.
+func extractResourceTags(properties []types.ResourceProperty) map[string]string {
+	tags := map[string]string{}
+	for _, property := range properties {
+		if aws.ToString(property.Name) != "tags" || property.Data == nil {
+			continue
+		}
+
+		var data []map[string]interface{}
+		if err := property.Data.UnmarshalSmithyDocument(&data); err != nil {
+			continue
+		}
+
+		for _, tag := range data {
+			key, _ := tag["Key"].(string)
+			value, _ := tag["Value"].(string)
+			if key != "" {
+				tags[key] = value
+			}
+		}
+	}
+	return tags
+}
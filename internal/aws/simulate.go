@@ -0,0 +1,94 @@
+package aws
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+)
+
+// Decisions of the policy simulator.
+const (
+	DecisionAllowed      = string(types.PolicyEvaluationDecisionTypeAllowed)
+	DecisionImplicitDeny = string(types.PolicyEvaluationDecisionTypeImplicitDeny)
+	DecisionExplicitDeny = string(types.PolicyEvaluationDecisionTypeExplicitDeny)
+)
+
+// Simulation is the decision of the policy simulator for an action on a resource.
+type Simulation struct {
+	Action   string `json:"action"`
+	Resource string `json:"resource"`
+	Decision string `json:"decision"`
+	// DeniedBy names a service control policy or permissions boundary that denies an action the
+	// policies would allow, empty otherwise.
+	DeniedBy string `json:"denied_by,omitempty"`
+	// Missing lists the context keys the decision depends on but were not provided.
+	Missing []string `json:"missing_context,omitempty"`
+}
+
+// Allowed reports whether the simulator allowed the action.
+func (s Simulation) Allowed() bool {
+	return s.Decision == DecisionAllowed
+}
+
+// SimulatePrincipalPolicy simulates the actions on the resources for a user, group or role with all
+// of its policies, its permissions boundary and the service control policies of the account. The
+// documents are evaluated as if they were attached to the principal too. Without resources the
+// actions are simulated on "*".
+func (op *Api) SimulatePrincipalPolicy(ctx context.Context, principalArn string, actions, resources, documents []string) ([]Simulation, error) {
+	input := &iam.SimulatePrincipalPolicyInput{
+		PolicySourceArn: aws.String(principalArn),
+		ActionNames:     actions,
+		ResourceArns:    resources,
+		PolicyInputList: documents,
+	}
+
+	results, err := newPager(iam.NewSimulatePrincipalPolicyPaginator(op.client, input), func(o *iam.SimulatePrincipalPolicyOutput) []types.EvaluationResult {
+		return o.EvaluationResults
+	}).All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return simulations(results), nil
+}
+
+// SimulateCustomPolicy simulates the actions on the resources for policy documents that are not
+// attached to anyone yet, limited by the permissions boundary documents if any are given.
+func (op *Api) SimulateCustomPolicy(ctx context.Context, documents, boundaries, actions, resources []string) ([]Simulation, error) {
+	input := &iam.SimulateCustomPolicyInput{
+		PolicyInputList:                    documents,
+		PermissionsBoundaryPolicyInputList: boundaries,
+		ActionNames:                        actions,
+		ResourceArns:                       resources,
+	}
+
+	results, err := newPager(iam.NewSimulateCustomPolicyPaginator(op.client, input), func(o *iam.SimulateCustomPolicyOutput) []types.EvaluationResult {
+		return o.EvaluationResults
+	}).All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return simulations(results), nil
+}
+
+// simulations converts the evaluation results of the simulator.
+func simulations(results []types.EvaluationResult) []Simulation {
+	var items []Simulation
+	for _, result := range results {
+		item := Simulation{
+			Action:   aws.ToString(result.EvalActionName),
+			Resource: aws.ToString(result.EvalResourceName),
+			Decision: string(result.EvalDecision),
+			Missing:  result.MissingContextValues,
+		}
+		switch {
+		case result.OrganizationsDecisionDetail != nil && !result.OrganizationsDecisionDetail.AllowedByOrganizations:
+			item.DeniedBy = "service control policy"
+		case result.PermissionsBoundaryDecisionDetail != nil && !result.PermissionsBoundaryDecisionDetail.AllowedByPermissionsBoundary:
+			item.DeniedBy = "permissions boundary"
+		}
+		items = append(items, item)
+	}
+	return items
+}
@@ -9,12 +9,49 @@ import (
 	"github.com/spf13/viper"
 )
 
+// Folder is the directory that holds the targe configuration and local state.
+var Folder = os.ExpandEnv("$HOME/.targe/")
+
 type (
 	Config struct {
 		OpenaiApiKey string `mapstructure:"openai_api_key"`
+		AI           AI     `mapstructure:"ai"`
+	}
+
+	// AI selects the language model provider of the [ai] table. Without a provider, OpenAI is
+	// used with openai_api_key.
+	AI struct {
+		// Provider is one of openai, azure, anthropic, bedrock or openai-compatible.
+		Provider string `mapstructure:"provider"`
+		// Model is the model name, the model ID on Bedrock. Each provider has a default.
+		Model string `mapstructure:"model"`
+		// BaseUrl overrides the endpoint of the provider, e.g. "http://localhost:11434/v1" for
+		// Ollama. It is the resource endpoint on Azure.
+		BaseUrl string `mapstructure:"base_url"`
+		// ApiKey authenticates with the provider, openai_api_key is used for openai when empty.
+		ApiKey string `mapstructure:"api_key"`
+		// Deployment and ApiVersion select the deployment on Azure.
+		Deployment string `mapstructure:"deployment"`
+		ApiVersion string `mapstructure:"api_version"`
+		// Region and Profile select the AWS credentials on Bedrock.
+		Region  string `mapstructure:"region"`
+		Profile string `mapstructure:"profile"`
 	}
 )
 
+// Keys lists the keys `targe config` can set and get.
+var Keys = []string{
+	"openai_api_key",
+	"ai.provider",
+	"ai.model",
+	"ai.base_url",
+	"ai.api_key",
+	"ai.deployment",
+	"ai.api_version",
+	"ai.region",
+	"ai.profile",
+}
+
 // NewConfig initializes and returns a new Config object by reading and unmarshalling
 // the configuration file from the given path. It falls back to the DefaultConfig if the
 // file is not found. If there's an error during the process, it returns the error.
@@ -27,7 +64,7 @@ func NewConfig() (*Config, error) {
 	viper.SetConfigType("toml")
 
 	// Add the path where the config file is located
-	configPath := os.ExpandEnv("$HOME/.targe/")
+	configPath := Folder
 	viper.AddConfigPath(configPath)
 
 	// Ensure the directory exists
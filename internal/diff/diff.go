@@ -0,0 +1,95 @@
+package diff
+
+import (
+	"strings"
+)
+
+// Kinds of diff lines.
+const (
+	Equal   = ' '
+	Added   = '+'
+	Removed = '-'
+)
+
+// Line is a single line of a line based diff.
+type Line struct {
+	Kind rune
+	Text string
+}
+
+// String renders the line with its kind as prefix.
+func (l Line) String() string {
+	return string(l.Kind) + " " + l.Text
+}
+
+// Lines computes the line based diff turning a into b using the longest common subsequence.
+func Lines(a, b string) []Line {
+	before := splitLines(a)
+	after := splitLines(b)
+
+	// lcs[i][j] is the length of the longest common subsequence of before[i:] and after[j:]
+	lcs := make([][]int, len(before)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(after)+1)
+	}
+	for i := len(before) - 1; i >= 0; i-- {
+		for j := len(after) - 1; j >= 0; j-- {
+			if before[i] == after[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var lines []Line
+	i, j := 0, 0
+	for i < len(before) && j < len(after) {
+		switch {
+		case before[i] == after[j]:
+			lines = append(lines, Line{Kind: Equal, Text: before[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, Line{Kind: Removed, Text: before[i]})
+			i++
+		default:
+			lines = append(lines, Line{Kind: Added, Text: after[j]})
+			j++
+		}
+	}
+	for ; i < len(before); i++ {
+		lines = append(lines, Line{Kind: Removed, Text: before[i]})
+	}
+	for ; j < len(after); j++ {
+		lines = append(lines, Line{Kind: Added, Text: after[j]})
+	}
+
+	return lines
+}
+
+// Changed reports whether the diff contains any added or removed line.
+func Changed(lines []Line) bool {
+	for _, line := range lines {
+		if line.Kind != Equal {
+			return true
+		}
+	}
+	return false
+}
+
+// Unified renders the diff with a prefix on every line.
+func Unified(a, b string) string {
+	var rendered []string
+	for _, line := range Lines(a, b) {
+		rendered = append(rendered, line.String())
+	}
+	return strings.Join(rendered, "\n")
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
@@ -0,0 +1,76 @@
+package diff
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    string
+		want    []Line
+		changed bool
+	}{
+		{
+			name: "both empty",
+		},
+		{
+			name: "equal",
+			a:    "{\n}\n",
+			b:    "{\n}",
+			want: []Line{{Equal, "{"}, {Equal, "}"}},
+		},
+		{
+			name:    "added to an empty document",
+			b:       "{\n}",
+			want:    []Line{{Added, "{"}, {Added, "}"}},
+			changed: true,
+		},
+		{
+			name:    "removed entirely",
+			a:       "{\n}",
+			want:    []Line{{Removed, "{"}, {Removed, "}"}},
+			changed: true,
+		},
+		{
+			name:    "line changed",
+			a:       "{\n\t\"Effect\": \"Allow\"\n}",
+			b:       "{\n\t\"Effect\": \"Deny\"\n}",
+			want:    []Line{{Equal, "{"}, {Removed, "\t\"Effect\": \"Allow\""}, {Added, "\t\"Effect\": \"Deny\""}, {Equal, "}"}},
+			changed: true,
+		},
+		{
+			name:    "line inserted",
+			a:       "a\nc",
+			b:       "a\nb\nc",
+			want:    []Line{{Equal, "a"}, {Added, "b"}, {Equal, "c"}},
+			changed: true,
+		},
+		{
+			name:    "lines moved",
+			a:       "a\nb\nc",
+			b:       "c\na\nb",
+			want:    []Line{{Added, "c"}, {Equal, "a"}, {Equal, "b"}, {Removed, "c"}},
+			changed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Lines(tt.a, tt.b)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Lines(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+			if changed := Changed(got); changed != tt.changed {
+				t.Errorf("Changed() = %v, want %v", changed, tt.changed)
+			}
+		})
+	}
+}
+
+func TestUnified(t *testing.T) {
+	if got, want := Unified("a\nb", "a\nc"), "  a\n- b\n+ c"; got != want {
+		t.Errorf("Unified() = %q, want %q", got, want)
+	}
+}
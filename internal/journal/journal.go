@@ -0,0 +1,165 @@
+package journal
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/Permify/targe/internal/aws"
+	"github.com/Permify/targe/internal/config"
+	"github.com/Permify/targe/internal/safefile"
+)
+
+// FileName is the name of the journal file inside the config folder.
+var FileName = "journal.json"
+
+// Entry records an applied operation together with the calls that revert it.
+type Entry struct {
+	Id        string     `json:"id"`
+	Time      time.Time  `json:"time"`
+	Flow      string     `json:"flow"`
+	Operation string     `json:"operation"`
+	Principal string     `json:"principal"`
+	Inverse   []aws.Call `json:"inverse"`
+	RoleArn   string     `json:"role_arn,omitempty"`
+	UndoneAt  *time.Time `json:"undone_at,omitempty"`
+}
+
+func path() string {
+	return filepath.Join(config.Folder, FileName)
+}
+
+// Append assigns an id to the entry and appends it to the journal. The journal is locked while
+// the id is assigned, so concurrent runs never share an id.
+func Append(entry Entry) (Entry, error) {
+	unlock, err := safefile.Lock(path())
+	if err != nil {
+		return entry, err
+	}
+	defer unlock()
+
+	entries, err := List()
+	if err != nil {
+		return entry, err
+	}
+
+	entry.Id = strconv.Itoa(nextId(entries))
+	if entry.Time.IsZero() {
+		entry.Time = time.Now().UTC()
+	}
+
+	file, err := os.OpenFile(path(), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return entry, fmt.Errorf("failed to open journal: %w", err)
+	}
+	defer file.Close()
+
+	if err := json.NewEncoder(file).Encode(entry); err != nil {
+		return entry, fmt.Errorf("failed to write journal entry: %w", err)
+	}
+
+	return entry, nil
+}
+
+// nextId returns the id following the highest id of the entries.
+func nextId(entries []Entry) int {
+	next := 1
+	for _, entry := range entries {
+		if id, err := strconv.Atoi(entry.Id); err == nil && id >= next {
+			next = id + 1
+		}
+	}
+	return next
+}
+
+// List reads all journal entries, oldest first.
+func List() ([]Entry, error) {
+	file, err := os.Open(path())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open journal: %w", err)
+	}
+	defer file.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("failed to parse journal entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, scanner.Err()
+}
+
+// Find returns the entry with the given id. An empty id selects the latest entry that has not been undone.
+func Find(id string) (Entry, error) {
+	entries, err := List()
+	if err != nil {
+		return Entry{}, err
+	}
+
+	for i := len(entries) - 1; i >= 0; i-- {
+		if id == "" && entries[i].UndoneAt == nil {
+			return entries[i], nil
+		}
+		if id != "" && entries[i].Id == id {
+			return entries[i], nil
+		}
+	}
+
+	if id == "" {
+		return Entry{}, fmt.Errorf("nothing to undo")
+	}
+	return Entry{}, fmt.Errorf("journal entry '%s' not found", id)
+}
+
+// MarkUndone records that the entry with the given id has been reverted. The journal is rewritten
+// under the lock through a temporary file, so a failed write never truncates it.
+func MarkUndone(id string) error {
+	unlock, err := safefile.Lock(path())
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	entries, err := List()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	for i := range entries {
+		if entries[i].Id == id {
+			entries[i].UndoneAt = &now
+		}
+	}
+
+	var data bytes.Buffer
+	encoder := json.NewEncoder(&data)
+	for _, entry := range entries {
+		if err := encoder.Encode(entry); err != nil {
+			return fmt.Errorf("failed to write journal entry: %w", err)
+		}
+	}
+
+	if err := safefile.WriteFile(path(), data.Bytes(), 0o600); err != nil {
+		return fmt.Errorf("failed to write journal: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package journal
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/Permify/targe/internal/config"
+)
+
+func TestAppendConcurrent(t *testing.T) {
+	folder := config.Folder
+	config.Folder = t.TempDir()
+	defer func() { config.Folder = folder }()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := Append(Entry{Flow: "users", Operation: "attach_policy"}); err != nil {
+				t.Errorf("Append() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	entries, err := List()
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(entries) != 20 {
+		t.Fatalf("List() returned %d entries, want 20", len(entries))
+	}
+
+	ids := map[string]bool{}
+	for _, entry := range entries {
+		if ids[entry.Id] {
+			t.Errorf("Append() assigned id %s twice", entry.Id)
+		}
+		ids[entry.Id] = true
+	}
+}
+
+func TestMarkUndone(t *testing.T) {
+	folder := config.Folder
+	config.Folder = t.TempDir()
+	defer func() { config.Folder = folder }()
+
+	for i := 0; i < 3; i++ {
+		if _, err := Append(Entry{Flow: "users"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := MarkUndone("3"); err != nil {
+		t.Fatalf("MarkUndone() error = %v", err)
+	}
+
+	entry, err := Find("")
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+	if entry.Id != "2" {
+		t.Errorf("Find() = %s, want the latest entry not undone, 2", entry.Id)
+	}
+
+	// Ids keep growing after an entry is undone
+	entry, err = Append(Entry{Flow: "users"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.Id != "4" {
+		t.Errorf("Append() id = %s, want 4", entry.Id)
+	}
+}
@@ -0,0 +1,148 @@
+package lease
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/Permify/targe/internal/aws"
+	"github.com/Permify/targe/internal/config"
+	"github.com/Permify/targe/internal/safefile"
+)
+
+// FileName is the name of the lease store inside the config folder.
+var FileName = "leases.json"
+
+// Lease is a temporary grant that has to be revoked once it expires.
+type Lease struct {
+	Id        string     `json:"id"`
+	Flow      string     `json:"flow"`
+	Operation string     `json:"operation"`
+	Principal string     `json:"principal"`
+	Target    string     `json:"target"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	Revoke    []aws.Call `json:"revoke"`
+	RoleArn   string     `json:"role_arn,omitempty"`
+	// JournalId is the id of the journal entry of the grant.
+	JournalId string     `json:"journal_id,omitempty"`
+	ReapedAt  *time.Time `json:"reaped_at,omitempty"`
+}
+
+// Expired reports whether the lease has expired and is still waiting to be reaped.
+func (l Lease) Expired(now time.Time) bool {
+	return l.ReapedAt == nil && !now.Before(l.ExpiresAt)
+}
+
+func path() string {
+	return filepath.Join(config.Folder, FileName)
+}
+
+// Add assigns an id to the lease and stores it. The store is locked while the id is assigned,
+// so concurrent runs never share an id.
+func Add(lease Lease) (Lease, error) {
+	unlock, err := safefile.Lock(path())
+	if err != nil {
+		return lease, err
+	}
+	defer unlock()
+
+	leases, err := List()
+	if err != nil {
+		return lease, err
+	}
+
+	lease.Id = strconv.Itoa(nextId(leases))
+	if lease.CreatedAt.IsZero() {
+		lease.CreatedAt = time.Now().UTC()
+	}
+
+	return lease, save(append(leases, lease))
+}
+
+// nextId returns the id following the highest id of the leases.
+func nextId(leases []Lease) int {
+	next := 1
+	for _, lease := range leases {
+		if id, err := strconv.Atoi(lease.Id); err == nil && id >= next {
+			next = id + 1
+		}
+	}
+	return next
+}
+
+// List reads all leases, oldest first.
+func List() ([]Lease, error) {
+	data, err := os.ReadFile(path())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read leases: %w", err)
+	}
+
+	var leases []Lease
+	if err := json.Unmarshal(data, &leases); err != nil {
+		return nil, fmt.Errorf("failed to parse leases: %w", err)
+	}
+
+	return leases, nil
+}
+
+// MarkUndone retires the leases of the grant recorded in the journal entry with the given id once
+// the grant has been undone, so they are not revoked again.
+func MarkUndone(journalId string) error {
+	return retire(func(l Lease) bool { return l.JournalId == journalId })
+}
+
+// MarkReaped retires the lease with the given id once its grant has been revoked.
+func MarkReaped(id string) error {
+	return retire(func(l Lease) bool { return l.Id == id })
+}
+
+// retire marks the unreaped leases selected by match as reaped. The store is read and rewritten
+// under the lock, so leases added by a concurrent run are kept.
+func retire(match func(Lease) bool) error {
+	unlock, err := safefile.Lock(path())
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	leases, err := List()
+	if err != nil {
+		return err
+	}
+
+	changed := false
+	now := time.Now().UTC()
+	for i := range leases {
+		if match(leases[i]) && leases[i].ReapedAt == nil {
+			leases[i].ReapedAt = &now
+			changed = true
+		}
+	}
+
+	if !changed {
+		return nil
+	}
+	return save(leases)
+}
+
+// save replaces the stored leases through a temporary file, so a failed write never truncates them.
+// The caller holds the lock.
+func save(leases []Lease) error {
+	data, err := json.MarshalIndent(leases, "", " ")
+	if err != nil {
+		return err
+	}
+
+	if err := safefile.WriteFile(path(), data, 0o600); err != nil {
+		return fmt.Errorf("failed to write leases: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package lease
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Permify/targe/internal/config"
+)
+
+func TestAddConcurrent(t *testing.T) {
+	folder := config.Folder
+	config.Folder = t.TempDir()
+	defer func() { config.Folder = folder }()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := Add(Lease{Flow: "users", ExpiresAt: time.Now().Add(time.Hour)}); err != nil {
+				t.Errorf("Add() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	leases, err := List()
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(leases) != 20 {
+		t.Fatalf("List() returned %d leases, want 20", len(leases))
+	}
+
+	ids := map[string]bool{}
+	for _, l := range leases {
+		if ids[l.Id] {
+			t.Errorf("Add() assigned id %s twice", l.Id)
+		}
+		ids[l.Id] = true
+	}
+}
+
+func TestRetire(t *testing.T) {
+	folder := config.Folder
+	config.Folder = t.TempDir()
+	defer func() { config.Folder = folder }()
+
+	for _, journalId := range []string{"1", "2", "2"} {
+		if _, err := Add(Lease{JournalId: journalId}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := MarkUndone("2"); err != nil {
+		t.Fatalf("MarkUndone() error = %v", err)
+	}
+	if err := MarkReaped("1"); err != nil {
+		t.Fatalf("MarkReaped() error = %v", err)
+	}
+
+	leases, err := List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, l := range leases {
+		if l.ReapedAt == nil {
+			t.Errorf("lease #%s was not retired", l.Id)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/Permify/targe/internal/ai"
+)
+
+// Severities of a finding. Errors block the policy from being applied unless it is forced.
+const (
+	SeverityWarning = "warning"
+	SeverityError   = "error"
+)
+
+// Rules reported by Lint.
+const (
+	RuleAdminAccess      = "admin-access"
+	RuleAllResources     = "all-resources"
+	RuleNotAction        = "not-action"
+	RulePassRole         = "pass-role"
+	RuleServiceWildcard  = "service-wildcard"
+	RuleInvalidAction    = "invalid-action"
+	RuleUnknownAction    = "unknown-action"
+	RuleInvalidResource  = "invalid-resource"
+	RuleResourceMismatch = "resource-mismatch"
+)
+
+// Finding is a problem Lint found in a statement of a policy.
+type Finding struct {
+	Severity string `json:"severity"`
+	Rule     string `json:"rule"`
+	// Statement is the Sid of the statement, or its position when it has none.
+	Statement string `json:"statement"`
+	Message   string `json:"message"`
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%s: %s (statement %s)", f.Severity, f.Message, f.Statement)
+}
+
+// Catalog describes the actions of the AWS services.
+type Catalog interface {
+	// HasService reports whether the catalog describes the service prefix, e.g. "s3".
+	HasService(service string) bool
+	// HasAction reports whether the action exists, or whether a pattern such as "s3:Get*" matches
+	// any action.
+	HasAction(action string) bool
+	// AppliesTo reports whether the action applies to the resource ARN. The second value is false
+	// when the catalog does not describe the action.
+	AppliesTo(action, arn string) (bool, bool)
+	// Unscoped reports whether the action applies to no resource type, so it is allowed on "*".
+	Unscoped(action string) bool
+}
+
+// actionPattern is the syntax IAM accepts for an action, a service prefix and an action name that
+// may contain wildcards.
+var actionPattern = regexp.MustCompile(`^[a-zA-Z0-9-]+:[a-zA-Z0-9*?]+$`)
+
+// arnServices lists the services whose resources an action may name besides its own.
+var arnServices = map[string][]string{
+	"sts": {"iam"},
+	"ssm": {"ec2", "s3"},
+}
+
+// Lint checks the policy for statements that grant too much or cannot work, without calling AWS.
+// Actions are only checked against the catalog when one is given.
+func Lint(policy ai.IAMPolicy, catalog Catalog) []Finding {
+	var findings []Finding
+	for i, statement := range policy.Statement {
+		id := statement.Sid
+		if id == "" {
+			id = fmt.Sprintf("#%d", i+1)
+		}
+		add := func(severity, rule, message string) {
+			findings = append(findings, Finding{Severity: severity, Rule: rule, Statement: id, Message: message})
+		}
+
+		actions := slices.Concat(values(statement.Action), values(statement.NotAction))
+		resources := values(statement.Resource)
+
+		if statement.Effect == "Allow" {
+			allResources := slices.Contains(resources, "*") || statement.NotResource != nil
+			switch {
+			case slices.Contains(values(statement.Action), "*"):
+				add(SeverityError, RuleAdminAccess, "allows every action")
+			case statement.NotAction != nil:
+				add(SeverityWarning, RuleNotAction, "allows every action except "+strings.Join(values(statement.NotAction), ", "))
+			case allResources && !unscoped(catalog, values(statement.Action)):
+				add(SeverityWarning, RuleAllResources, "allows "+strings.Join(values(statement.Action), ", ")+" on all resources")
+			}
+
+			if allResources && !slices.Contains(values(statement.Action), "*") && slices.ContainsFunc(values(statement.Action), func(action string) bool {
+				return ai.MatchAction(action, "iam:PassRole")
+			}) {
+				add(SeverityError, RulePassRole, "allows iam:PassRole on all roles, which lets the principal hand any role to a service")
+			}
+
+			for _, action := range values(statement.Action) {
+				if service, name, ok := strings.Cut(action, ":"); ok && name == "*" {
+					add(SeverityWarning, RuleServiceWildcard, "allows every "+service+" action")
+				}
+			}
+		}
+
+		for _, action := range actions {
+			if action == "*" {
+				continue
+			}
+			if !actionPattern.MatchString(action) {
+				add(SeverityError, RuleInvalidAction, fmt.Sprintf("%q is not a valid action, expected service:Action", action))
+				continue
+			}
+			if catalog == nil {
+				continue
+			}
+			service := Service(action)
+			switch {
+			case !catalog.HasService(service):
+				add(SeverityWarning, RuleUnknownAction, fmt.Sprintf("%q is not a known service", service))
+			case !catalog.HasAction(action) && strings.ContainsAny(action, "*?"):
+				add(SeverityWarning, RuleUnknownAction, fmt.Sprintf("%s matches no action", action))
+			case !catalog.HasAction(action):
+				add(SeverityWarning, RuleUnknownAction, fmt.Sprintf("%s does not exist", action))
+			}
+		}
+
+		for _, resource := range slices.Concat(resources, values(statement.NotResource)) {
+			if resource == "*" {
+				continue
+			}
+			parts := strings.SplitN(resource, ":", 6)
+			if len(parts) < 6 || parts[0] != "arn" || parts[2] == "" {
+				add(SeverityError, RuleInvalidResource, fmt.Sprintf("%q is not an ARN", resource))
+				continue
+			}
+			if len(actions) > 0 && !fits(catalog, actions, resource) {
+				add(SeverityWarning, RuleResourceMismatch, fmt.Sprintf("none of the actions apply to %s", resource))
+			}
+		}
+	}
+	return findings
+}
+
+// Blocking reports whether any of the findings is an error.
+func Blocking(findings []Finding) bool {
+	return slices.ContainsFunc(findings, func(f Finding) bool {
+		return f.Severity == SeverityError
+	})
+}
+
+// Service returns the service prefix of an action, "s3" for "s3:GetObject".
+func Service(action string) string {
+	service, _, _ := strings.Cut(action, ":")
+	return strings.ToLower(service)
+}
+
+// fits reports whether one of the actions may apply to the resource. Actions the catalog describes
+// are compared with the ARN formats of their resource types, other actions and resources with
+// wildcards or policy variables only with the service of the ARN.
+func fits(catalog Catalog, actions []string, resource string) bool {
+	arnService := strings.SplitN(resource, ":", 4)[2]
+	for _, action := range actions {
+		if catalog != nil && !strings.ContainsAny(action+resource, "*?$") {
+			if applies, known := catalog.AppliesTo(action, resource); known {
+				if applies {
+					return true
+				}
+				continue
+			}
+		}
+
+		service := Service(action)
+		if action == "*" || service == arnService || slices.Contains(arnServices[service], arnService) {
+			return true
+		}
+	}
+	return false
+}
+
+// unscoped reports whether none of the actions applies to a resource type.
+func unscoped(catalog Catalog, actions []string) bool {
+	return catalog != nil && !slices.ContainsFunc(actions, func(action string) bool {
+		return !catalog.Unscoped(action)
+	})
+}
+
+// values returns the entries of an Action or Resource element, "*" for a wildcard.
+func values(ar *ai.IAMActionResource) []string {
+	if ar == nil {
+		return nil
+	}
+	if ar.IsWildcard {
+		return []string{"*"}
+	}
+	return ar.Resources
+}
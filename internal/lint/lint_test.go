@@ -0,0 +1,246 @@
+package lint
+
+import (
+	"reflect"
+	"regexp"
+	"slices"
+	"testing"
+
+	"github.com/Permify/targe/internal/ai"
+)
+
+// catalog describes a few S3 and IAM actions.
+type catalog struct{}
+
+var (
+	catalogActions = []string{"s3:GetObject", "s3:PutObject", "s3:ListBucket", "s3:ListAllMyBuckets", "iam:PassRole", "iam:GetRole"}
+
+	// catalogResources are the ARN formats of the actions that apply to a resource type.
+	catalogResources = map[string]*regexp.Regexp{
+		"s3:GetObject":  regexp.MustCompile(`^arn:aws:s3:::[^/]+/.+$`),
+		"s3:PutObject":  regexp.MustCompile(`^arn:aws:s3:::[^/]+/.+$`),
+		"s3:ListBucket": regexp.MustCompile(`^arn:aws:s3:::[^/]+$`),
+		"iam:PassRole":  regexp.MustCompile(`^arn:aws:iam::\d+:role/.+$`),
+		"iam:GetRole":   regexp.MustCompile(`^arn:aws:iam::\d+:role/.+$`),
+	}
+)
+
+func (catalog) HasService(service string) bool {
+	return service == "s3" || service == "iam"
+}
+
+func (catalog) HasAction(action string) bool {
+	return slices.ContainsFunc(catalogActions, func(known string) bool {
+		return ai.MatchAction(action, known)
+	})
+}
+
+func (catalog) AppliesTo(action, arn string) (bool, bool) {
+	format, ok := catalogResources[action]
+	if !ok {
+		return false, false
+	}
+	return format.MatchString(arn), true
+}
+
+func (catalog) Unscoped(action string) bool {
+	return action == "s3:ListAllMyBuckets"
+}
+
+func policy(t *testing.T, document string) ai.IAMPolicy {
+	t.Helper()
+	p, err := ai.ParsePolicy(document)
+	if err != nil {
+		t.Fatalf("ParsePolicy(%s) error = %v", document, err)
+	}
+	return p
+}
+
+// rules returns the severity and rule of each finding.
+func rules(findings []Finding) []string {
+	var rules []string
+	for _, f := range findings {
+		rules = append(rules, f.Severity+" "+f.Rule)
+	}
+	return rules
+}
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		catalog  Catalog
+		want     []string
+	}{
+		{
+			name:     "every action allowed",
+			document: `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"*","Resource":"*"}]}`,
+			want:     []string{"error admin-access"},
+		},
+		{
+			name:     "every action denied",
+			document: `{"Version":"2012-10-17","Statement":[{"Effect":"Deny","Action":"*","Resource":"*"}]}`,
+		},
+		{
+			name:     "pass role on all roles",
+			document: `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"iam:PassRole","Resource":"*"}]}`,
+			want:     []string{"warning all-resources", "error pass-role"},
+		},
+		{
+			name:     "pass role through a wildcard",
+			document: `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["iam:Pass*"],"Resource":"*"}]}`,
+			catalog:  catalog{},
+			want:     []string{"warning all-resources", "error pass-role"},
+		},
+		{
+			name:     "pass role on a single role",
+			document: `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"iam:PassRole","Resource":"arn:aws:iam::123456789012:role/deploy"}]}`,
+			catalog:  catalog{},
+		},
+		{
+			name:     "service wildcard",
+			document: `{"Version":"2012-10-17","Statement":[{"Sid":"Reports","Effect":"Allow","Action":"s3:*","Resource":"arn:aws:s3:::reports/*"}]}`,
+			want:     []string{"warning service-wildcard"},
+		},
+		{
+			name:     "every action except some",
+			document: `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","NotAction":"iam:*","Resource":"*"}]}`,
+			want:     []string{"warning not-action"},
+		},
+		{
+			name:     "resource that is not an ARN",
+			document: `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"reports"}]}`,
+			want:     []string{"error invalid-resource"},
+		},
+		{
+			name:     "action without a service",
+			document: `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"GetObject","Resource":"arn:aws:s3:::reports/*"}]}`,
+			want:     []string{"error invalid-action", "warning resource-mismatch"},
+		},
+		{
+			name:     "unknown action without a catalog",
+			document: `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:GetObjekt","Resource":"arn:aws:s3:::reports/*"}]}`,
+		},
+		{
+			name:     "unknown action with a catalog",
+			document: `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:GetObjekt","Resource":"arn:aws:s3:::reports/*"}]}`,
+			catalog:  catalog{},
+			want:     []string{"warning unknown-action"},
+		},
+		{
+			name:     "pattern that matches no action",
+			document: `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:Frob*","Resource":"arn:aws:s3:::reports/*"}]}`,
+			catalog:  catalog{},
+			want:     []string{"warning unknown-action"},
+		},
+		{
+			name:     "unknown service",
+			document: `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"foo:Bar","Resource":"*"}]}`,
+			catalog:  catalog{},
+			want:     []string{"warning all-resources", "warning unknown-action"},
+		},
+		{
+			name:     "unscoped action on all resources with a catalog",
+			document: `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:ListAllMyBuckets","Resource":"*"}]}`,
+			catalog:  catalog{},
+		},
+		{
+			name:     "unscoped action on all resources without a catalog",
+			document: `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:ListAllMyBuckets","Resource":"*"}]}`,
+			want:     []string{"warning all-resources"},
+		},
+		{
+			name:     "object action on a bucket",
+			document: `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"arn:aws:s3:::reports"}]}`,
+			catalog:  catalog{},
+			want:     []string{"warning resource-mismatch"},
+		},
+		{
+			name:     "action of another service on a role",
+			document: `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"sts:AssumeRole","Resource":"arn:aws:iam::123456789012:role/deploy"}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := rules(Lint(policy(t, tt.document), tt.catalog))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Lint() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLintStatementIds(t *testing.T) {
+	p := policy(t, `{"Version":"2012-10-17","Statement":[
+		{"Effect":"Allow","Action":"s3:GetObject","Resource":"arn:aws:s3:::reports/*"},
+		{"Sid":"Admin","Effect":"Allow","Action":"*","Resource":"*"}
+	]}`)
+
+	findings := Lint(p, nil)
+	if len(findings) != 1 || findings[0].Statement != "Admin" {
+		t.Fatalf("Lint() = %v, want a single finding for statement Admin", findings)
+	}
+	if !Blocking(findings) {
+		t.Errorf("Blocking(%v) = false, want true", findings)
+	}
+
+	p = policy(t, `{"Version":"2012-10-17","Statement":{"Effect":"Allow","Action":"s3:*","Resource":"arn:aws:s3:::reports/*"}}`)
+	findings = Lint(p, nil)
+	if len(findings) != 1 || findings[0].Statement != "#1" {
+		t.Fatalf("Lint() = %v, want a single finding for statement #1", findings)
+	}
+	if Blocking(findings) {
+		t.Errorf("Blocking(%v) = true, want false", findings)
+	}
+}
+
+func TestFits(t *testing.T) {
+	tests := []struct {
+		name     string
+		actions  []string
+		resource string
+		catalog  Catalog
+		want     bool
+	}{
+		{name: "same service", actions: []string{"s3:GetObject"}, resource: "arn:aws:s3:::reports", want: true},
+		{name: "other service", actions: []string{"s3:GetObject"}, resource: "arn:aws:dynamodb:eu-west-1:123456789012:table/orders"},
+		{name: "related service", actions: []string{"sts:AssumeRole"}, resource: "arn:aws:iam::123456789012:role/deploy", want: true},
+		{name: "every action", actions: []string{"*"}, resource: "arn:aws:dynamodb:eu-west-1:123456789012:table/orders", want: true},
+		{name: "resource type of the catalog", actions: []string{"s3:GetObject"}, resource: "arn:aws:s3:::reports/2024", catalog: catalog{}, want: true},
+		{name: "other resource type of the catalog", actions: []string{"s3:GetObject"}, resource: "arn:aws:s3:::reports", catalog: catalog{}},
+		{name: "one of the actions applies", actions: []string{"s3:GetObject", "s3:ListBucket"}, resource: "arn:aws:s3:::reports", catalog: catalog{}, want: true},
+		{name: "wildcard resource compares services", actions: []string{"s3:GetObject"}, resource: "arn:aws:s3:::*", catalog: catalog{}, want: true},
+		{name: "action the catalog does not describe", actions: []string{"s3:GetObjekt"}, resource: "arn:aws:s3:::reports", catalog: catalog{}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fits(tt.catalog, tt.actions, tt.resource); got != tt.want {
+				t.Errorf("fits(%v, %s) = %v, want %v", tt.actions, tt.resource, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnscoped(t *testing.T) {
+	tests := []struct {
+		name    string
+		actions []string
+		catalog Catalog
+		want    bool
+	}{
+		{name: "without a catalog", actions: []string{"s3:ListAllMyBuckets"}},
+		{name: "unscoped action", actions: []string{"s3:ListAllMyBuckets"}, catalog: catalog{}, want: true},
+		{name: "scoped action", actions: []string{"s3:GetObject"}, catalog: catalog{}},
+		{name: "scoped and unscoped actions", actions: []string{"s3:ListAllMyBuckets", "s3:GetObject"}, catalog: catalog{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := unscoped(tt.catalog, tt.actions); got != tt.want {
+				t.Errorf("unscoped(%v) = %v, want %v", tt.actions, got, tt.want)
+			}
+		})
+	}
+}
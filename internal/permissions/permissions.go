@@ -0,0 +1,128 @@
+package permissions
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/Permify/targe/internal/ai"
+	"github.com/Permify/targe/internal/aws"
+)
+
+// EffectBoundary marks the actions a permissions boundary permits. The boundary grants nothing
+// itself, it caps what the other policies grant.
+const EffectBoundary = "Boundary"
+
+// Permission is a row of the merged statement table.
+type Permission struct {
+	Service   string   `json:"service"`
+	Action    string   `json:"action"`
+	Effect    string   `json:"effect"`
+	Resources []string `json:"resources"`
+	Condition string   `json:"condition,omitempty"`
+	// Sources are the policies that contain the statement, see aws.PolicySource.
+	Sources []string `json:"sources"`
+}
+
+// Merge parses the policies and merges their statements into one table sorted by service and
+// action. Statements with the same action, effect, resources and condition share a row that lists
+// every policy they come from. NotAction and NotResource entries are prefixed with "not ".
+func Merge(policies []aws.SourcedPolicy) ([]Permission, error) {
+	rows := map[string]*Permission{}
+	for _, sourced := range policies {
+		policy, err := ai.ParsePolicy(sourced.Document)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", sourced.Source, err)
+		}
+
+		for _, statement := range policy.Statement {
+			effect := statement.Effect
+			if sourced.Source.Kind == aws.SourceBoundary && effect == "Allow" {
+				effect = EffectBoundary
+			}
+
+			resources := values(statement.Resource, "")
+			if statement.NotResource != nil {
+				resources = values(statement.NotResource, "not ")
+			}
+
+			var condition string
+			if len(statement.Condition) > 0 {
+				encoded, err := json.Marshal(statement.Condition)
+				if err != nil {
+					return nil, err
+				}
+				condition = string(encoded)
+			}
+
+			actions := values(statement.Action, "")
+			if statement.NotAction != nil {
+				actions = values(statement.NotAction, "not ")
+			}
+
+			for _, action := range actions {
+				key := strings.Join([]string{action, effect, strings.Join(resources, ","), condition}, "|")
+				row, ok := rows[key]
+				if !ok {
+					row = &Permission{
+						Service:   Service(action),
+						Action:    action,
+						Effect:    effect,
+						Resources: resources,
+						Condition: condition,
+					}
+					rows[key] = row
+				}
+				if source := sourced.Source.String(); !slices.Contains(row.Sources, source) {
+					row.Sources = append(row.Sources, source)
+				}
+			}
+		}
+	}
+
+	permissions := make([]Permission, 0, len(rows))
+	for _, row := range rows {
+		permissions = append(permissions, *row)
+	}
+	sort.Slice(permissions, func(i, j int) bool {
+		a, b := permissions[i], permissions[j]
+		if a.Service != b.Service {
+			return a.Service < b.Service
+		}
+		if a.Action != b.Action {
+			return a.Action < b.Action
+		}
+		if a.Effect != b.Effect {
+			return a.Effect < b.Effect
+		}
+		return strings.Join(a.Resources, ",") < strings.Join(b.Resources, ",")
+	})
+	return permissions, nil
+}
+
+// Service returns the service prefix of an action, e.g. "s3" for "s3:GetObject", or "*".
+func Service(action string) string {
+	action = strings.TrimPrefix(action, "not ")
+	if service, _, ok := strings.Cut(action, ":"); ok {
+		return strings.ToLower(service)
+	}
+	return "*"
+}
+
+// values returns the entries of an Action or Resource element with the prefix, "*" for a wildcard.
+func values(ar *ai.IAMActionResource, prefix string) []string {
+	if ar == nil {
+		return nil
+	}
+	if ar.IsWildcard {
+		return []string{prefix + "*"}
+	}
+
+	var entries []string
+	for _, entry := range ar.Resources {
+		entries = append(entries, prefix+entry)
+	}
+	return entries
+}
@@ -0,0 +1,402 @@
+package aws
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Permify/targe/internal/ai"
+)
+
+// Access levels of an action in the service authorization reference.
+const (
+	AccessList        = "List"
+	AccessRead        = "Read"
+	AccessWrite       = "Write"
+	AccessPermissions = "Permissions management"
+	AccessTagging     = "Tagging"
+)
+
+// AccessLevels lists the access levels from the least to the most privileged.
+var AccessLevels = []string{AccessList, AccessRead, AccessWrite, AccessPermissions, AccessTagging}
+
+// referenceUrl lists the services of the service authorization reference and where their actions are described.
+const referenceUrl = "https://servicereference.us-east-1.amazonaws.com/"
+
+// snapshot is the catalog of the most used services, used until the full reference is installed.
+//
+//go:embed snapshot/actions.json
+var snapshot []byte
+
+type Actions struct{}
+
+func (a Actions) GetName() string {
+	return "aws::actions"
+}
+
+func (a Actions) GetFileName() string {
+	return "actions.json"
+}
+
+// errReferenceUnreachable is returned when the index of the service authorization reference cannot
+// be downloaded, usually because there is no network.
+var errReferenceUnreachable = errors.New("the service authorization reference is unreachable")
+
+// Install downloads the service authorization reference. Without network nothing is written and
+// the catalog falls back to the bundled snapshot. When a service fails to download, nothing is
+// written either and the error is returned.
+func (a Actions) Install() error {
+	services, err := a.getServices()
+	if errors.Is(err, errReferenceUnreachable) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("%w, the bundled snapshot of the most used services is used instead", err)
+	}
+	return writeServicesToJSONFile(Folder, a.GetFileName(), services)
+}
+
+// ServiceActions describes the actions of a service.
+type ServiceActions struct {
+	// Name is the service prefix of the actions, e.g. "s3".
+	Name          string         `json:"name"`
+	Actions       []Action       `json:"actions"`
+	Resources     []ResourceType `json:"resources"`
+	ConditionKeys []string       `json:"condition_keys"`
+}
+
+// Action is an action of a service.
+type Action struct {
+	Name        string `json:"name"`
+	AccessLevel string `json:"access_level"`
+	// Resources are the names of the resource types the action applies to, empty when it only
+	// applies to "*".
+	Resources     []string `json:"resources"`
+	ConditionKeys []string `json:"condition_keys,omitempty"`
+}
+
+// ResourceType is a type of resource of a service.
+type ResourceType struct {
+	Name string `json:"name"`
+	// Arns are the ARN formats of the resource, e.g. "arn:${Partition}:s3:::${BucketName}".
+	Arns []string `json:"arns"`
+}
+
+// referenceService is a service as the service authorization reference describes it.
+type referenceService struct {
+	Name    string `json:"Name"`
+	Actions []struct {
+		Name                string   `json:"Name"`
+		ActionConditionKeys []string `json:"ActionConditionKeys"`
+		Annotations         struct {
+			Properties struct {
+				IsList                 bool `json:"IsList"`
+				IsPermissionManagement bool `json:"IsPermissionManagement"`
+				IsTaggingOnly          bool `json:"IsTaggingOnly"`
+				IsWrite                bool `json:"IsWrite"`
+			} `json:"Properties"`
+		} `json:"Annotations"`
+		Resources []struct {
+			Name string `json:"Name"`
+		} `json:"Resources"`
+	} `json:"Actions"`
+	ConditionKeys []struct {
+		Name string `json:"Name"`
+	} `json:"ConditionKeys"`
+	Resources []struct {
+		Name       string   `json:"Name"`
+		ARNFormats []string `json:"ARNFormats"`
+	} `json:"Resources"`
+}
+
+// getServices downloads the actions of every service of the service authorization reference.
+func (a Actions) getServices() ([]ServiceActions, error) {
+	var index []struct {
+		Service string `json:"service"`
+		Url     string `json:"url"`
+	}
+	if err := getJSON(referenceUrl, &index); err != nil {
+		return nil, fmt.Errorf("%w: %w", errReferenceUnreachable, err)
+	}
+
+	services := make([]ServiceActions, len(index))
+	errs := make([]error, len(index))
+
+	// The services are downloaded by a few workers, the reference has hundreds of them
+	var wg sync.WaitGroup
+	jobs := make(chan int)
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				var service referenceService
+				if errs[i] = getJSON(index[i].Url, &service); errs[i] == nil {
+					services[i] = convertService(index[i].Service, service)
+				}
+			}
+		}()
+	}
+	for i := range index {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("failed to download %s: %w", index[i].Service, err)
+		}
+	}
+
+	return services, nil
+}
+
+// getJSON fetches the url and decodes the JSON response into v.
+func getJSON(url string, v any) error {
+	resp, err := http.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(body, v)
+}
+
+// convertService converts a service of the reference into the catalog format.
+func convertService(prefix string, service referenceService) ServiceActions {
+	converted := ServiceActions{Name: prefix}
+	for _, action := range service.Actions {
+		properties := action.Annotations.Properties
+
+		level := AccessRead
+		switch {
+		case properties.IsPermissionManagement:
+			level = AccessPermissions
+		case properties.IsTaggingOnly:
+			level = AccessTagging
+		case properties.IsWrite:
+			level = AccessWrite
+		case properties.IsList:
+			level = AccessList
+		}
+
+		item := Action{Name: action.Name, AccessLevel: level, Resources: []string{}, ConditionKeys: action.ActionConditionKeys}
+		for _, resource := range action.Resources {
+			item.Resources = append(item.Resources, resource.Name)
+		}
+		converted.Actions = append(converted.Actions, item)
+	}
+	for _, resource := range service.Resources {
+		converted.Resources = append(converted.Resources, ResourceType{Name: resource.Name, Arns: resource.ARNFormats})
+	}
+	for _, key := range service.ConditionKeys {
+		converted.ConditionKeys = append(converted.ConditionKeys, key.Name)
+	}
+	return converted
+}
+
+// GetServices reads the installed catalog, or the bundled snapshot when the reference has not
+// been installed. The second value reports whether the snapshot was used.
+func (a Actions) GetServices() ([]ServiceActions, bool, error) {
+	data, err := os.ReadFile(Folder + "/" + a.GetFileName())
+	bundled := os.IsNotExist(err)
+	if bundled {
+		data, err = snapshot, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	var services []ServiceActions
+	if err := json.NewDecoder(bytes.NewReader(data)).Decode(&services); err != nil {
+		return nil, false, err
+	}
+	return services, bundled, nil
+}
+
+// Catalog looks up the actions of the services. It satisfies lint.Catalog.
+type Catalog struct {
+	services map[string]ServiceActions
+	// partial is set for the bundled snapshot, which does not describe every service.
+	partial bool
+}
+
+// LoadCatalog loads the catalog once, see Actions.GetServices.
+var LoadCatalog = sync.OnceValues(func() (*Catalog, error) {
+	services, partial, err := Actions{}.GetServices()
+	if err != nil {
+		return nil, err
+	}
+	return NewCatalog(services, partial), nil
+})
+
+// NewCatalog indexes the services by prefix. A partial catalog does not consider services it does
+// not describe unknown.
+func NewCatalog(services []ServiceActions, partial bool) *Catalog {
+	c := &Catalog{services: map[string]ServiceActions{}, partial: partial}
+	for _, service := range services {
+		c.services[strings.ToLower(service.Name)] = service
+	}
+	return c
+}
+
+// Services returns the services of the catalog sorted by prefix.
+func (c *Catalog) Services() []ServiceActions {
+	var services []ServiceActions
+	for _, service := range c.services {
+		services = append(services, service)
+	}
+	sort.Slice(services, func(i, j int) bool {
+		return services[i].Name < services[j].Name
+	})
+	return services
+}
+
+// Service returns the service with the prefix.
+func (c *Catalog) Service(prefix string) (ServiceActions, bool) {
+	service, ok := c.services[strings.ToLower(prefix)]
+	return service, ok
+}
+
+// HasService reports whether the service prefix exists.
+func (c *Catalog) HasService(prefix string) bool {
+	_, ok := c.Service(prefix)
+	return ok || c.partial
+}
+
+// HasAction reports whether the action exists, or for a pattern such as "s3:Get*" whether it
+// matches any action. Actions of services the catalog does not describe are assumed to exist.
+func (c *Catalog) HasAction(action string) bool {
+	prefix, _, _ := strings.Cut(action, ":")
+	if _, ok := c.Service(prefix); !ok {
+		return true
+	}
+	return len(c.Expand(action)) > 0
+}
+
+// Expand returns the actions that match the pattern, "*" matches the actions of every service.
+func (c *Catalog) Expand(pattern string) []string {
+	var expanded []string
+	for _, service := range c.Services() {
+		for _, action := range service.Actions {
+			name := service.Name + ":" + action.Name
+			if ai.MatchAction(pattern, name) {
+				expanded = append(expanded, name)
+			}
+		}
+	}
+	return expanded
+}
+
+// Action returns the action, e.g. "s3:GetObject", and the service it belongs to.
+func (c *Catalog) Action(action string) (Action, ServiceActions, bool) {
+	prefix, name, _ := strings.Cut(action, ":")
+	service, ok := c.Service(prefix)
+	if !ok {
+		return Action{}, service, false
+	}
+	for _, a := range service.Actions {
+		if strings.EqualFold(a.Name, name) {
+			return a, service, true
+		}
+	}
+	return Action{}, service, false
+}
+
+// ResourceArns returns the ARN formats of the resource types the action applies to. The second
+// value is false when the catalog does not describe the action.
+func (c *Catalog) ResourceArns(action string) ([]string, bool) {
+	a, service, ok := c.Action(action)
+	if !ok {
+		return nil, false
+	}
+
+	var arns []string
+	for _, resource := range service.Resources {
+		for _, name := range a.Resources {
+			if resource.Name == name {
+				arns = append(arns, resource.Arns...)
+			}
+		}
+	}
+	return arns, true
+}
+
+// Unscoped reports whether the action applies to no resource type, so it is allowed on "*".
+func (c *Catalog) Unscoped(action string) bool {
+	arns, ok := c.ResourceArns(action)
+	return ok && len(arns) == 0
+}
+
+// arnVariable matches the variables of an ARN format, e.g. "${BucketName}".
+var arnVariable = regexp.MustCompile(`\$\{[^}]*\}`)
+
+// MatchArn reports whether the ARN has the format, "arn:aws:s3:::reports" has the format
+// "arn:${Partition}:s3:::${BucketName}".
+func MatchArn(format, arn string) bool {
+	literals := arnVariable.Split(format, -1)
+	for i, literal := range literals {
+		literals[i] = regexp.QuoteMeta(literal)
+	}
+	matched, err := regexp.MatchString("^"+strings.Join(literals, ".*")+"$", arn)
+	return err == nil && matched
+}
+
+// AppliesTo reports whether the action applies to the resource ARN. The second value is false
+// when the catalog does not describe the action.
+func (c *Catalog) AppliesTo(action, arn string) (bool, bool) {
+	arns, ok := c.ResourceArns(action)
+	if !ok {
+		return false, false
+	}
+	for _, format := range arns {
+		if MatchArn(format, arn) {
+			return true, true
+		}
+	}
+	return false, true
+}
+
+// servicePrefixes maps the CloudFormation namespaces whose service prefix differs from their
+// lowercased name.
+var servicePrefixes = map[string]string{
+	"ApiGatewayV2":           "apigateway",
+	"CertificateManager":     "acm",
+	"Cognito":                "cognito-idp",
+	"ElasticLoadBalancingV2": "elasticloadbalancing",
+	"Elasticsearch":          "es",
+	"OpenSearchService":      "es",
+	"StepFunctions":          "states",
+	"KinesisFirehose":        "firehose",
+}
+
+// ServicePrefix returns the service prefix of the actions on a CloudFormation resource type,
+// "s3" for "AWS::S3::Bucket".
+func ServicePrefix(typeName string) string {
+	parts := strings.Split(typeName, "::")
+	if len(parts) < 2 {
+		return strings.ToLower(typeName)
+	}
+	if prefix, ok := servicePrefixes[parts[1]]; ok {
+		return prefix
+	}
+	return strings.ToLower(parts[1])
+}
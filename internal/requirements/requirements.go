@@ -13,6 +13,7 @@ type Requirement interface {
 var requirements = []Requirement{
 	aws.Types{},
 	aws.ManagedPolicies{},
+	aws.Actions{},
 }
 
 func GetRequirements() []Requirement {
@@ -2,28 +2,48 @@ package groups
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"slices"
+	"strings"
+	"time"
 
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
 
+	"github.com/Permify/targe/internal/ai"
+	"github.com/Permify/targe/internal/audit"
 	"github.com/Permify/targe/internal/aws"
+	"github.com/Permify/targe/internal/journal"
+	"github.com/Permify/targe/internal/lease"
+	"github.com/Permify/targe/internal/lint"
 	requirements "github.com/Permify/targe/internal/requirements/aws"
 	"github.com/Permify/targe/pkg/aws/models"
+	"github.com/Permify/targe/pkg/aws/policyforms"
 )
 
 type Controller struct {
-	api          *aws.Api
-	openAiApiKey string
-	State        *State
+	api      *aws.Api
+	provider ai.Provider
+	State    *State
+	// DryRun limits the flow to previewing the IAM calls without applying them.
+	DryRun bool
+	// Force applies custom policies even when the linter reports errors.
+	Force bool
+	// Accounts applies the operation to each of the accounts instead of the current one.
+	Accounts []aws.Account
+	results  []aws.AccountResult
+	// notCreated are the accounts the group created with the "Create new" entry is missing from.
+	notCreated []aws.AccountResult
 }
 
-func NewController(api *aws.Api, openaiApiKey string, state *State) *Controller {
+func NewController(api *aws.Api, provider ai.Provider, state *State) *Controller {
 	return &Controller{
-		api:          api,
-		openAiApiKey: openaiApiKey,
-		State:        state,
+		api:      api,
+		provider: provider,
+		State:    state,
 	}
 }
 
@@ -32,28 +52,139 @@ type FailedMsg struct {
 	Err error
 }
 
-type GroupLoadedMsg struct{ List []list.Item }
+// GroupLoadedMsg carries a page of groups. Next loads the following page and is nil on the last one.
+type GroupLoadedMsg struct {
+	List []list.Item
+	Next tea.Cmd
+}
 
-// LoadGroups loads groups from the AWS API.
+// LoadGroups loads groups from the AWS API page by page. The first page starts with the entry
+// that creates a new group, unless the flow only previews its calls.
 func (c *Controller) LoadGroups() tea.Cmd {
+	load := c.loadGroupsPage(c.api.GroupPager())
+	return func() tea.Msg {
+		msg := load()
+		if loaded, ok := msg.(GroupLoadedMsg); ok && !c.DryRun {
+			loaded.List = append([]list.Item{models.CreateNew{Kind: "group"}}, loaded.List...)
+			return loaded
+		}
+		return msg
+	}
+}
+
+// GroupCreatedMsg carries the group created with the "Create new" entry.
+type GroupCreatedMsg struct {
+	Group models.Group
+}
+
+func (c *Controller) loadGroupsPage(pager *aws.Pager[types.Group]) tea.Cmd {
 	return func() tea.Msg {
 		var items []list.Item
-		output, err := c.api.ListGroups(context.Background())
+
+		groups, err := pager.NextPage(context.Background())
 		if err != nil {
 			return FailedMsg{Err: err}
 		}
 
-		for _, group := range output.Groups {
+		for _, group := range groups {
 			items = append(items, models.Group{
 				Name: *group.GroupName,
 				Arn:  *group.Arn,
 			})
 		}
 
-		return GroupLoadedMsg{List: items}
+		msg := GroupLoadedMsg{List: items}
+		if pager.HasMorePages() {
+			msg.Next = c.loadGroupsPage(pager)
+		}
+		return msg
 	}
 }
 
+// CreateGroup creates the group in the current account, or in each of the accounts, and records it.
+func (c *Controller) CreateGroup(name, path string) tea.Cmd {
+	return func() tea.Msg {
+		var created *types.Group
+		failed, err := c.create(CreateGroupOperation, name, func(api *aws.Api) (aws.Call, error) {
+			group, err := api.CreateGroup(context.Background(), name, path)
+			if err != nil {
+				return aws.Call{}, err
+			}
+			if created == nil {
+				created = group
+			}
+			return aws.NewCall(aws.CreateGroupAction, "GroupName", name, "Path", path), nil
+		})
+		if created == nil {
+			return FailedMsg{Err: err}
+		}
+
+		// The flow continues with the group even when it could not be created in every account, the
+		// overview lists the accounts it is missing from
+		c.notCreated = failed
+		return GroupCreatedMsg{Group: models.Group{Name: *created.GroupName, Arn: *created.Arn}}
+	}
+}
+
+// create runs the creation of a group in the current account, or in each of the accounts. Every
+// creation is journaled, so it can be undone, and written to the audit log. The accounts the
+// creation failed in are returned with their error.
+func (c *Controller) create(operation, name string, create func(api *aws.Api) (aws.Call, error)) ([]aws.AccountResult, error) {
+	accounts := c.Accounts
+	if len(accounts) == 0 {
+		accounts = []aws.Account{{Api: c.api}}
+	}
+
+	var errs []error
+	var failed []aws.AccountResult
+	for _, account := range accounts {
+		var applied []aws.Call
+		call, err := create(account.Api)
+		if err == nil {
+			applied = append(applied, call)
+			_, jerr := journal.Append(journal.Entry{
+				Flow:      "groups",
+				Operation: operation,
+				Principal: name,
+				Inverse:   aws.Inverse(applied),
+				RoleArn:   account.RoleArn,
+			})
+			if jerr != nil {
+				err = fmt.Errorf("operation could not be recorded for undo: %w", jerr)
+			}
+		}
+
+		entry := audit.Entry{
+			Caller:    "unknown",
+			Flow:      "groups",
+			Operation: operation,
+			Principal: name,
+			Calls:     applied,
+			Outcome:   audit.OutcomeSuccess,
+		}
+		if arn, id, ierr := account.Api.CallerIdentity(context.Background()); ierr == nil {
+			entry.Caller, entry.Account = arn, id
+		}
+		if err != nil {
+			entry.Outcome = audit.OutcomeFailure
+			entry.Error = err.Error()
+		}
+		if aerr := audit.Record(entry); aerr != nil {
+			err = errors.Join(err, aerr)
+		}
+
+		if err != nil {
+			failed = append(failed, aws.AccountResult{Account: account, Err: err})
+			if account.Id != "" {
+				err = fmt.Errorf("account %s: %w", account.Id, err)
+			}
+			errs = append(errs, err)
+		}
+	}
+
+	return failed, errors.Join(errs...)
+}
+
 type OperationLoadedMsg struct{ List []list.Item }
 
 // LoadOperations loads operations.
@@ -63,6 +194,9 @@ func (c *Controller) LoadOperations() tea.Cmd {
 			models.Operation{Id: AttachPolicySlug.String(), Name: ReachableOperations[AttachPolicySlug].Name, Desc: ReachableOperations[AttachPolicySlug].Desc},
 			models.Operation{Id: DetachPolicySlug.String(), Name: ReachableOperations[DetachPolicySlug].Name, Desc: ReachableOperations[DetachPolicySlug].Desc},
 			models.Operation{Id: AttachCustomPolicySlug.String(), Name: ReachableOperations[AttachCustomPolicySlug].Name, Desc: ReachableOperations[AttachCustomPolicySlug].Desc},
+			models.Operation{Id: EditPolicySlug.String(), Name: ReachableOperations[EditPolicySlug].Name, Desc: ReachableOperations[EditPolicySlug].Desc},
+			models.Operation{Id: AttachInlinePolicySlug.String(), Name: ReachableOperations[AttachInlinePolicySlug].Name, Desc: ReachableOperations[AttachInlinePolicySlug].Desc},
+			models.Operation{Id: RemoveInlinePolicySlug.String(), Name: ReachableOperations[RemoveInlinePolicySlug].Name, Desc: ReachableOperations[RemoveInlinePolicySlug].Desc},
 		}
 		return OperationLoadedMsg{List: items}
 	}
@@ -91,102 +225,141 @@ func (c *Controller) LoadServices() tea.Cmd {
 	}
 }
 
-type ResourceLoadedMsg struct{ List []list.Item }
+// ResourceLoadedMsg carries a page of resources. Next loads the following page and is nil on the last one.
+type ResourceLoadedMsg struct {
+	List []list.Item
+	Next tea.Cmd
+}
 
-// LoadResources loads resources.
+// LoadResources loads the resources of the selected service page by page, narrowed down by the resource query.
 func (c *Controller) LoadResources() tea.Cmd {
 	return func() tea.Msg {
-		items := []list.Item{
-			models.Resource{Name: "All Resources", Arn: "*"},
+		query := aws.ResourceQuery{Type: c.State.GetService().Name}
+		if q := c.State.GetResourceQuery(); q != nil {
+			query.Text, query.Tags = q.Text, q.Tags
 		}
 
-		resources, err := c.api.ListResources(c.State.GetService().Name)
+		pager, err := c.api.ResourcePager(context.Background(), query)
 		if err != nil {
 			return FailedMsg{Err: err}
 		}
 
-		for _, resource := range resources {
-			items = append(items, models.Resource{
-				Name: resource.Name,
-				Arn:  resource.Arn,
-			})
+		msg := c.loadResourcesPage(pager)()
+		if loaded, ok := msg.(ResourceLoadedMsg); ok {
+			loaded.List = append([]list.Item{models.Resource{Name: "All Resources", Arn: "*"}}, loaded.List...)
+			return loaded
 		}
-
-		return ResourceLoadedMsg{List: items}
+		return msg
 	}
 }
 
-type PolicyLoadedMsg struct{ List []list.Item }
-
-// LoadPolicies loads policies.
-func (c *Controller) LoadPolicies() tea.Cmd {
+func (c *Controller) loadResourcesPage(pager *aws.Pager[aws.Resource]) tea.Cmd {
 	return func() tea.Msg {
 		var items []list.Item
 
-		policies, err := c.api.ListPolicies(context.Background())
+		resources, err := pager.NextPage(context.Background())
 		if err != nil {
 			return FailedMsg{Err: err}
 		}
 
-		mp := requirements.ManagedPolicies{}
-		managedPolicies, err := mp.GetPolicies()
-		if err != nil {
-			return FailedMsg{Err: err}
+		for _, resource := range resources {
+			items = append(items, models.Resource{
+				Name:   resource.Name,
+				Arn:    resource.Arn,
+				Region: resource.Region,
+				Tags:   resource.Tags,
+			})
 		}
 
-		attachedPolicies, err := c.api.ListAttachedGroupPolicies(context.Background(), c.State.group.Name)
-		if err != nil {
-			return FailedMsg{Err: err}
+		msg := ResourceLoadedMsg{List: items}
+		if pager.HasMorePages() {
+			msg.Next = c.loadResourcesPage(pager)
 		}
+		return msg
+	}
+}
 
-		switch c.State.operation.Id {
-		case AttachPolicySlug.String():
-			for _, policy := range policies.Policies {
-				if !slices.Contains(attachedPolicies, *policy.PolicyName) {
-					items = append(items, models.Policy{
-						Name: *policy.PolicyName,
-						Arn:  *policy.Arn,
-					})
-				}
-			}
+// PolicyLoadedMsg carries a page of policies. Next loads the following page and is nil on the last one.
+type PolicyLoadedMsg struct {
+	List []list.Item
+	Next tea.Cmd
+}
 
-			for _, policy := range managedPolicies {
-				if !slices.Contains(attachedPolicies, policy.Name) {
-					items = append(items, models.Policy{
-						Name: policy.Name,
-						Arn:  policy.Arn,
-					})
-				}
-			}
-		case DetachPolicySlug.String():
+// LoadPolicies loads policies. Customer managed policies are streamed page by page,
+// AWS managed policies are added with the last page. Removing an inline policy lists
+// the inline policies of the group instead.
+func (c *Controller) LoadPolicies() tea.Cmd {
+	return func() tea.Msg {
+		if c.State.operation.Id == RemoveInlinePolicySlug.String() {
 			inlinePolicies, err := c.api.ListGroupInlinePolicies(context.Background(), c.State.group.Name)
 			if err != nil {
 				return FailedMsg{Err: err}
 			}
 
+			var items []list.Item
 			for _, name := range inlinePolicies {
 				items = append(items, models.Policy{
-					Name: name,
-					Arn:  "inline",
+					Name:   name,
+					Inline: true,
 				})
 			}
+			return PolicyLoadedMsg{List: items}
+		}
 
-			for _, policy := range policies.Policies {
-				if slices.Contains(attachedPolicies, *policy.PolicyName) {
-					items = append(items, models.Policy{
-						Name: *policy.PolicyName,
-						Arn:  *policy.Arn,
-					})
-				}
+		attachedPolicies, err := c.api.ListAttachedGroupPolicies(context.Background(), c.State.group.Name)
+		if err != nil {
+			return FailedMsg{Err: err}
+		}
+
+		return c.loadPoliciesPage(c.api.PolicyPager(), attachedPolicies)()
+	}
+}
+
+func (c *Controller) loadPoliciesPage(pager *aws.Pager[types.Policy], attachedPolicies []string) tea.Cmd {
+	return func() tea.Msg {
+		var items []list.Item
+
+		// Attach offers the policies that are not attached yet, detach and edit the attached ones
+		attached := c.State.operation.Id == DetachPolicySlug.String() || c.State.operation.Id == EditPolicySlug.String()
+		offered := func(name string) bool {
+			return slices.Contains(attachedPolicies, name) == attached
+		}
+
+		policies, err := pager.NextPage(context.Background())
+		if err != nil {
+			return FailedMsg{Err: err}
+		}
+
+		for _, policy := range policies {
+			if offered(*policy.PolicyName) {
+				items = append(items, models.Policy{
+					Name: *policy.PolicyName,
+					Arn:  *policy.Arn,
+				})
 			}
+		}
 
-			for _, policy := range managedPolicies {
-				if slices.Contains(attachedPolicies, policy.Name) {
-					items = append(items, models.Policy{
-						Name: policy.Name,
-						Arn:  policy.Arn,
-					})
-				}
+		if pager.HasMorePages() {
+			return PolicyLoadedMsg{List: items, Next: c.loadPoliciesPage(pager, attachedPolicies)}
+		}
+
+		// AWS managed policies cannot be edited
+		if c.State.operation.Id == EditPolicySlug.String() {
+			return PolicyLoadedMsg{List: items}
+		}
+
+		mp := requirements.ManagedPolicies{}
+		managedPolicies, err := mp.GetPolicies()
+		if err != nil {
+			return FailedMsg{Err: err}
+		}
+
+		for _, policy := range managedPolicies {
+			if offered(policy.Name) {
+				items = append(items, models.Policy{
+					Name: policy.Name,
+					Arn:  policy.Arn,
+				})
 			}
 		}
 
@@ -194,19 +367,231 @@ func (c *Controller) LoadPolicies() tea.Cmd {
 	}
 }
 
+// InspectPolicy loads the versions of the selected policy and the document of its default version into the state.
+func (c *Controller) InspectPolicy(ctx context.Context) error {
+	policy := c.State.GetPolicy()
+	if strings.Contains(policy.Arn, ":aws:policy/") {
+		return fmt.Errorf("%s is an AWS managed policy, only customer managed policies can be edited", policy.Name)
+	}
+
+	versions, err := c.api.ListPolicyVersions(ctx, policy.Arn)
+	if err != nil {
+		return fmt.Errorf("failed to list the versions of %s: %w", policy.Name, err)
+	}
+
+	defaultVersion := aws.DefaultPolicyVersion(versions)
+	document, err := c.api.GetPolicyDocument(ctx, policy.Arn, defaultVersion)
+	if err != nil {
+		return fmt.Errorf("failed to read the default version of %s: %w", policy.Name, err)
+	}
+
+	c.State.SetPolicyVersions(&models.PolicyVersions{
+		Default:  defaultVersion,
+		Document: document,
+		Oldest:   aws.OldestPolicyVersion(versions),
+		Count:    len(versions),
+	})
+	return nil
+}
+
 type PolicyOptionLoadedMsg struct{ List []list.Item }
 
+// SimulationLoadedMsg signals that the policy simulation has run.
+type SimulationLoadedMsg struct{}
+
+// LoadSimulation runs the policy simulator for the selected policy.
+func (c *Controller) LoadSimulation() tea.Cmd {
+	return func() tea.Msg {
+		if err := c.Simulate(context.Background()); err != nil {
+			return FailedMsg{Err: fmt.Errorf("access was not simulated: %w", err)}
+		}
+		return SimulationLoadedMsg{}
+	}
+}
+
+// Simulate runs the policy simulator for the actions the selected policy allows, on the selected
+// resources if there are any. A new policy document is simulated on its own with SimulateCustomPolicy.
+// An existing policy is simulated with SimulatePrincipalPolicy as if it were attached to the group,
+// so the other policies of the group and service control policies apply too.
+func (c *Controller) Simulate(ctx context.Context) error {
+	if !c.attaching() {
+		return nil
+	}
+
+	// Custom policies do not exist yet, their document is in the state
+	document := c.policyDocument()
+	custom := document != ""
+	if !custom {
+		var err error
+		if document, err = c.api.GetDefaultPolicyDocument(ctx, c.State.policy.Arn); err != nil {
+			return fmt.Errorf("failed to read %s: %w", c.State.policy.Name, err)
+		}
+	}
+
+	policy, err := ai.ParsePolicy(document)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", c.State.policy.Name, err)
+	}
+
+	actions := policy.SimulatableActions()
+	if len(actions) == 0 {
+		c.State.SetSimulation([]aws.Simulation{})
+		return nil
+	}
+
+	resources := c.resourceArns()
+
+	var simulation []aws.Simulation
+	if custom {
+		simulation, err = c.api.SimulateCustomPolicy(ctx, []string{document}, nil, actions, resources)
+	} else {
+		simulation, err = c.api.SimulatePrincipalPolicy(ctx, c.State.GetGroup().Arn, actions, resources, []string{document})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to simulate %s: %w", c.State.policy.Name, err)
+	}
+
+	c.State.SetSimulation(simulation)
+	return nil
+}
+
+// attaching reports whether the operation grants the selected policy to the group.
+func (c *Controller) attaching() bool {
+	if c.State.operation == nil || c.State.policy == nil {
+		return false
+	}
+	switch c.State.operation.Id {
+	case AttachPolicySlug.String(), AttachCustomPolicySlug.String(), AttachInlinePolicySlug.String():
+		return true
+	default:
+		return false
+	}
+}
+
+// Findings lints the custom policy document the operation writes.
+func (c *Controller) Findings() []lint.Finding {
+	if c.State.operation == nil || c.State.policy == nil || c.State.policy.Document == "" {
+		return nil
+	}
+	switch c.State.operation.Id {
+	case AttachCustomPolicySlug.String(), AttachInlinePolicySlug.String(), EditPolicySlug.String():
+	default:
+		return nil
+	}
+
+	policy, err := ai.ParsePolicy(c.State.policy.Document)
+	if err != nil {
+		return nil
+	}
+
+	// Actions are checked against the catalog when it can be loaded
+	var catalog lint.Catalog
+	if loaded, err := requirements.LoadCatalog(); err == nil {
+		catalog = loaded
+	}
+	return lint.Lint(policy, catalog)
+}
+
+// CatalogService returns the actions of the selected service from the action catalog.
+func (c *Controller) CatalogService() (requirements.ServiceActions, error) {
+	catalog, err := requirements.LoadCatalog()
+	if err != nil {
+		return requirements.ServiceActions{}, err
+	}
+
+	prefix := requirements.ServicePrefix(c.State.service.Name)
+	service, ok := catalog.Service(prefix)
+	if !ok {
+		return service, fmt.Errorf("the action catalog does not describe %s, install the requirements with network access to get every service", prefix)
+	}
+	return service, nil
+}
+
+// BuildPolicy creates the custom policy from the actions picked in the builder, the selected
+// resources and the conditions, see ai.ParseConditions. Actions that do not apply to a resource
+// type are allowed on "*" instead of the resources.
+func (c *Controller) BuildPolicy(name, conditions string) error {
+	condition, err := ai.ParseConditions(conditions)
+	if err != nil {
+		return err
+	}
+
+	resources := c.resourceArns()
+	if len(resources) == 0 {
+		resources = []string{"*"}
+	}
+
+	var actions, unscoped []string
+	catalog, _ := requirements.LoadCatalog()
+	for _, action := range c.State.actions {
+		if catalog != nil && !slices.Contains(resources, "*") && catalog.Unscoped(action) {
+			unscoped = append(unscoped, action)
+		} else {
+			actions = append(actions, action)
+		}
+	}
+
+	document, err := json.MarshalIndent(ai.BuildPolicy(name, actions, resources, unscoped, condition), "", "\t")
+	if err != nil {
+		return err
+	}
+
+	c.SetCustomPolicy(name, string(document))
+	return nil
+}
+
+// SetCustomPolicy selects a new policy with the document, inline when the operation attaches an inline policy.
+func (c *Controller) SetCustomPolicy(name, document string) {
+	c.State.SetPolicy(&models.Policy{
+		Arn:      "new",
+		Name:     name,
+		Document: document,
+		Inline:   c.State.operation.Id == AttachInlinePolicySlug.String(),
+	})
+}
+
+// resourceArns returns the ARNs of the selected resources.
+func (c *Controller) resourceArns() []string {
+	var arns []string
+	for _, resource := range c.State.resources {
+		arns = append(arns, resource.Arn)
+	}
+	return arns
+}
+
+// Principal returns the kind, the name and the ARN of the selected group for the titles of the policy forms.
+func (c *Controller) Principal() (kind, name, arn string) {
+	if c.State.group == nil {
+		return "Group", "", ""
+	}
+	return "Group", c.State.group.Name, c.State.group.Arn
+}
+
+// Provider returns the AI provider, nil when none is configured.
+func (c *Controller) Provider() ai.Provider {
+	return c.provider
+}
+
 // LoadPolicyOptions loads operations.
 func (c *Controller) LoadPolicyOptions() tea.Cmd {
 	return func() tea.Msg {
 		items := []list.Item{
 			models.PolicyOption{Id: WithoutResourceSlug.String(), Name: ReachablePolicyOptions[WithoutResourceSlug].Name, Desc: ReachablePolicyOptions[WithoutResourceSlug].Desc},
 			models.PolicyOption{Id: WithResourceSlug.String(), Name: ReachablePolicyOptions[WithResourceSlug].Name, Desc: ReachablePolicyOptions[WithResourceSlug].Desc},
+			models.PolicyOption{Id: BuildSlug.String(), Name: ReachablePolicyOptions[BuildSlug].Name, Desc: ReachablePolicyOptions[BuildSlug].Desc},
+		}
+
+		// Policies are only generated when an AI provider is configured, the builder works without one
+		if c.provider == nil {
+			items = items[2:]
 		}
 		return PolicyOptionLoadedMsg{List: items}
 	}
 }
 
+// CreateGroupOperation is the operation recorded in the journal and the audit log when a group is created.
+const CreateGroupOperation = "create_group"
+
 type OperationType string
 
 // Constants representing group actions and their slugs
@@ -214,12 +599,18 @@ const (
 	AttachPolicySlug       OperationType = "attach_policy"
 	DetachPolicySlug       OperationType = "detach_policy"
 	AttachCustomPolicySlug OperationType = "attach_custom_policy"
+	EditPolicySlug         OperationType = "edit_policy"
+	AttachInlinePolicySlug OperationType = "attach_inline_policy"
+	RemoveInlinePolicySlug OperationType = "remove_inline_policy"
 )
 
 func (o OperationType) String() string {
 	return string(o)
 }
 
+// TemporaryOperations lists the operations that can grant access for a limited time.
+var TemporaryOperations = []OperationType{AttachPolicySlug, AttachCustomPolicySlug, AttachInlinePolicySlug}
+
 // ReachableOperations Predefined list of actions with their names and descriptions
 var ReachableOperations = map[OperationType]models.Operation{
 	AttachPolicySlug: {
@@ -237,6 +628,21 @@ var ReachableOperations = map[OperationType]models.Operation{
 		Name: "Attach Custom Policy (attach_custom_policy)",
 		Desc: "Create and attach a custom policy.",
 	},
+	EditPolicySlug: {
+		Id:   EditPolicySlug.String(),
+		Name: "Edit Existing Policy (edit_policy)",
+		Desc: "Change an attached customer managed policy.",
+	},
+	AttachInlinePolicySlug: {
+		Id:   AttachInlinePolicySlug.String(),
+		Name: "Attach Inline Policy (attach_inline_policy)",
+		Desc: "Embed a custom policy in the group.",
+	},
+	RemoveInlinePolicySlug: {
+		Id:   RemoveInlinePolicySlug.String(),
+		Name: "Remove Inline Policy (remove_inline_policy)",
+		Desc: "Delete an inline policy from the group.",
+	},
 }
 
 type PolicyOptionType string
@@ -245,6 +651,7 @@ type PolicyOptionType string
 const (
 	WithoutResourceSlug PolicyOptionType = "without_resource"
 	WithResourceSlug    PolicyOptionType = "with_resource"
+	BuildSlug           PolicyOptionType = "build"
 )
 
 func (o PolicyOptionType) String() string {
@@ -263,6 +670,11 @@ var ReachablePolicyOptions = map[PolicyOptionType]models.PolicyOption{
 		Name: "With Resource (with_resource)",
 		Desc: "Scoped to a specific resource.",
 	},
+	BuildSlug: {
+		Id:   BuildSlug.String(),
+		Name: "Build Manually (build)",
+		Desc: "Pick the actions, resource and conditions yourself.",
+	},
 }
 
 // Next determines the next step based on the current state.
@@ -277,8 +689,8 @@ func (c *Controller) Next() tea.Model {
 		return NewOperationList(c)
 	}
 
-	// Handle specific action: AttachCustomPolicySlug
-	if c.State.operation.Id == AttachCustomPolicySlug.String() {
+	// Handle custom policies, created as managed or inline policies
+	if c.State.operation.Id == AttachCustomPolicySlug.String() || c.State.operation.Id == AttachInlinePolicySlug.String() {
 
 		if c.State.policy != nil {
 			return NewResult(c)
@@ -288,12 +700,12 @@ func (c *Controller) Next() tea.Model {
 		if c.State.policyOption != nil {
 			switch c.State.policyOption.Id {
 			case WithoutResourceSlug.String():
-				return NewCreatePolicy(c)
+				return policyforms.NewCreatePolicy(c, c.State)
 
 			case WithResourceSlug.String():
-				// Handle case where resource is defined
-				if c.State.resource != nil {
-					return NewCreatePolicy(c)
+				// Handle case where resources are defined
+				if len(c.State.resources) > 0 {
+					return policyforms.NewCreatePolicy(c, c.State)
 				}
 
 				// Handle case where service is defined
@@ -302,11 +714,22 @@ func (c *Controller) Next() tea.Model {
 				}
 				// If service is not defined
 				return NewServiceList(c)
+
+			case BuildSlug.String():
+				if c.State.service == nil {
+					return NewServiceList(c)
+				}
+
+				// The actions of the service are picked before the resources, the conditions after them
+				if len(c.State.actions) == 0 || len(c.State.resources) > 0 {
+					return policyforms.NewPolicyBuilder(c, c.State)
+				}
+				return NewResourceList(c)
 			}
 		} else {
-			// Handle case where resource is defined
-			if c.State.resource != nil {
-				return NewCreatePolicy(c)
+			// Handle case where resources are defined
+			if len(c.State.resources) > 0 {
+				return policyforms.NewCreatePolicy(c, c.State)
 			}
 
 			// Handle case where service is defined
@@ -323,27 +746,391 @@ func (c *Controller) Next() tea.Model {
 		return NewPolicyList(c)
 	}
 
+	// Handle case where the change to the selected policy is not described yet
+	if c.State.operation.Id == EditPolicySlug.String() && c.State.policy.Document == "" {
+		return policyforms.NewEditPolicy(c, c.State)
+	}
+
 	// Default fallback
 	return NewResult(c)
 }
 
+// Done applies the operation, records its inverse in the journal so it can be undone,
+// and writes the outcome to the audit log.
 func (c *Controller) Done() error {
+	// Errors of the linter block the policy unless it is forced
+	if !c.Force && lint.Blocking(c.Findings()) {
+		return errors.New("the policy has lint errors, fix them or apply it with --force")
+	}
+
+	if len(c.Accounts) == 0 {
+		return c.done(aws.Account{Api: c.api})
+	}
+
+	// A failure in one account does not stop the others
+	c.results = nil
+	var errs []error
+	for _, account := range c.Accounts {
+		err := c.done(account)
+		c.results = append(c.results, aws.AccountResult{Account: account, Err: err})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("account %s: %w", account.Id, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Results returns the outcome per account once Done has run for several accounts.
+func (c *Controller) Results() []aws.AccountResult {
+	return c.results
+}
+
+// done applies the operation in a single account and records it.
+func (c *Controller) done(account aws.Account) error {
+	applied, err := c.apply(account)
+
+	// Partially applied operations are journaled too, so they can be cleaned up with undo.
+	var journaled journal.Entry
+	if len(applied) > 0 {
+		var jerr error
+		journaled, jerr = journal.Append(journal.Entry{
+			Flow:      "groups",
+			Operation: c.State.operation.Id,
+			Principal: c.State.GetGroup().Name,
+			Inverse:   aws.Inverse(applied),
+			RoleArn:   account.RoleArn,
+		})
+		if jerr != nil {
+			err = errors.Join(err, fmt.Errorf("operation could not be recorded for undo: %w", jerr))
+		}
+	}
+
+	if err == nil && c.leased() {
+		if lerr := c.addLease(applied, account, journaled.Id); lerr != nil {
+			err = fmt.Errorf("access granted but the lease could not be recorded, revoke it manually: %w", lerr)
+		}
+	}
+
+	if aerr := audit.Record(c.auditEntry(account.Api, applied, err)); aerr != nil {
+		err = errors.Join(err, aerr)
+	}
+
+	return err
+}
+
+// leased reports whether the operation is a temporary grant that has to be revoked by a lease.
+// Custom policies expire through a condition in their document instead.
+func (c *Controller) leased() bool {
+	return c.State.expiresAt != nil && (c.State.operation.Id == AttachPolicySlug.String())
+}
+
+// addLease records the temporary grant so it can be revoked once it expires. The lease refers to the
+// journal entry of the grant, so undoing the grant also retires the lease.
+func (c *Controller) addLease(applied []aws.Call, account aws.Account, journalId string) error {
+	target := aws.RebaseArn(c.State.GetPolicy().Arn, account.Id)
+
+	_, err := lease.Add(lease.Lease{
+		Flow:      "groups",
+		Operation: c.State.operation.Id,
+		Principal: c.State.GetGroup().Name,
+		Target:    target,
+		ExpiresAt: *c.State.expiresAt,
+		Revoke:    aws.Inverse(applied),
+		RoleArn:   account.RoleArn,
+		JournalId: journalId,
+	})
+	return err
+}
+
+// policyDocument returns the custom policy document. When the access is temporary,
+// every Allow statement is limited to the expiry time.
+func (c *Controller) policyDocument() string {
+	if c.State.policy == nil {
+		return ""
+	}
+
+	document := c.State.policy.Document
+	if c.State.expiresAt == nil || document == "" {
+		return document
+	}
+
+	var policy ai.IAMPolicy
+	if err := json.Unmarshal([]byte(document), &policy); err != nil {
+		return document
+	}
+	policy.ExpireAt(*c.State.expiresAt)
+
+	expiring, err := json.MarshalIndent(policy, "", "\t")
+	if err != nil {
+		return document
+	}
+	return string(expiring)
+}
+
+// auditEntry describes the operation for the audit log.
+func (c *Controller) auditEntry(api *aws.Api, applied []aws.Call, err error) audit.Entry {
+	entry := audit.Entry{
+		Caller:    "unknown",
+		Flow:      "groups",
+		Operation: c.State.operation.Id,
+		Principal: c.State.GetGroup().Name,
+		Calls:     applied,
+		Outcome:   audit.OutcomeSuccess,
+	}
+
+	if arn, account, ierr := api.CallerIdentity(context.Background()); ierr == nil {
+		entry.Caller, entry.Account = arn, account
+	}
+	if c.State.policy != nil {
+		if !c.State.policy.Inline {
+			entry.PolicyArn = aws.RebaseArn(c.State.policy.Arn, entry.Account)
+		}
+		entry.PolicyDocument = c.policyDocument()
+	}
+	for _, call := range applied {
+		switch call.Action {
+		case aws.CreatePolicyAction:
+			entry.PolicyArn = call.Param("PolicyArn")
+		case aws.DeleteGroupPolicyAction:
+			// The document of a removed inline policy is only known once it has been read
+			entry.PolicyDocument = call.Param("PolicyDocument")
+		}
+	}
+	if err != nil {
+		entry.Outcome = audit.OutcomeFailure
+		entry.Error = err.Error()
+	}
+
+	return entry
+}
+
+// apply performs the operation in the account and returns the calls that were applied, including
+// the ones that succeeded before a failure.
+func (c *Controller) apply(account aws.Account) ([]aws.Call, error) {
+	groupName := c.State.GetGroup().Name
+
 	switch c.State.operation.Id {
 	case AttachPolicySlug.String():
-		return c.api.AttachPolicyToGroup(context.Background(), c.State.GetPolicy().Arn, c.State.GetGroup().Name)
+		policyArn := aws.RebaseArn(c.State.GetPolicy().Arn, account.Id)
+
+		// Attaching an attached policy changes nothing, undoing it or its lease would revoke access targe did not grant
+		attached, err := account.Api.IsPolicyAttachedToGroup(context.Background(), policyArn, groupName)
+		if err != nil {
+			return nil, err
+		}
+		if attached {
+			return nil, fmt.Errorf("policy %s is already attached to group %s", policyArn, groupName)
+		}
+
+		call := aws.NewCall(aws.AttachGroupPolicyAction, "PolicyArn", policyArn, "GroupName", groupName)
+		return calls(call, account.Api.AttachPolicyToGroup(context.Background(), policyArn, groupName))
 	case DetachPolicySlug.String():
-		return c.api.DetachPolicyFromGroup(context.Background(), c.State.GetPolicy().Arn, c.State.GetGroup().Name)
+		call := aws.NewCall(aws.DetachGroupPolicyAction, "PolicyArn", aws.RebaseArn(c.State.GetPolicy().Arn, account.Id), "GroupName", groupName)
+		return calls(call, account.Api.DetachPolicyFromGroup(context.Background(), aws.RebaseArn(c.State.GetPolicy().Arn, account.Id), groupName))
 	case AttachCustomPolicySlug.String():
-		output, err := c.api.CreatePolicy(context.Background(), c.State.GetPolicy().Name, c.State.GetPolicy().Document)
+		output, err := account.Api.CreatePolicy(context.Background(), c.State.GetPolicy().Name, c.policyDocument())
 		if err != nil {
-			return err
+			return nil, err
 		}
-		return c.api.AttachPolicyToGroup(context.Background(), *output.Policy.Arn, c.State.GetGroup().Name)
+
+		policyArn := *output.Policy.Arn
+		applied := []aws.Call{aws.NewCall(aws.CreatePolicyAction, "PolicyName", c.State.GetPolicy().Name, "PolicyArn", policyArn)}
+
+		attach := aws.NewCall(aws.AttachGroupPolicyAction, "PolicyArn", policyArn, "GroupName", groupName)
+		if err := account.Api.AttachPolicyToGroup(context.Background(), policyArn, groupName); err != nil {
+			return applied, fmt.Errorf("policy %s created but not attached: %w", policyArn, err)
+		}
+
+		return append(applied, attach), nil
+	case AttachInlinePolicySlug.String():
+		policyName, document := c.State.GetPolicy().Name, c.policyDocument()
+
+		// PutUserPolicy overwrites an inline policy of the same name, which undo could not restore
+		existing, err := account.Api.ListGroupInlinePolicies(context.Background(), groupName)
+		if err != nil {
+			return nil, err
+		}
+		if slices.Contains(existing, policyName) {
+			return nil, fmt.Errorf("group %s already has an inline policy named %s", groupName, policyName)
+		}
+
+		call := aws.NewCall(aws.PutGroupPolicyAction, "PolicyName", policyName, "PolicyDocument", document, "GroupName", groupName)
+		return calls(call, account.Api.PutInlinePolicyToGroup(context.Background(), policyName, document, groupName))
+	case RemoveInlinePolicySlug.String():
+		policyName := c.State.GetPolicy().Name
+
+		// The document is kept in the call so undo can put the policy back
+		document, err := account.Api.GetGroupInlinePolicy(context.Background(), policyName, groupName)
+		if err != nil {
+			return nil, err
+		}
+
+		call := aws.NewCall(aws.DeleteGroupPolicyAction, "PolicyName", policyName, "PolicyDocument", document, "GroupName", groupName)
+		return calls(call, account.Api.DeleteInlinePolicyFromGroup(context.Background(), policyName, groupName))
+	case EditPolicySlug.String():
+		return account.Api.UpdatePolicy(context.Background(), aws.RebaseArn(c.State.GetPolicy().Arn, account.Id), c.State.GetPolicy().Document, c.State.GetPruneVersion())
 	default:
-		return errors.New("operation not supported")
+		return nil, errors.New("operation not supported")
 	}
+}
 
-	return nil
+// calls returns the call as applied when err is nil.
+func calls(call aws.Call, err error) ([]aws.Call, error) {
+	if err != nil {
+		return nil, err
+	}
+	return []aws.Call{call}, nil
+}
+
+// Plan returns the IAM API calls that Done would perform for the current state, one plan for each
+// of the accounts with the ARNs of that account.
+func (c *Controller) Plan() []aws.AccountPlan {
+	if len(c.Accounts) == 0 {
+		return []aws.AccountPlan{{Calls: c.plan("")}}
+	}
+
+	var plans []aws.AccountPlan
+	for _, account := range c.Accounts {
+		plans = append(plans, aws.AccountPlan{Account: account.Id, Calls: c.plan(account.Id)})
+	}
+	return plans
+}
+
+// plan returns the IAM API calls that Done would perform in the account with the given id.
+func (c *Controller) plan(accountId string) []aws.Call {
+	switch c.State.operation.Id {
+	case AttachPolicySlug.String():
+		return []aws.Call{aws.NewCall(aws.AttachGroupPolicyAction, "PolicyArn", aws.RebaseArn(c.State.GetPolicy().Arn, accountId), "GroupName", c.State.GetGroup().Name)}
+	case DetachPolicySlug.String():
+		return []aws.Call{aws.NewCall(aws.DetachGroupPolicyAction, "PolicyArn", aws.RebaseArn(c.State.GetPolicy().Arn, accountId), "GroupName", c.State.GetGroup().Name)}
+	case AttachCustomPolicySlug.String():
+		return []aws.Call{
+			aws.NewCall(aws.CreatePolicyAction, "PolicyName", c.State.GetPolicy().Name, "Description", aws.PolicyDescription, "PolicyDocument", c.policyDocument()),
+			aws.NewCall(aws.AttachGroupPolicyAction, "PolicyArn", aws.CreatedPolicyArn, "GroupName", c.State.GetGroup().Name),
+		}
+	case AttachInlinePolicySlug.String():
+		return []aws.Call{aws.NewCall(aws.PutGroupPolicyAction, "PolicyName", c.State.GetPolicy().Name, "PolicyDocument", c.policyDocument(), "GroupName", c.State.GetGroup().Name)}
+	case RemoveInlinePolicySlug.String():
+		return []aws.Call{aws.NewCall(aws.DeleteGroupPolicyAction, "PolicyName", c.State.GetPolicy().Name, "GroupName", c.State.GetGroup().Name)}
+	case EditPolicySlug.String():
+		return c.editPlan(accountId)
+	default:
+		return nil
+	}
+}
+
+// editPlan returns the calls that make the edited document the default version of the policy,
+// pruning the oldest version first when the policy is at the version limit.
+func (c *Controller) editPlan(accountId string) []aws.Call {
+	var plan []aws.Call
+	policyArn := aws.RebaseArn(c.State.GetPolicy().Arn, accountId)
+
+	if versions := c.State.GetPolicyVersions(); versions != nil && versions.Count >= aws.MaxPolicyVersions && c.State.GetPruneVersion() {
+		plan = append(plan, aws.NewCall(aws.DeletePolicyVersionAction, "PolicyArn", policyArn, "VersionId", versions.Oldest))
+	}
+
+	return append(plan, aws.NewCall(aws.CreatePolicyVersionAction, "PolicyArn", policyArn, "PolicyDocument", c.State.GetPolicy().Document, "SetAsDefault", "true"))
+}
+
+// Missing lists the inputs that are still required before Done can apply the operation.
+func (c *Controller) Missing() []string {
+	var missing []string
+
+	if c.State.group == nil {
+		missing = append(missing, "group (--group)")
+	}
+
+	if c.State.operation == nil {
+		return append(missing, "operation (--operation)")
+	}
+
+	switch c.State.operation.Id {
+	case AttachCustomPolicySlug.String(), AttachInlinePolicySlug.String():
+		if c.State.policy == nil || c.State.policy.Document == "" {
+			missing = append(missing, "policy document (--policy-file)")
+		}
+	case EditPolicySlug.String():
+		if c.State.policy == nil {
+			missing = append(missing, "policy (--policy)")
+		}
+		if c.State.policy == nil || c.State.policy.Document == "" {
+			missing = append(missing, "policy document (--policy-file)")
+		}
+	default:
+		if c.State.policy == nil {
+			missing = append(missing, "policy (--policy)")
+		}
+	}
+
+	return missing
+}
+
+// Overview returns the rows that describe the current state.
+func (c *Controller) Overview() [][]string {
+	var rows [][]string
+
+	if c.State.group != nil {
+		rows = append(rows, []string{"Group", c.State.group.Name, c.State.group.Arn})
+	}
+	if c.State.operation != nil {
+		rows = append(rows, []string{"Operation", c.State.operation.Name, c.State.operation.Desc})
+	}
+	if c.State.service != nil {
+		rows = append(rows, []string{"Service", c.State.service.Name, c.State.service.Desc})
+	}
+	for _, resource := range c.State.resources {
+		rows = append(rows, []string{"Resource", resource.Name, resource.Arn})
+	}
+	if c.State.expiresAt != nil && c.State.operation != nil && slices.Contains(TemporaryOperations, OperationType(c.State.operation.Id)) {
+		rows = append(rows, []string{"Expires", c.State.expiresAt.Local().Format("2006-01-02 15:04"), time.Until(*c.State.expiresAt).Round(time.Minute).String()})
+	}
+	if c.State.policy != nil {
+		switch {
+		case c.State.policy.Inline:
+			rows = append(rows, []string{"Policy", c.State.policy.Name, "inline"})
+		case len(c.State.policy.Document) > 0 && (c.State.operation == nil || c.State.operation.Id != EditPolicySlug.String()):
+			rows = append(rows, []string{"Policy", c.State.policy.Name, "new"})
+		default:
+			rows = append(rows, []string{"Policy", c.State.policy.Name, c.State.policy.Arn})
+		}
+	}
+
+	if versions := c.State.versions; versions != nil && c.State.operation != nil && c.State.operation.Id == EditPolicySlug.String() {
+		detail := "default " + versions.Default
+		if versions.Count >= aws.MaxPolicyVersions {
+			detail += ", at the limit"
+			if c.State.prune {
+				detail += ", prune " + versions.Oldest
+			}
+		}
+		rows = append(rows, []string{"Versions", fmt.Sprintf("%d of %d", versions.Count, aws.MaxPolicyVersions), detail})
+	}
+
+	for _, finding := range c.Findings() {
+		rows = append(rows, []string{"Lint", finding.Severity, finding.Message + " (statement " + finding.Statement + ")"})
+	}
+
+	if len(c.Accounts) > 0 {
+		var ids []string
+		for _, account := range c.Accounts {
+			ids = append(ids, account.Id)
+		}
+		rows = append(rows, []string{"Accounts", fmt.Sprintf("%d accounts", len(c.Accounts)), strings.Join(ids, ", ")})
+	}
+	for _, result := range c.notCreated {
+		rows = append(rows, []string{"Warning", "group not created in " + result.Account.String(), result.Err.Error()})
+	}
+
+	return rows
+}
+
+// pageTitle returns a list title that shows the number of loaded items while more pages are loading.
+func pageTitle(title string, loaded int, loading bool) string {
+	if loading {
+		return fmt.Sprintf("%s (loading %d…)", title, loaded)
+	}
+	return title
 }
 
 // Switch handles window size changes and updates the model accordingly.
@@ -0,0 +1,189 @@
+package groups
+
+import (
+	"errors"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Permify/targe/internal/aws"
+)
+
+type CreateGroup struct {
+	controller *Controller
+	lg         *lipgloss.Renderer
+	styles     *Styles
+	form       *huh.Form
+	err        error
+	width      int
+	creating   bool
+	name       *string
+	path       *string
+	create     *bool
+}
+
+func NewCreateGroup(controller *Controller) CreateGroup {
+	m := CreateGroup{controller: controller, width: maxWidth}
+	m.lg = lipgloss.DefaultRenderer()
+	m.styles = NewStyles(m.lg)
+
+	name, path := "", aws.DefaultPath
+	m.name, m.path = &name, &path
+
+	m.reinitializeForm()
+
+	return m
+}
+
+func (m CreateGroup) Init() tea.Cmd {
+	return m.form.Init()
+}
+
+func (m CreateGroup) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = min(msg.Width, maxWidth) - m.styles.Base.GetHorizontalFrameSize()
+
+	case GroupCreatedMsg:
+		m.controller.State.SetGroup(&msg.Group)
+		return Switch(m.controller.Next(), 0, 0)
+
+	case FailedMsg:
+		m.creating = false
+		m.err = msg.Err
+		m.reinitializeForm()
+		return m, m.form.Init()
+
+	case tea.KeyMsg:
+		if msg.String() == "esc" || msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+	}
+
+	if m.creating {
+		return m, nil
+	}
+
+	var cmds []tea.Cmd
+
+	// Process the form
+	form, cmd := m.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.form = f
+		cmds = append(cmds, cmd)
+	}
+
+	// The form completes on "Create" or "Cancel"
+	if m.form.State == huh.StateCompleted {
+		if !*m.create {
+			return Switch(NewGroupList(m.controller), 0, 0)
+		}
+
+		name := strings.TrimSpace(*m.name)
+		if name == "" {
+			m.err = errors.New("Please enter a name for the group")
+		} else {
+			m.err = aws.ValidatePath(*m.path)
+		}
+		if m.err == nil {
+			m.creating = true
+			return m, m.controller.CreateGroup(name, *m.path)
+		}
+
+		m.reinitializeForm()
+		cmds = append(cmds, m.form.Init())
+	}
+
+	return m, tea.Batch(cmds...)
+}
+
+func (m CreateGroup) View() string {
+	s := m.styles
+
+	v := strings.TrimSuffix(m.form.View(), "\n\n")
+	form := m.lg.NewStyle().Margin(1, 0).Render(v)
+
+	// Status (right side)
+	var status string
+	{
+		const statusWidth = 60
+		statusMarginLeft := m.width - statusWidth - lipgloss.Width(form) - s.Status.GetMarginRight()
+		status = s.Status.
+			Height(lipgloss.Height(form)).
+			Width(statusWidth).
+			MarginLeft(statusMarginLeft).
+			Render(s.StatusHeader.Render("New Group") + "\n" +
+				m.statusView())
+	}
+
+	header := m.appBoundaryView("Create Group")
+	if m.err != nil {
+		header = m.appErrorBoundaryView(m.err.Error())
+	}
+	body := lipgloss.JoinHorizontal(lipgloss.Top, form, status)
+
+	footer := m.appBoundaryView(m.form.Help().ShortHelpView(m.form.KeyBinds()))
+
+	return s.Base.Render(header + "\n" + body + "\n\n" + footer)
+}
+
+// statusView describes where the group is created.
+func (m CreateGroup) statusView() string {
+	if m.creating {
+		return "Creating the group…"
+	}
+	if len(m.controller.Accounts) > 0 {
+		return "The group is created in each of the selected accounts."
+	}
+	return "The group is created in the current account. IAM groups cannot be tagged."
+}
+
+func (m CreateGroup) appBoundaryView(text string) string {
+	return lipgloss.PlaceHorizontal(
+		m.width,
+		lipgloss.Left,
+		m.styles.HeaderText.Render(text),
+		lipgloss.WithWhitespaceChars("/"),
+		lipgloss.WithWhitespaceForeground(indigo),
+	)
+}
+
+func (m CreateGroup) appErrorBoundaryView(text string) string {
+	return lipgloss.PlaceHorizontal(
+		m.width,
+		lipgloss.Left,
+		m.styles.ErrorHeaderText.Render(text),
+		lipgloss.WithWhitespaceChars("/"),
+		lipgloss.WithWhitespaceForeground(red),
+	)
+}
+
+func (m *CreateGroup) reinitializeForm() {
+	createInitialValue := true
+	m.create = &createInitialValue
+
+	// Preserve the entered values
+	m.form = huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Key("name").
+				Title("Name").
+				Value(m.name),
+			huh.NewInput().
+				Key("path").
+				Title("Path").
+				Value(m.path),
+			huh.NewConfirm().
+				Key("create").
+				Title("Create the group?").
+				Value(m.create).
+				Affirmative("Create").
+				Negative("Cancel"),
+		),
+	).
+		WithWidth(45).
+		WithShowHelp(false).
+		WithShowErrors(false)
+}
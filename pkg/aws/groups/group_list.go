@@ -45,8 +45,10 @@ func (m GroupList) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "ctrl+c":
 			return m, tea.Quit
 		case "enter":
-			if !m.loading {
-				group := m.list.SelectedItem().(models.Group)
+			if _, ok := m.list.SelectedItem().(models.CreateNew); ok && !m.loading {
+				return Switch(NewCreateGroup(m.controller), m.list.Width(), m.list.Height())
+			}
+			if group, ok := m.list.SelectedItem().(models.Group); ok && !m.loading {
 				m.controller.State.SetGroup(&group)
 				return Switch(m.controller.Next(), m.list.Width(), m.list.Height())
 			}
@@ -55,9 +57,13 @@ func (m GroupList) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		h, v := listStyle.GetFrameSize()
 		m.list.SetSize(msg.Width-h, msg.Height-v)
 	case GroupLoadedMsg:
-		// Update list with loaded users
+		// Append the loaded page, more pages may follow
 		m.loading = false
-		m.list.SetItems(msg.List)
+		m.list.SetItems(append(m.list.Items(), msg.List...))
+		m.list.Title = pageTitle("Groups", len(m.list.Items()), msg.Next != nil)
+		if msg.Next != nil {
+			return m, msg.Next
+		}
 	case FailedMsg:
 		// Handle error
 		m.loading = false
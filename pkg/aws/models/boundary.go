@@ -0,0 +1,9 @@
+package models
+
+// Boundary is the permissions boundary in effect for a user or role.
+type Boundary struct {
+	// Arn is the managed policy used as the boundary, empty when there is none.
+	Arn string
+	// Outside lists the actions the selected policy allows beyond the boundary.
+	Outside []string
+}
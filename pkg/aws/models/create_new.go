@@ -0,0 +1,13 @@
+package models
+
+// CreateNew is the list entry that creates a new user, group or role instead of selecting one.
+type CreateNew struct {
+	// Kind is the kind of entity, e.g. user.
+	Kind string
+}
+
+func (i CreateNew) Title() string { return "Create new " + i.Kind + "…" }
+func (i CreateNew) Description() string {
+	return "Create a new IAM " + i.Kind + " and continue with it"
+}
+func (i CreateNew) FilterValue() string { return "create new " + i.Kind }
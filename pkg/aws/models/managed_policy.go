@@ -0,0 +1,33 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ManagedPolicy is a customer managed policy created by targe.
+type ManagedPolicy struct {
+	Arn            string
+	Name           string
+	Attachments    int32
+	Versions       int
+	DefaultVersion string
+	Requester      string
+	CreatedAt      string
+}
+
+func (i ManagedPolicy) Title() string { return i.Name }
+func (i ManagedPolicy) Description() string {
+	parts := []string{
+		fmt.Sprintf("%d attachments", i.Attachments),
+		fmt.Sprintf("%d versions (default %s)", i.Versions, i.DefaultVersion),
+	}
+	if i.CreatedAt != "" {
+		parts = append(parts, "created "+i.CreatedAt)
+	}
+	if i.Requester != "" {
+		parts = append(parts, "by "+i.Requester)
+	}
+	return strings.Join(parts, " · ")
+}
+func (i ManagedPolicy) FilterValue() string { return i.Name }
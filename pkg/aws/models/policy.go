@@ -4,8 +4,15 @@ type Policy struct {
 	Arn      string
 	Name     string
 	Document string
+	// Inline marks a policy embedded in a user, group or role. Inline policies have no ARN.
+	Inline bool
 }
 
-func (i Policy) Title() string       { return i.Name }
-func (i Policy) Description() string { return i.Arn }
+func (i Policy) Title() string { return i.Name }
+func (i Policy) Description() string {
+	if i.Inline {
+		return "inline policy"
+	}
+	return i.Arn
+}
 func (i Policy) FilterValue() string { return i.Name }
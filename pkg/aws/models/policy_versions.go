@@ -0,0 +1,11 @@
+package models
+
+// PolicyVersions summarizes the versions of a customer managed policy that is being edited.
+type PolicyVersions struct {
+	// Default is the id of the default version and Document its decoded policy document.
+	Default  string
+	Document string
+	// Oldest is the id of the oldest non-default version, the one pruned at the version limit.
+	Oldest string
+	Count  int
+}
@@ -1,10 +1,49 @@
 package models
 
+import (
+	"sort"
+	"strings"
+)
+
 type Resource struct {
-	Arn  string
-	Name string
+	Arn    string
+	Name   string
+	Region string
+	Tags   map[string]string
+	// Selected marks the resource as one of several picked in the resource list
+	Selected bool
+}
+
+func (i Resource) Title() string {
+	if i.Selected {
+		return "✓ " + i.Name
+	}
+	return i.Name
+}
+func (i Resource) Description() string {
+	parts := []string{i.Arn}
+	if i.Region != "" {
+		parts = append(parts, i.Region)
+	}
+	if tags := i.tags(); tags != "" {
+		parts = append(parts, tags)
+	}
+	return strings.Join(parts, " · ")
 }
+func (i Resource) FilterValue() string { return i.Arn + " " + i.Region + " " + i.tags() }
 
-func (i Resource) Title() string       { return i.Name }
-func (i Resource) Description() string { return i.Arn }
-func (i Resource) FilterValue() string { return i.Arn }
+// tags renders the tags as sorted key=value pairs.
+func (i Resource) tags() string {
+	pairs := make([]string, 0, len(i.Tags))
+	for key, value := range i.Tags {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ", ")
+}
+
+// ResourceQuery narrows down the resources offered in the resource list.
+type ResourceQuery struct {
+	Text string
+	Tags map[string]string
+}
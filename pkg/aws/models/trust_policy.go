@@ -0,0 +1,9 @@
+package models
+
+// TrustPolicy is the trust policy of a role that is being changed.
+type TrustPolicy struct {
+	// Current is the trust policy of the role when it was loaded.
+	Current string
+	// Document is the changed trust policy, empty until a change has been made.
+	Document string
+}
@@ -0,0 +1,328 @@
+package policies
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/Permify/targe/internal/audit"
+	"github.com/Permify/targe/internal/aws"
+	"github.com/Permify/targe/internal/journal"
+	"github.com/Permify/targe/pkg/aws/models"
+)
+
+// DeletePolicyOperation is the operation recorded in the audit log when a policy is deleted.
+const DeletePolicyOperation = "delete_policy"
+
+type Controller struct {
+	api   *aws.Api
+	State *State
+	// DryRun limits the flow to previewing the IAM calls without applying them.
+	DryRun bool
+}
+
+func NewController(api *aws.Api, state *State) *Controller {
+	return &Controller{
+		api:   api,
+		State: state,
+	}
+}
+
+// FailedMsg represents a failure operation.
+type FailedMsg struct {
+	Err error
+}
+
+// PolicyLoadedMsg carries a page of policies. Next loads the following page and is nil on the last one.
+type PolicyLoadedMsg struct {
+	List []list.Item
+	Next tea.Cmd
+}
+
+// LoadPolicies loads the policies created by targe page by page.
+func (c *Controller) LoadPolicies() tea.Cmd {
+	return c.loadPoliciesPage(c.api.TargePolicyPager())
+}
+
+func (c *Controller) loadPoliciesPage(pager *aws.Pager[aws.TargePolicy]) tea.Cmd {
+	return func() tea.Msg {
+		var items []list.Item
+
+		policies, err := pager.NextPage(context.Background())
+		if err != nil {
+			return FailedMsg{Err: err}
+		}
+
+		for _, policy := range policies {
+			items = append(items, NewManagedPolicy(policy))
+		}
+
+		msg := PolicyLoadedMsg{List: items}
+		if pager.HasMorePages() {
+			msg.Next = c.loadPoliciesPage(pager)
+		}
+		return msg
+	}
+}
+
+// NewManagedPolicy converts a policy created by targe to its list item.
+func NewManagedPolicy(policy aws.TargePolicy) models.ManagedPolicy {
+	item := models.ManagedPolicy{
+		Arn:            awssdk.ToString(policy.Policy.Arn),
+		Name:           awssdk.ToString(policy.Policy.PolicyName),
+		Attachments:    awssdk.ToInt32(policy.Policy.AttachmentCount),
+		Versions:       len(policy.Versions),
+		DefaultVersion: awssdk.ToString(policy.Policy.DefaultVersionId),
+		Requester:      policy.Tag(aws.RequesterTagKey),
+		CreatedAt:      policy.Tag(aws.CreatedAtTagKey),
+	}
+
+	// Policies created before tagging fall back to the IAM creation date
+	if item.CreatedAt == "" && policy.Policy.CreateDate != nil {
+		item.CreatedAt = policy.Policy.CreateDate.UTC().Format(time.RFC3339)
+	}
+
+	return item
+}
+
+// DetailsLoadedMsg signals that the attachments and versions of the selected policy are loaded.
+type DetailsLoadedMsg struct{}
+
+// LoadDetails loads the attachments and versions of the selected policy.
+func (c *Controller) LoadDetails() tea.Cmd {
+	return func() tea.Msg {
+		if err := c.Inspect(context.Background()); err != nil {
+			return FailedMsg{Err: err}
+		}
+		return DetailsLoadedMsg{}
+	}
+}
+
+// Inspect loads the principals the selected policy is attached to, the principals that use it as
+// their permissions boundary, its versions, its tags and the document of its default version into the state.
+func (c *Controller) Inspect(ctx context.Context) error {
+	policy := c.State.GetPolicy()
+
+	entities, err := c.api.ListEntitiesForPolicy(ctx, policy.Arn, types.PolicyUsageTypePermissionsPolicy)
+	if err != nil {
+		return fmt.Errorf("failed to list the entities of %s: %w", policy.Name, err)
+	}
+
+	boundaries, err := c.api.ListEntitiesForPolicy(ctx, policy.Arn, types.PolicyUsageTypePermissionsBoundary)
+	if err != nil {
+		return fmt.Errorf("failed to list the principals %s is the permissions boundary of: %w", policy.Name, err)
+	}
+
+	// The policy list carries no tags
+	if policy.Requester == "" {
+		found, err := c.api.GetPolicy(ctx, policy.Arn)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", policy.Name, err)
+		}
+		tagged := NewManagedPolicy(aws.TargePolicy{Policy: *found})
+		policy.Requester = tagged.Requester
+		if tagged.CreatedAt != "" {
+			policy.CreatedAt = tagged.CreatedAt
+		}
+	}
+
+	versions, err := c.api.ListPolicyVersions(ctx, policy.Arn)
+	if err != nil {
+		return fmt.Errorf("failed to list the versions of %s: %w", policy.Name, err)
+	}
+
+	document, err := c.api.GetPolicyDocument(ctx, policy.Arn, policy.DefaultVersion)
+	if err != nil {
+		return fmt.Errorf("failed to read the default version of %s: %w", policy.Name, err)
+	}
+
+	c.State.entities = &entities
+	c.State.boundaries = &boundaries
+	c.State.versions = versions
+	c.State.document = document
+	return nil
+}
+
+// Next returns the view for the current state.
+func (c *Controller) Next() tea.Model {
+	if c.State.policy == nil {
+		return NewPolicyList(c)
+	}
+
+	return NewResult(c)
+}
+
+// Done deletes the selected policy. It stops at the first failed call.
+func (c *Controller) Done() error {
+	var applied []aws.Call
+	var err error
+	for _, call := range c.deletion() {
+		if err = c.api.Execute(context.Background(), call); err != nil {
+			err = fmt.Errorf("%s failed: %w", call.Action, err)
+			break
+		}
+		applied = append(applied, call)
+	}
+
+	// Undo attaches the detached principals again, and once the policy is deleted first creates it
+	// again from its default version.
+	if inverse := aws.Inverse(applied); len(inverse) > 0 {
+		_, jerr := journal.Append(journal.Entry{
+			Flow:      "policies",
+			Operation: DeletePolicyOperation,
+			Principal: c.State.GetPolicy().Name,
+			Inverse:   inverse,
+		})
+		if jerr != nil {
+			err = errors.Join(err, fmt.Errorf("operation could not be recorded for undo: %w", jerr))
+		}
+	}
+
+	if aerr := audit.Record(c.auditEntry(applied, err)); aerr != nil {
+		err = errors.Join(err, aerr)
+	}
+
+	return err
+}
+
+// auditEntry describes the deletion for the audit log. The default version document is kept
+// so a deleted policy can be recreated by hand.
+func (c *Controller) auditEntry(applied []aws.Call, err error) audit.Entry {
+	entry := audit.Entry{
+		Caller:         "unknown",
+		Flow:           "policies",
+		Operation:      DeletePolicyOperation,
+		PolicyArn:      c.State.GetPolicy().Arn,
+		PolicyDocument: c.State.GetDocument(),
+		Calls:          applied,
+		Outcome:        audit.OutcomeSuccess,
+	}
+
+	if arn, account, ierr := c.api.CallerIdentity(context.Background()); ierr == nil {
+		entry.Caller, entry.Account = arn, account
+	}
+	if err != nil {
+		entry.Outcome = audit.OutcomeFailure
+		entry.Error = err.Error()
+	}
+
+	return entry
+}
+
+// Plan returns the IAM API calls that Done would perform, the policies flow always runs in a single account.
+func (c *Controller) Plan() []aws.AccountPlan {
+	return []aws.AccountPlan{{Calls: c.deletion()}}
+}
+
+// deletion returns the IAM API calls that delete the selected policy: detach the policy from every principal,
+// remove it as the permissions boundary of users and roles, delete its non-default versions and
+// finally the policy itself. The DeletePolicy call carries the default version document, so undo
+// can create the policy again.
+func (c *Controller) deletion() []aws.Call {
+	policy := c.State.GetPolicy()
+	if policy == nil || c.State.entities == nil || c.State.boundaries == nil {
+		return nil
+	}
+
+	var calls []aws.Call
+	for _, user := range c.State.entities.Users {
+		calls = append(calls, aws.NewCall(aws.DetachUserPolicyAction, "PolicyArn", policy.Arn, "UserName", user))
+	}
+	for _, group := range c.State.entities.Groups {
+		calls = append(calls, aws.NewCall(aws.DetachGroupPolicyAction, "PolicyArn", policy.Arn, "GroupName", group))
+	}
+	for _, role := range c.State.entities.Roles {
+		calls = append(calls, aws.NewCall(aws.DetachRolePolicyAction, "PolicyArn", policy.Arn, "RoleName", role))
+	}
+	for _, user := range c.State.boundaries.Users {
+		calls = append(calls, aws.NewCall(aws.DeleteUserBoundaryAction, "UserName", user, "PermissionsBoundary", policy.Arn))
+	}
+	for _, role := range c.State.boundaries.Roles {
+		calls = append(calls, aws.NewCall(aws.DeleteRoleBoundaryAction, "RoleName", role, "PermissionsBoundary", policy.Arn))
+	}
+	for _, version := range c.State.versions {
+		if version.IsDefaultVersion {
+			continue
+		}
+		calls = append(calls, aws.NewCall(aws.DeletePolicyVersionAction, "PolicyArn", policy.Arn, "VersionId", awssdk.ToString(version.VersionId)))
+	}
+
+	return append(calls, aws.NewCall(aws.DeletePolicyAction, "PolicyArn", policy.Arn, "PolicyName", policy.Name, "PolicyDocument", c.State.document))
+}
+
+// Missing lists the inputs that are still required before Done can delete the policy.
+func (c *Controller) Missing() []string {
+	if c.State.policy == nil {
+		return []string{"policy (--policy)"}
+	}
+	return nil
+}
+
+// Overview returns the rows that describe the current state.
+func (c *Controller) Overview() [][]string {
+	var rows [][]string
+
+	policy := c.State.GetPolicy()
+	if policy == nil {
+		return rows
+	}
+
+	rows = append(rows, []string{"Policy", policy.Name, policy.Arn})
+	if policy.Requester != "" {
+		rows = append(rows, []string{"Created", policy.CreatedAt, policy.Requester})
+	}
+	if c.State.versions != nil {
+		rows = append(rows, []string{"Versions", strconv.Itoa(len(c.State.versions)), "default " + policy.DefaultVersion})
+	}
+	if entities := c.State.entities; entities != nil {
+		rows = append(rows, []string{"Users", strconv.Itoa(len(entities.Users)), strings.Join(entities.Users, ", ")})
+		rows = append(rows, []string{"Groups", strconv.Itoa(len(entities.Groups)), strings.Join(entities.Groups, ", ")})
+		rows = append(rows, []string{"Roles", strconv.Itoa(len(entities.Roles)), strings.Join(entities.Roles, ", ")})
+	}
+	if boundaries := c.State.boundaries; boundaries != nil && len(boundaries.Users)+len(boundaries.Roles) > 0 {
+		principals := append(append([]string{}, boundaries.Users...), boundaries.Roles...)
+		rows = append(rows, []string{"Boundary of", strconv.Itoa(len(principals)), strings.Join(principals, ", ")})
+	}
+
+	return rows
+}
+
+// Results returns nil, the policies flow always runs in a single account.
+func (c *Controller) Results() []aws.AccountResult {
+	return nil
+}
+
+// pageTitle returns a list title that shows the number of loaded items while more pages are loading.
+func pageTitle(title string, loaded int, loading bool) string {
+	if loading {
+		return fmt.Sprintf("%s (loading %d…)", title, loaded)
+	}
+	return title
+}
+
+// Switch handles window size changes and updates the model accordingly.
+func Switch(model tea.Model, width, height int) (tea.Model, tea.Cmd) {
+	// Always initialize the model
+	initCmd := model.Init()
+
+	// Handle window size updates
+	if width == 0 && height == 0 {
+		return model, initCmd
+	}
+
+	updateModel, updateCmd := model.Update(tea.WindowSizeMsg{
+		Width:  width,
+		Height: height,
+	})
+
+	// Combine initialization and update commands
+	return updateModel, tea.Batch(initCmd, updateCmd)
+}
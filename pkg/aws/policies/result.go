@@ -0,0 +1,283 @@
+package policies
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+
+	"github.com/Permify/targe/internal/aws"
+)
+
+// Choices offered on the result screen.
+const (
+	applyChoice   = "apply"
+	previewChoice = "preview"
+	cancelChoice  = "cancel"
+)
+
+type Result struct {
+	controller *Controller
+	lg         *lipgloss.Renderer
+	styles     *Styles
+	form       *huh.Form
+	width      int
+	value      *string
+	applied    bool
+	loading    bool
+	error      error
+}
+
+func NewResult(controller *Controller) Result {
+	// Initialize the Result with default values
+	result := Result{
+		width:      maxWidth,
+		lg:         lipgloss.DefaultRenderer(),
+		controller: controller,
+		loading:    controller.State.GetEntities() == nil,
+	}
+
+	// Initialize styles
+	result.styles = NewStyles(result.lg)
+
+	// Initialize value pointer
+	initialValue := applyChoice
+	if controller.DryRun {
+		initialValue = previewChoice
+	}
+	result.value = &initialValue
+
+	// Configure the form
+	result.form = createForm(result.value, controller.DryRun)
+
+	return result
+}
+
+func (m Result) Init() tea.Cmd {
+	if m.loading {
+		return tea.Batch(m.form.Init(), m.controller.LoadDetails())
+	}
+	return m.form.Init()
+}
+
+func min(x, y int) int {
+	if x > y {
+		return y
+	}
+	return x
+}
+
+func (m Result) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = min(msg.Width, 80) - m.styles.Base.GetHorizontalFrameSize()
+	case DetailsLoadedMsg:
+		m.loading = false
+		return m, nil
+	case FailedMsg:
+		m.loading = false
+		m.error = msg.Err
+		return m, nil
+	case tea.KeyMsg:
+		if msg.String() == "esc" || msg.String() == "ctrl+c" || msg.String() == "q" {
+			return m, tea.Quit
+		}
+
+		// Nothing can be chosen until the policy details are loaded
+		if m.loading || m.controller.State.GetEntities() == nil {
+			return m, nil
+		}
+
+		// Handle "Enter" for exit confirmation
+		if m.form.State == huh.StateCompleted && msg.String() == "enter" {
+			return m, tea.Quit
+		}
+	}
+
+	var cmds []tea.Cmd
+
+	// Process the form
+	form, cmd := m.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.form = f
+		cmds = append(cmds, cmd)
+	}
+
+	// Handle form completion once
+	if m.form.State == huh.StateCompleted && !m.applied {
+		m.applied = true
+		switch *m.value {
+		case applyChoice:
+			if err := m.controller.Done(); err != nil {
+				// Handle error without quitting
+				m.error = err
+				return m, nil // Return updated model without quitting
+			}
+		case previewChoice:
+			// The plan is rendered by View, nothing is applied
+		default:
+			cmds = append(cmds, tea.Quit)
+		}
+	}
+
+	return m, tea.Batch(cmds...)
+}
+
+func (m Result) View() string {
+	if m.loading {
+		return m.styles.Base.Render(m.appBoundaryView("Overview") + "\n\nLoading policy details...")
+	}
+
+	if m.controller.State.GetEntities() == nil && m.error != nil {
+		return m.styles.Base.Render(m.appErrorBoundaryView("Error") + "\n\n" + m.error.Error())
+	}
+
+	if m.form.State == huh.StateCompleted && *m.value == previewChoice {
+		return m.planView()
+	}
+
+	if m.form.State == huh.StateCompleted && m.error == nil {
+		// Success Message with Exit Footer
+		successMessage := fmt.Sprintf(
+			"\n%s\n\n%s\n",
+			lipgloss.NewStyle().
+				Bold(true).
+				Foreground(lipgloss.Color("10")).
+				Render("✔ Policy deleted successfully!"),
+			lipgloss.NewStyle().
+				Foreground(lipgloss.Color("7")).
+				Italic(true).
+				Render("The policy has been detached from every principal and deleted with all of its versions."),
+		)
+
+		exitFooter := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("8")).
+			Italic(true).
+			Render("Press Enter to exit.")
+
+		return successMessage + "\n" + exitFooter
+	}
+
+	// When not in completed state, display other UI elements
+	rows := m.controller.Overview()
+	t := m.createTable(rows)
+	formView := m.lg.NewStyle().Margin(1, 0).Render(strings.TrimSuffix(m.form.View(), "\n\n"))
+	header := m.renderHeader()
+	footer := m.renderFooter()
+
+	body := lipgloss.JoinVertical(lipgloss.Top, t.Render(), formView)
+
+	// Add error message if present
+	if m.error != nil {
+		errorView := fmt.Sprintf(
+			"\n%s\n\n%s\n",
+			lipgloss.NewStyle().
+				Bold(true).
+				Foreground(lipgloss.Color("9")).
+				Render("✖ An error occurred"),
+			lipgloss.NewStyle().
+				Foreground(lipgloss.Color("1")).
+				Italic(true).
+				Render(m.error.Error()),
+		)
+		body = lipgloss.JoinVertical(lipgloss.Top, body, errorView)
+	}
+
+	return m.styles.Base.Render(header + "\n" + body + "\n\n" + footer)
+}
+
+// planView renders the IAM calls that would be performed without applying them.
+func (m Result) planView() string {
+	plan := m.lg.NewStyle().Margin(1, 0, 0, 2).Render(aws.FormatAccountPlans(m.controller.Plan()))
+
+	exitFooter := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("8")).
+		Italic(true).
+		Render("Nothing has been applied. Press Enter to exit.")
+
+	return m.styles.Base.Render(m.appBoundaryView("Plan") + "\n" + m.createTable(m.controller.Overview()).Render() + "\n" + plan + "\n\n" + exitFooter)
+}
+
+func (m Result) createTable(rows [][]string) *table.Table {
+	return table.New().
+		Border(lipgloss.HiddenBorder()).
+		BorderStyle(lipgloss.NewStyle().Foreground(lipgloss.Color("99"))).
+		StyleFunc(func(row, col int) lipgloss.Style {
+			if col == 0 {
+				return m.styles.Base.Foreground(lipgloss.Color("205")).Bold(true)
+			}
+			return m.styles.Base
+		}).
+		Rows(rows...)
+}
+
+func (m Result) renderHeader() string {
+	errors := m.form.Errors()
+	if len(errors) > 0 {
+		return m.appErrorBoundaryView(m.errorView())
+	}
+	return m.appBoundaryView("Overview")
+}
+
+func (m Result) renderFooter() string {
+	errors := m.form.Errors()
+	if len(errors) > 0 {
+		return m.appErrorBoundaryView("")
+	}
+	return m.appBoundaryView(m.form.Help().ShortHelpView(m.form.KeyBinds()))
+}
+
+func (m Result) errorView() string {
+	var s string
+	for _, err := range m.form.Errors() {
+		s += err.Error() + "\n"
+	}
+	return s
+}
+
+func (m Result) appBoundaryView(text string) string {
+	return lipgloss.PlaceHorizontal(
+		m.width,
+		lipgloss.Left,
+		m.styles.HeaderText.Render(text),
+		lipgloss.WithWhitespaceChars("/"),
+		lipgloss.WithWhitespaceForeground(indigo),
+	)
+}
+
+func (m Result) appErrorBoundaryView(text string) string {
+	return lipgloss.PlaceHorizontal(
+		m.width,
+		lipgloss.Left,
+		m.styles.ErrorHeaderText.Render(text),
+		lipgloss.WithWhitespaceChars("/"),
+		lipgloss.WithWhitespaceForeground(red),
+	)
+}
+
+func createForm(value *string, dryRun bool) *huh.Form {
+	options := []huh.Option[string]{
+		huh.NewOption("Delete", applyChoice),
+		huh.NewOption("Preview only", previewChoice),
+		huh.NewOption("No", cancelChoice),
+	}
+	if dryRun {
+		options = options[1:]
+	}
+
+	confirm := huh.NewSelect[string]().
+		Key("done").
+		Title("Delete this policy?").
+		Options(options...).
+		Value(value)
+
+	return huh.NewForm(
+		huh.NewGroup(confirm),
+	).
+		WithWidth(45).
+		WithShowHelp(false).
+		WithShowErrors(false)
+}
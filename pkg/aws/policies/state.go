@@ -0,0 +1,56 @@
+package policies
+
+import (
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+
+	"github.com/Permify/targe/internal/aws"
+	"github.com/Permify/targe/pkg/aws/models"
+)
+
+// State represents the policies flow state.
+type State struct {
+	policy   *models.ManagedPolicy
+	entities *aws.PolicyEntities
+	// boundaries are the users and roles that use the policy as their permissions boundary.
+	boundaries *aws.PolicyEntities
+	versions   []types.PolicyVersion
+	document   string
+}
+
+// Getters
+
+// GetPolicy retrieves the policy from the state.
+func (s *State) GetPolicy() *models.ManagedPolicy {
+	return s.policy
+}
+
+// GetEntities retrieves the principals the policy is attached to.
+func (s *State) GetEntities() *aws.PolicyEntities {
+	return s.entities
+}
+
+// GetBoundaries retrieves the users and roles that use the policy as their permissions boundary.
+func (s *State) GetBoundaries() *aws.PolicyEntities {
+	return s.boundaries
+}
+
+// GetVersions retrieves the versions of the policy.
+func (s *State) GetVersions() []types.PolicyVersion {
+	return s.versions
+}
+
+// GetDocument retrieves the document of the default policy version.
+func (s *State) GetDocument() string {
+	return s.document
+}
+
+// Setters
+
+// SetPolicy updates the policy in the state and drops the details of the previous one.
+func (s *State) SetPolicy(policy *models.ManagedPolicy) {
+	s.policy = policy
+	s.entities = nil
+	s.boundaries = nil
+	s.versions = nil
+	s.document = ""
+}
@@ -0,0 +1,122 @@
+// Package policyforms holds the forms the users, groups and roles flows share to write policies:
+// the policy builder, the custom policy generator and the policy editor.
+package policyforms
+
+import (
+	"context"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Permify/targe/internal/ai"
+	"github.com/Permify/targe/internal/diff"
+	"github.com/Permify/targe/internal/lint"
+	requirements "github.com/Permify/targe/internal/requirements/aws"
+	"github.com/Permify/targe/pkg/aws/models"
+)
+
+// State is the part of the state of a flow the forms read and update.
+type State interface {
+	GetService() *models.Service
+	GetResources() []models.Resource
+	SetResources(resources []models.Resource)
+	GetActions() []string
+	SetActions(actions []string)
+	GetPrompt() string
+	GetPolicy() *models.Policy
+	SetPolicy(policy *models.Policy)
+	GetPolicyVersions() *models.PolicyVersions
+}
+
+// Controller is the part of the controller of a flow the forms use.
+type Controller interface {
+	// Principal returns the kind, the name and the ARN of the selected principal, an empty name
+	// until one is selected.
+	Principal() (kind, name, arn string)
+	// Provider returns the AI provider, nil when none is configured.
+	Provider() ai.Provider
+	Findings() []lint.Finding
+	CatalogService() (requirements.ServiceActions, error)
+	BuildPolicy(name, conditions string) error
+	// SetCustomPolicy selects a new policy with the document, inline when the operation attaches an inline policy.
+	SetCustomPolicy(name, document string)
+	InspectPolicy(ctx context.Context) error
+	Next() tea.Model
+}
+
+// next switches to the model the controller continues with.
+func next(controller Controller) (tea.Model, tea.Cmd) {
+	model := controller.Next()
+	return model, model.Init()
+}
+
+// resourceArns returns the ARNs of the selected resources.
+func resourceArns(state State) []string {
+	var arns []string
+	for _, resource := range state.GetResources() {
+		arns = append(arns, resource.Arn)
+	}
+	return arns
+}
+
+// resourceTitles renders a title row for each selected resource.
+func resourceTitles(s *Styles, state State) []string {
+	var titles []string
+	for _, resource := range state.GetResources() {
+		titles = append(titles, s.StateHeader.Render("Resource ARN: "+resource.Arn))
+	}
+	return titles
+}
+
+// linesView renders the lines of a diff, the added lines in green and the removed lines in red.
+func linesView(lines []diff.Line) string {
+	var rendered []string
+	for _, line := range lines {
+		switch line.Kind {
+		case diff.Added:
+			rendered = append(rendered, lipgloss.NewStyle().Foreground(lipgloss.Color("10")).Render(line.String()))
+		case diff.Removed:
+			rendered = append(rendered, lipgloss.NewStyle().Foreground(lipgloss.Color("9")).Render(line.String()))
+		default:
+			rendered = append(rendered, line.String())
+		}
+	}
+	return strings.Join(rendered, "\n")
+}
+
+// findingsView renders the findings of the linter below the generated policy.
+func findingsView(findings []lint.Finding) string {
+	var lines []string
+	for _, finding := range findings {
+		color := lipgloss.Color("11")
+		if finding.Severity == lint.SeverityError {
+			color = lipgloss.Color("9")
+		}
+		lines = append(lines, lipgloss.NewStyle().Foreground(color).Render("✖ "+finding.String()))
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return "\n\n" + strings.Join(lines, "\n")
+}
+
+func appBoundaryView(s *Styles, width int, text string) string {
+	return lipgloss.PlaceHorizontal(
+		width,
+		lipgloss.Left,
+		s.HeaderText.Render(text),
+		lipgloss.WithWhitespaceChars("/"),
+		lipgloss.WithWhitespaceForeground(indigo),
+	)
+}
+
+func appErrorBoundaryView(s *Styles, width int, text string) string {
+	return lipgloss.PlaceHorizontal(
+		width,
+		lipgloss.Left,
+		s.ErrorHeaderText.Render(text),
+		lipgloss.WithWhitespaceChars("/"),
+		lipgloss.WithWhitespaceForeground(red),
+	)
+}
@@ -0,0 +1,267 @@
+package policyforms
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Permify/targe/internal/ai"
+	"github.com/Permify/targe/internal/diff"
+)
+
+type CreatePolicy struct {
+	controller  Controller
+	state       State
+	lg          *lipgloss.Renderer
+	styles      *Styles
+	form        *huh.Form
+	senderStyle lipgloss.Style
+	err         error
+	width       int
+	message     *string
+	done        *bool
+	result      string
+	// history is the conversation sent along with each follow-up, the requests and the policies returned for them
+	history []map[string]string
+	// previous is the policy document before the last request, empty until the policy is refined
+	previous string
+}
+
+func NewCreatePolicy(controller Controller, state State) CreatePolicy {
+	m := CreatePolicy{controller: controller, state: state, width: maxWidth}
+	m.lg = lipgloss.DefaultRenderer()
+	m.styles = NewStyles(m.lg)
+	m.senderStyle = m.lg.NewStyle().Foreground(indigo).Bold(true)
+
+	// The description starts from the request the flow was started with
+	messageInitialValue := state.GetPrompt()
+	m.message = &messageInitialValue
+
+	m.reinitializeForm()
+
+	return m
+}
+
+func (m CreatePolicy) Init() tea.Cmd {
+	return m.form.Init()
+}
+
+func (m CreatePolicy) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = min(msg.Width, maxWidth) - m.styles.Base.GetHorizontalFrameSize()
+
+	case tea.KeyMsg:
+
+		if msg.String() == "esc" || msg.String() == "ctrl+c" || msg.String() == "q" {
+			return m, tea.Quit
+		}
+
+		// Check if the "Send" or "Done" button was selected
+		if msg.String() == "enter" {
+			if m.done != nil && *m.done {
+				return next(m.controller)
+			} else {
+				m.err = nil
+				if strings.TrimSpace(*m.message) == "" {
+					m.err = errors.New("Please describe the policy")
+				} else if err := m.send(*m.message); err != nil {
+					m.err = err
+				} else {
+					// The input is cleared for the next follow-up
+					*m.message = ""
+				}
+
+				m.reinitializeForm()
+			}
+		}
+	}
+
+	var cmds []tea.Cmd
+
+	// Process the form
+	form, cmd := m.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.form = f
+		cmds = append(cmds, cmd)
+	}
+
+	return m, tea.Batch(cmds...)
+}
+
+func (m CreatePolicy) View() string {
+	s := m.styles
+
+	v := strings.TrimSuffix(m.form.View(), "\n\n")
+	form := m.lg.NewStyle().Margin(1, 0).Render(v)
+
+	var titles []string
+	var title string
+
+	if kind, name, arn := m.controller.Principal(); name != "" {
+		titles = append(titles,
+			s.StateHeader.Render(kind+" Name: "+name),
+			s.StateHeader.Render(kind+" ARN: "+arn),
+		)
+	}
+
+	if m.state.GetService() != nil && len(m.state.GetResources()) > 0 {
+		titles = append(titles, s.StateHeader.Render("Service Name: "+m.state.GetService().Name))
+		titles = append(titles, resourceTitles(s, m.state)...)
+	}
+
+	if len(titles) > 0 {
+		// Join the titles vertically
+		title = lipgloss.JoinVertical(lipgloss.Left, titles...)
+
+		// Apply margin-top to the entire title block
+		title = lipgloss.NewStyle().
+			MarginTop(1). // Set the margin top to 2 lines
+			Render(title)
+	}
+
+	// Status (right side)
+	var status string
+	{
+		const statusWidth = 60
+		statusMarginLeft := m.width - statusWidth - lipgloss.Width(form) - s.Status.GetMarginRight()
+		status = s.Status.
+			Height(lipgloss.Height(form)).
+			Width(statusWidth).
+			MarginLeft(statusMarginLeft).
+			Render(m.conversationView() +
+				s.StatusHeader.Render("Policy") + "\n" +
+				m.policyView() + findingsView(m.controller.Findings()))
+	}
+
+	errors := m.form.Errors()
+	header := lipgloss.JoinVertical(lipgloss.Top,
+		appBoundaryView(s, m.width, "Custom Policy Generator"),
+		title,
+	)
+	if len(errors) > 0 {
+		header = appErrorBoundaryView(s, m.width, m.errorView())
+	} else if m.err != nil {
+		header = appErrorBoundaryView(s, m.width, m.err.Error())
+	}
+	body := lipgloss.JoinHorizontal(lipgloss.Top, form, status)
+
+	footer := appBoundaryView(s, m.width, m.form.Help().ShortHelpView(m.form.KeyBinds()))
+	if len(errors) > 0 {
+		footer = appErrorBoundaryView(s, m.width, "")
+	}
+
+	return s.Base.Render(header + "\n" + body + "\n\n" + footer)
+}
+
+// send generates the policy from the message, or refines the current policy with it once one is
+// generated, and records the turn in the conversation.
+func (m *CreatePolicy) send(message string) error {
+	var policy ai.IAMPolicy
+	var err error
+
+	if m.result == "" {
+		var serviceName *string = nil
+		if m.state.GetService() != nil {
+			serviceName = &m.state.GetService().Name
+		}
+
+		policy, err = ai.GeneratePolicy(m.controller.Provider(), message, serviceName, resourceArns(m.state))
+	} else {
+		policy, err = ai.RefinePolicy(m.controller.Provider(), m.result, m.history, message)
+	}
+	if err != nil {
+		return err
+	}
+
+	policyJson, err := json.MarshalIndent(policy, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	// Refining keeps the name the policy was generated with
+	name := policy.Id
+	if current := m.state.GetPolicy(); m.result != "" && current != nil && current.Name != "" {
+		name = current.Name
+	}
+
+	m.previous, m.result = m.result, string(policyJson)
+	m.history = append(m.history,
+		map[string]string{"role": "user", "content": message},
+		map[string]string{"role": "assistant", "content": m.result},
+	)
+
+	m.controller.SetCustomPolicy(name, m.result)
+	return nil
+}
+
+// conversationView renders the requests of the conversation so far above the policy.
+func (m CreatePolicy) conversationView() string {
+	var lines []string
+	for _, message := range m.history {
+		if message["role"] == "user" {
+			lines = append(lines, m.senderStyle.Render("You: ")+message["content"])
+		}
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return m.styles.StatusHeader.Render("Conversation") + "\n" + strings.Join(lines, "\n") + "\n\n"
+}
+
+// policyView renders the generated policy, or its diff to the previous version once it is refined.
+func (m CreatePolicy) policyView() string {
+	if m.result == "" {
+		return "(None)"
+	}
+	if m.previous == "" {
+		return m.result
+	}
+
+	lines := diff.Lines(m.previous, m.result)
+	if !diff.Changed(lines) {
+		return m.result + "\n\n(No changes)"
+	}
+	return linesView(lines)
+}
+
+func (m CreatePolicy) errorView() string {
+	var s string
+	for _, err := range m.form.Errors() {
+		s += err.Error()
+	}
+	return s
+}
+
+func (m *CreatePolicy) reinitializeForm() {
+	doneInitialValue := false
+	m.done = &doneInitialValue
+
+	// Once a policy is generated, the message refines it
+	title := "Describe Your Policy"
+	if m.result != "" {
+		title = "Refine the Policy"
+	}
+
+	// Preserve the current message value
+	m.form = huh.NewForm(
+		huh.NewGroup(
+			huh.NewText().
+				Key("message").
+				Title(title).Value(m.message),
+			huh.NewConfirm().
+				Key("done").
+				Title("All done?").
+				Value(m.done).
+				Affirmative("Yes").
+				Negative("Send"),
+		),
+	).
+		WithWidth(45).
+		WithShowHelp(false).
+		WithShowErrors(false)
+}
@@ -0,0 +1,236 @@
+package policyforms
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Permify/targe/internal/ai"
+	"github.com/Permify/targe/internal/diff"
+	"github.com/Permify/targe/pkg/aws/models"
+)
+
+// versionsLoadedMsg signals that the versions of the policy to edit are loaded.
+type versionsLoadedMsg struct{}
+
+// versionsFailedMsg carries the error the versions of the policy to edit failed to load with.
+type versionsFailedMsg struct {
+	Err error
+}
+
+type EditPolicy struct {
+	controller Controller
+	state      State
+	lg         *lipgloss.Renderer
+	styles     *Styles
+	form       *huh.Form
+	err        error
+	width      int
+	loading    bool
+	message    *string
+	done       *bool
+	// document is the edited policy document, empty until a change has been generated
+	document string
+}
+
+func NewEditPolicy(controller Controller, state State) EditPolicy {
+	m := EditPolicy{controller: controller, state: state, width: maxWidth}
+	m.lg = lipgloss.DefaultRenderer()
+	m.styles = NewStyles(m.lg)
+
+	m.loading = state.GetPolicyVersions() == nil
+
+	messageInitialValue := ""
+	m.message = &messageInitialValue
+
+	m.reinitializeForm()
+
+	return m
+}
+
+func (m EditPolicy) Init() tea.Cmd {
+	if m.loading {
+		return tea.Batch(m.form.Init(), m.loadPolicyVersions())
+	}
+	return m.form.Init()
+}
+
+// loadPolicyVersions loads the versions and the current document of the policy to edit.
+func (m EditPolicy) loadPolicyVersions() tea.Cmd {
+	return func() tea.Msg {
+		if err := m.controller.InspectPolicy(context.Background()); err != nil {
+			return versionsFailedMsg{Err: err}
+		}
+		return versionsLoadedMsg{}
+	}
+}
+
+func (m EditPolicy) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = min(msg.Width, maxWidth) - m.styles.Base.GetHorizontalFrameSize()
+
+	case versionsLoadedMsg:
+		m.loading = false
+		return m, nil
+
+	case versionsFailedMsg:
+		m.loading = false
+		m.err = msg.Err
+		return m, nil
+
+	case tea.KeyMsg:
+
+		if msg.String() == "esc" || msg.String() == "ctrl+c" || msg.String() == "q" {
+			return m, tea.Quit
+		}
+
+		// Check if the "Refresh" or "Done" button was selected
+		if msg.String() == "enter" && !m.loading && m.state.GetPolicyVersions() != nil {
+			if m.done != nil && *m.done {
+				if m.document == "" {
+					m.err = errors.New("Describe the change and refresh before continuing")
+					m.reinitializeForm()
+				} else {
+					policy := m.state.GetPolicy()
+					m.state.SetPolicy(&models.Policy{
+						Arn:      policy.Arn,
+						Name:     policy.Name,
+						Document: m.document,
+					})
+					return next(m.controller)
+				}
+			} else {
+				m.err = nil
+				if strings.TrimSpace(*m.message) == "" {
+					m.err = errors.New("Please describe the change")
+				} else if policy, err := ai.EditPolicy(m.controller.Provider(), m.state.GetPolicyVersions().Document, *m.message); err != nil {
+					m.err = err
+				} else if policyJson, err := json.MarshalIndent(policy, "", "\t"); err != nil {
+					m.err = err
+				} else {
+					m.document = string(policyJson)
+				}
+
+				m.reinitializeForm()
+			}
+		}
+	}
+
+	var cmds []tea.Cmd
+
+	// Process the form
+	form, cmd := m.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.form = f
+		cmds = append(cmds, cmd)
+	}
+
+	return m, tea.Batch(cmds...)
+}
+
+func (m EditPolicy) View() string {
+	s := m.styles
+
+	v := strings.TrimSuffix(m.form.View(), "\n\n")
+	form := m.lg.NewStyle().Margin(1, 0).Render(v)
+
+	var titles []string
+	var title string
+
+	if kind, name, _ := m.controller.Principal(); name != "" {
+		titles = append(titles,
+			s.StateHeader.Render(kind+" Name: "+name),
+		)
+	}
+
+	if policy := m.state.GetPolicy(); policy != nil {
+		titles = append(titles,
+			s.StateHeader.Render("Policy ARN: "+policy.Arn),
+		)
+	}
+
+	if len(titles) > 0 {
+		title = lipgloss.JoinVertical(lipgloss.Left, titles...)
+
+		title = lipgloss.NewStyle().
+			MarginTop(1).
+			Render(title)
+	}
+
+	// Status (right side)
+	var status string
+	{
+		const statusWidth = 60
+		statusMarginLeft := m.width - statusWidth - lipgloss.Width(form) - s.Status.GetMarginRight()
+		status = s.Status.
+			Height(lipgloss.Height(form)).
+			Width(statusWidth).
+			MarginLeft(statusMarginLeft).
+			Render(s.StatusHeader.Render("Changes") + "\n" +
+				m.diffView())
+	}
+
+	header := lipgloss.JoinVertical(lipgloss.Top,
+		appBoundaryView(s, m.width, "Policy Editor"),
+		title,
+	)
+	if m.err != nil {
+		header = appErrorBoundaryView(s, m.width, m.err.Error())
+	}
+	body := lipgloss.JoinHorizontal(lipgloss.Top, form, status)
+
+	footer := appBoundaryView(s, m.width, m.form.Help().ShortHelpView(m.form.KeyBinds()))
+
+	return s.Base.Render(header + "\n" + body + "\n\n" + footer)
+}
+
+// diffView renders the current policy document, or its diff to the edited document once a change is generated.
+func (m EditPolicy) diffView() string {
+	if m.loading {
+		return "Loading the current policy…"
+	}
+
+	versions := m.state.GetPolicyVersions()
+	if versions == nil {
+		return "(None)"
+	}
+
+	if m.document == "" {
+		return versions.Document
+	}
+
+	lines := diff.Lines(versions.Document, m.document)
+	if !diff.Changed(lines) {
+		return "(No changes)"
+	}
+	return linesView(lines)
+}
+
+func (m *EditPolicy) reinitializeForm() {
+	doneInitialValue := false
+	m.done = &doneInitialValue
+
+	// Preserve the current message value
+	m.form = huh.NewForm(
+		huh.NewGroup(
+			huh.NewText().
+				Key("message").
+				Title("Describe the Change").Value(m.message),
+			huh.NewConfirm().
+				Key("done").
+				Title("All done?").
+				Value(m.done).
+				Affirmative("Yes").
+				Negative("Refresh"),
+		),
+	).
+		WithWidth(45).
+		WithShowHelp(false).
+		WithShowErrors(false)
+}
@@ -0,0 +1,234 @@
+package policyforms
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+
+	requirements "github.com/Permify/targe/internal/requirements/aws"
+)
+
+// PolicyBuilder builds a custom policy without AI. It first picks the actions of the selected
+// service filtered by access level, and once the resources are selected, the conditions.
+type PolicyBuilder struct {
+	controller Controller
+	state      State
+	lg         *lipgloss.Renderer
+	styles     *Styles
+	form       *huh.Form
+	err        error
+	width      int
+	service    requirements.ServiceActions
+	levels     *[]string
+	actions    *[]string
+	name       *string
+	conditions *string
+	confirm    *bool
+}
+
+func NewPolicyBuilder(controller Controller, state State) PolicyBuilder {
+	m := PolicyBuilder{controller: controller, state: state, width: maxWidth}
+	m.lg = lipgloss.DefaultRenderer()
+	m.styles = NewStyles(m.lg)
+
+	levels := slices.Clone(requirements.AccessLevels)
+	actions := slices.Clone(state.GetActions())
+	name := fmt.Sprintf("targe-%s-%s", requirements.ServicePrefix(state.GetService().Name), time.Now().Format("20060102150405"))
+	conditions := ""
+	m.levels, m.actions, m.name, m.conditions = &levels, &actions, &name, &conditions
+
+	m.service, m.err = controller.CatalogService()
+
+	m.reinitializeForm()
+
+	return m
+}
+
+// picking reports whether the builder picks the actions, otherwise it picks the conditions.
+func (m PolicyBuilder) picking() bool {
+	return len(m.state.GetActions()) == 0
+}
+
+func (m PolicyBuilder) Init() tea.Cmd {
+	return m.form.Init()
+}
+
+func (m PolicyBuilder) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = min(msg.Width, maxWidth) - m.styles.Base.GetHorizontalFrameSize()
+
+	case tea.KeyMsg:
+		if msg.String() == "esc" || msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+	}
+
+	var cmds []tea.Cmd
+
+	// Process the form
+	form, cmd := m.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.form = f
+		cmds = append(cmds, cmd)
+	}
+
+	if m.form.State == huh.StateCompleted {
+		switch {
+		case m.picking() && len(*m.actions) == 0:
+			m.err = errors.New("Please pick at least one action")
+		case m.picking():
+			m.state.SetActions(*m.actions)
+			return next(m.controller)
+		case !*m.confirm:
+			// Start over with the actions
+			m.state.SetActions(nil)
+			m.state.SetResources(nil)
+			return next(m.controller)
+		case strings.TrimSpace(*m.name) == "":
+			m.err = errors.New("Please enter a name for the policy")
+		default:
+			if m.err = m.controller.BuildPolicy(strings.TrimSpace(*m.name), *m.conditions); m.err == nil {
+				return next(m.controller)
+			}
+		}
+
+		m.reinitializeForm()
+		cmds = append(cmds, m.form.Init())
+	}
+
+	return m, tea.Batch(cmds...)
+}
+
+func (m PolicyBuilder) View() string {
+	s := m.styles
+
+	v := strings.TrimSuffix(m.form.View(), "\n\n")
+	form := m.lg.NewStyle().Margin(1, 0).Render(v)
+
+	var titles []string
+	if kind, name, _ := m.controller.Principal(); name != "" {
+		titles = append(titles, s.StateHeader.Render(kind+" Name: "+name))
+	}
+	titles = append(titles, s.StateHeader.Render("Service Name: "+m.state.GetService().Name))
+	titles = append(titles, resourceTitles(s, m.state)...)
+	title := lipgloss.NewStyle().MarginTop(1).Render(lipgloss.JoinVertical(lipgloss.Left, titles...))
+
+	// Status (right side)
+	var status string
+	{
+		const statusWidth = 60
+		statusMarginLeft := m.width - statusWidth - lipgloss.Width(form) - s.Status.GetMarginRight()
+		status = s.Status.
+			Height(lipgloss.Height(form)).
+			Width(statusWidth).
+			MarginLeft(statusMarginLeft).
+			Render(s.StatusHeader.Render("Actions") + "\n" +
+				m.statusView())
+	}
+
+	header := lipgloss.JoinVertical(lipgloss.Top, appBoundaryView(s, m.width, "Policy Builder"), title)
+	if m.err != nil {
+		header = appErrorBoundaryView(s, m.width, m.err.Error())
+	}
+	body := lipgloss.JoinHorizontal(lipgloss.Top, form, status)
+
+	footer := appBoundaryView(s, m.width, m.form.Help().ShortHelpView(m.form.KeyBinds()))
+
+	return s.Base.Render(header + "\n" + body + "\n\n" + footer)
+}
+
+// statusView lists the picked actions.
+func (m PolicyBuilder) statusView() string {
+	if len(*m.actions) == 0 {
+		return "(None)"
+	}
+	return strings.Join(*m.actions, "\n")
+}
+
+// actionOptions returns the actions of the service with one of the selected access levels.
+func (m PolicyBuilder) actionOptions() []huh.Option[string] {
+	var options []huh.Option[string]
+	for _, action := range m.service.Actions {
+		if slices.Contains(*m.levels, action.AccessLevel) {
+			name := m.service.Name + ":" + action.Name
+			options = append(options, huh.NewOption(name, name).Selected(slices.Contains(*m.actions, name)))
+		}
+	}
+	return options
+}
+
+// conditionKeys describes the condition keys of the service for the conditions field.
+func (m PolicyBuilder) conditionKeys() string {
+	description := "One per line: <operator> <key> <value>[,<value>...]."
+	if len(m.service.ConditionKeys) > 0 {
+		description += "\nKeys: " + strings.Join(m.service.ConditionKeys, ", ")
+	}
+	return description
+}
+
+func (m *PolicyBuilder) reinitializeForm() {
+	confirmInitialValue := true
+	m.confirm = &confirmInitialValue
+
+	// Preserve the picked values
+	if m.picking() {
+		levels := make([]huh.Option[string], 0, len(requirements.AccessLevels))
+		for _, level := range requirements.AccessLevels {
+			levels = append(levels, huh.NewOption(level, level).Selected(slices.Contains(*m.levels, level)))
+		}
+
+		m.form = huh.NewForm(
+			huh.NewGroup(
+				huh.NewMultiSelect[string]().
+					Key("levels").
+					Title("Access Levels").
+					Options(levels...).
+					Value(m.levels),
+			),
+			huh.NewGroup(
+				huh.NewMultiSelect[string]().
+					Key("actions").
+					Title("Actions").
+					Description("Press / to filter.").
+					OptionsFunc(m.actionOptions, m.levels).
+					Height(12).
+					Value(m.actions),
+			),
+		).
+			WithWidth(45).
+			WithShowHelp(false).
+			WithShowErrors(false)
+		return
+	}
+
+	m.form = huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Key("name").
+				Title("Policy Name").
+				Value(m.name),
+			huh.NewText().
+				Key("conditions").
+				Title("Conditions").
+				Description(m.conditionKeys()).
+				Placeholder("StringEquals aws:RequestedRegion eu-central-1").
+				Value(m.conditions),
+			huh.NewConfirm().
+				Key("confirm").
+				Title("Build the policy?").
+				Value(m.confirm).
+				Affirmative("Build").
+				Negative("Back"),
+		),
+	).
+		WithWidth(45).
+		WithShowHelp(false).
+		WithShowErrors(false)
+}
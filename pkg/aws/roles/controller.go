@@ -2,28 +2,49 @@ package roles
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"slices"
+	"strings"
+	"time"
 
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
 
+	"github.com/Permify/targe/internal/ai"
+	"github.com/Permify/targe/internal/audit"
 	"github.com/Permify/targe/internal/aws"
+	"github.com/Permify/targe/internal/diff"
+	"github.com/Permify/targe/internal/journal"
+	"github.com/Permify/targe/internal/lease"
+	"github.com/Permify/targe/internal/lint"
 	requirements "github.com/Permify/targe/internal/requirements/aws"
 	"github.com/Permify/targe/pkg/aws/models"
+	"github.com/Permify/targe/pkg/aws/policyforms"
 )
 
 type Controller struct {
-	api          *aws.Api
-	openAiApiKey string
-	State        *State
+	api      *aws.Api
+	provider ai.Provider
+	State    *State
+	// DryRun limits the flow to previewing the IAM calls without applying them.
+	DryRun bool
+	// Force applies custom policies even when the linter reports errors.
+	Force bool
+	// Accounts applies the operation to each of the accounts instead of the current one.
+	Accounts []aws.Account
+	results  []aws.AccountResult
+	// notCreated are the accounts the role created with the "Create new" entry is missing from.
+	notCreated []aws.AccountResult
 }
 
-func NewController(api *aws.Api, openaiApiKey string, state *State) *Controller {
+func NewController(api *aws.Api, provider ai.Provider, state *State) *Controller {
 	return &Controller{
-		api:          api,
-		openAiApiKey: openaiApiKey,
-		State:        state,
+		api:      api,
+		provider: provider,
+		State:    state,
 	}
 }
 
@@ -32,27 +53,190 @@ type FailedMsg struct {
 	Err error
 }
 
-type RoleLoadedMsg struct{ List []list.Item }
+// RoleLoadedMsg carries a page of roles. Next loads the following page and is nil on the last one.
+type RoleLoadedMsg struct {
+	List []list.Item
+	Next tea.Cmd
+}
 
-// LoadRoles loads roles from the AWS API.
+// LoadRoles loads roles from the AWS API page by page. The first page starts with the entry
+// that creates a new role, unless the flow only previews its calls.
 func (c *Controller) LoadRoles() tea.Cmd {
+	load := c.loadRolesPage(c.api.RolePager())
+	return func() tea.Msg {
+		msg := load()
+		if loaded, ok := msg.(RoleLoadedMsg); ok && !c.DryRun {
+			loaded.List = append([]list.Item{models.CreateNew{Kind: "role"}}, loaded.List...)
+			return loaded
+		}
+		return msg
+	}
+}
+
+// RoleCreatedMsg carries the role created with the "Create new" entry.
+type RoleCreatedMsg struct {
+	Role models.Role
+}
+
+func (c *Controller) loadRolesPage(pager *aws.Pager[types.Role]) tea.Cmd {
 	return func() tea.Msg {
 		var items []list.Item
 
-		output, err := c.api.ListRoles(context.Background())
+		roles, err := pager.NextPage(context.Background())
 		if err != nil {
 			return FailedMsg{Err: err}
 		}
 
-		for _, role := range output.Roles {
+		for _, role := range roles {
 			items = append(items, models.Role{
 				Name: *role.RoleName,
 				Arn:  *role.Arn,
 			})
 		}
 
-		return RoleLoadedMsg{List: items}
+		msg := RoleLoadedMsg{List: items}
+		if pager.HasMorePages() {
+			msg.Next = c.loadRolesPage(pager)
+		}
+		return msg
+	}
+}
+
+// CreateRole creates the role with the trust policy of the template in the current account, or in
+// each of the accounts, and records it.
+func (c *Controller) CreateRole(name, path, template string, tags map[string]string) tea.Cmd {
+	return func() tea.Msg {
+		var created *types.Role
+		failed, err := c.create(CreateRoleOperation, name, func(api *aws.Api) (aws.Call, error) {
+			trustPolicy, err := trustTemplateDocument(api, template)
+			if err != nil {
+				return aws.Call{}, err
+			}
+			role, err := api.CreateRole(context.Background(), name, path, trustPolicy, tags)
+			if err != nil {
+				return aws.Call{}, err
+			}
+			if created == nil {
+				created = role
+			}
+			return aws.NewCall(aws.CreateRoleAction, "RoleName", name, "Path", path, "AssumeRolePolicyDocument", trustPolicy), nil
+		})
+		if created == nil {
+			return FailedMsg{Err: err}
+		}
+
+		// The flow continues with the role even when it could not be created in every account, the
+		// overview lists the accounts it is missing from
+		c.notCreated = failed
+		return RoleCreatedMsg{Role: models.Role{Name: *created.RoleName, Arn: *created.Arn}}
+	}
+}
+
+// TrustTemplate is a starting trust policy for a new role.
+type TrustTemplate struct {
+	Id            string
+	Name          string
+	PrincipalType string
+	// Principal is the trusted principal, empty for the root of the account the role is created in.
+	Principal string
+}
+
+// TrustTemplates lists the trust policies a new role can start with.
+var TrustTemplates = []TrustTemplate{
+	{Id: "ec2", Name: "EC2 instances", PrincipalType: ai.PrincipalService, Principal: "ec2.amazonaws.com"},
+	{Id: "lambda", Name: "Lambda functions", PrincipalType: ai.PrincipalService, Principal: "lambda.amazonaws.com"},
+	{Id: "ecs-tasks", Name: "ECS tasks", PrincipalType: ai.PrincipalService, Principal: "ecs-tasks.amazonaws.com"},
+	{Id: "account", Name: "Principals of this account", PrincipalType: ai.PrincipalAWS},
+}
+
+// trustTemplateDocument builds the trust policy of the template for the account of the api.
+func trustTemplateDocument(api *aws.Api, id string) (string, error) {
+	for _, template := range TrustTemplates {
+		if template.Id != id {
+			continue
+		}
+
+		principal := template.Principal
+		if principal == "" {
+			arn, account, err := api.CallerIdentity(context.Background())
+			if err != nil {
+				return "", err
+			}
+			partition := "aws"
+			if parts := strings.Split(arn, ":"); len(parts) > 1 {
+				partition = parts[1]
+			}
+			principal = fmt.Sprintf("arn:%s:iam::%s:root", partition, account)
+		}
+
+		policy := ai.IAMPolicy{Version: "2012-10-17"}
+		policy.AddTrustPrincipal(template.PrincipalType, principal)
+		document, err := json.MarshalIndent(policy, "", "\t")
+		if err != nil {
+			return "", err
+		}
+		return string(document), nil
+	}
+	return "", fmt.Errorf("unknown trust policy template '%s'", id)
+}
+
+// create runs the creation of a role in the current account, or in each of the accounts. Every
+// creation is journaled, so it can be undone, and written to the audit log. The accounts the
+// creation failed in are returned with their error.
+func (c *Controller) create(operation, name string, create func(api *aws.Api) (aws.Call, error)) ([]aws.AccountResult, error) {
+	accounts := c.Accounts
+	if len(accounts) == 0 {
+		accounts = []aws.Account{{Api: c.api}}
+	}
+
+	var errs []error
+	var failed []aws.AccountResult
+	for _, account := range accounts {
+		var applied []aws.Call
+		call, err := create(account.Api)
+		if err == nil {
+			applied = append(applied, call)
+			_, jerr := journal.Append(journal.Entry{
+				Flow:      "roles",
+				Operation: operation,
+				Principal: name,
+				Inverse:   aws.Inverse(applied),
+				RoleArn:   account.RoleArn,
+			})
+			if jerr != nil {
+				err = fmt.Errorf("operation could not be recorded for undo: %w", jerr)
+			}
+		}
+
+		entry := audit.Entry{
+			Caller:    "unknown",
+			Flow:      "roles",
+			Operation: operation,
+			Principal: name,
+			Calls:     applied,
+			Outcome:   audit.OutcomeSuccess,
+		}
+		if arn, id, ierr := account.Api.CallerIdentity(context.Background()); ierr == nil {
+			entry.Caller, entry.Account = arn, id
+		}
+		if err != nil {
+			entry.Outcome = audit.OutcomeFailure
+			entry.Error = err.Error()
+		}
+		if aerr := audit.Record(entry); aerr != nil {
+			err = errors.Join(err, aerr)
+		}
+
+		if err != nil {
+			failed = append(failed, aws.AccountResult{Account: account, Err: err})
+			if account.Id != "" {
+				err = fmt.Errorf("account %s: %w", account.Id, err)
+			}
+			errs = append(errs, err)
+		}
 	}
+
+	return failed, errors.Join(errs...)
 }
 
 type OperationLoadedMsg struct{ List []list.Item }
@@ -64,6 +248,16 @@ func (c *Controller) LoadOperations() tea.Cmd {
 			models.Operation{Id: AttachPolicySlug.String(), Name: ReachableOperations[AttachPolicySlug].Name, Desc: ReachableOperations[AttachPolicySlug].Desc},
 			models.Operation{Id: DetachPolicySlug.String(), Name: ReachableOperations[DetachPolicySlug].Name, Desc: ReachableOperations[DetachPolicySlug].Desc},
 			models.Operation{Id: AttachCustomPolicySlug.String(), Name: ReachableOperations[AttachCustomPolicySlug].Name, Desc: ReachableOperations[AttachCustomPolicySlug].Desc},
+			models.Operation{Id: EditPolicySlug.String(), Name: ReachableOperations[EditPolicySlug].Name, Desc: ReachableOperations[EditPolicySlug].Desc},
+			models.Operation{Id: AttachInlinePolicySlug.String(), Name: ReachableOperations[AttachInlinePolicySlug].Name, Desc: ReachableOperations[AttachInlinePolicySlug].Desc},
+			models.Operation{Id: RemoveInlinePolicySlug.String(), Name: ReachableOperations[RemoveInlinePolicySlug].Name, Desc: ReachableOperations[RemoveInlinePolicySlug].Desc},
+			models.Operation{Id: SetBoundarySlug.String(), Name: ReachableOperations[SetBoundarySlug].Name, Desc: ReachableOperations[SetBoundarySlug].Desc},
+			models.Operation{Id: RemoveBoundarySlug.String(), Name: ReachableOperations[RemoveBoundarySlug].Name, Desc: ReachableOperations[RemoveBoundarySlug].Desc},
+		}
+
+		// A trust policy names the principals of its own account, it is only edited in a single account
+		if len(c.Accounts) == 0 {
+			items = slices.Insert(items, 6, list.Item(models.Operation{Id: EditTrustPolicySlug.String(), Name: ReachableOperations[EditTrustPolicySlug].Name, Desc: ReachableOperations[EditTrustPolicySlug].Desc}))
 		}
 		return OperationLoadedMsg{List: items}
 	}
@@ -92,102 +286,142 @@ func (c *Controller) LoadServices() tea.Cmd {
 	}
 }
 
-type ResourceLoadedMsg struct{ List []list.Item }
+// ResourceLoadedMsg carries a page of resources. Next loads the following page and is nil on the last one.
+type ResourceLoadedMsg struct {
+	List []list.Item
+	Next tea.Cmd
+}
 
-// LoadResources loads resources.
+// LoadResources loads the resources of the selected service page by page, narrowed down by the resource query.
 func (c *Controller) LoadResources() tea.Cmd {
 	return func() tea.Msg {
-		items := []list.Item{
-			models.Resource{Name: "All Resources", Arn: "*"},
+		query := aws.ResourceQuery{Type: c.State.GetService().Name}
+		if q := c.State.GetResourceQuery(); q != nil {
+			query.Text, query.Tags = q.Text, q.Tags
 		}
 
-		resources, err := c.api.ListResources(c.State.GetService().Name)
+		pager, err := c.api.ResourcePager(context.Background(), query)
 		if err != nil {
 			return FailedMsg{Err: err}
 		}
 
-		for _, resource := range resources {
-			items = append(items, models.Resource{
-				Name: resource.Name,
-				Arn:  resource.Arn,
-			})
+		msg := c.loadResourcesPage(pager)()
+		if loaded, ok := msg.(ResourceLoadedMsg); ok {
+			loaded.List = append([]list.Item{models.Resource{Name: "All Resources", Arn: "*"}}, loaded.List...)
+			return loaded
 		}
-
-		return ResourceLoadedMsg{List: items}
+		return msg
 	}
 }
 
-type PolicyLoadedMsg struct{ List []list.Item }
-
-// LoadPolicies loads policies.
-func (c *Controller) LoadPolicies() tea.Cmd {
+func (c *Controller) loadResourcesPage(pager *aws.Pager[aws.Resource]) tea.Cmd {
 	return func() tea.Msg {
 		var items []list.Item
 
-		policies, err := c.api.ListPolicies(context.Background())
+		resources, err := pager.NextPage(context.Background())
 		if err != nil {
 			return FailedMsg{Err: err}
 		}
 
-		mp := requirements.ManagedPolicies{}
-		managedPolicies, err := mp.GetPolicies()
-		if err != nil {
-			return FailedMsg{Err: err}
+		for _, resource := range resources {
+			items = append(items, models.Resource{
+				Name:   resource.Name,
+				Arn:    resource.Arn,
+				Region: resource.Region,
+				Tags:   resource.Tags,
+			})
 		}
 
-		attachedPolicies, err := c.api.ListAttachedRolePolicies(context.Background(), c.State.role.Name)
-		if err != nil {
-			return FailedMsg{Err: err}
+		msg := ResourceLoadedMsg{List: items}
+		if pager.HasMorePages() {
+			msg.Next = c.loadResourcesPage(pager)
 		}
+		return msg
+	}
+}
 
-		switch c.State.operation.Id {
-		case AttachPolicySlug.String():
-			for _, policy := range policies.Policies {
-				if !slices.Contains(attachedPolicies, *policy.PolicyName) {
-					items = append(items, models.Policy{
-						Name: *policy.PolicyName,
-						Arn:  *policy.Arn,
-					})
-				}
-			}
+// PolicyLoadedMsg carries a page of policies. Next loads the following page and is nil on the last one.
+type PolicyLoadedMsg struct {
+	List []list.Item
+	Next tea.Cmd
+}
 
-			for _, policy := range managedPolicies {
-				if !slices.Contains(attachedPolicies, policy.Name) {
-					items = append(items, models.Policy{
-						Name: policy.Name,
-						Arn:  policy.Arn,
-					})
-				}
-			}
-		case DetachPolicySlug.String():
+// LoadPolicies loads policies. Customer managed policies are streamed page by page,
+// AWS managed policies are added with the last page. Removing an inline policy lists
+// the inline policies of the role instead.
+func (c *Controller) LoadPolicies() tea.Cmd {
+	return func() tea.Msg {
+		if c.State.operation.Id == RemoveInlinePolicySlug.String() {
 			inlinePolicies, err := c.api.ListRoleInlinePolicies(context.Background(), c.State.role.Name)
 			if err != nil {
 				return FailedMsg{Err: err}
 			}
 
+			var items []list.Item
 			for _, name := range inlinePolicies {
 				items = append(items, models.Policy{
-					Name: name,
-					Arn:  "inline",
+					Name:   name,
+					Inline: true,
 				})
 			}
+			return PolicyLoadedMsg{List: items}
+		}
 
-			for _, policy := range policies.Policies {
-				if slices.Contains(attachedPolicies, *policy.PolicyName) {
-					items = append(items, models.Policy{
-						Name: *policy.PolicyName,
-						Arn:  *policy.Arn,
-					})
-				}
+		attachedPolicies, err := c.api.ListAttachedRolePolicies(context.Background(), c.State.role.Name)
+		if err != nil {
+			return FailedMsg{Err: err}
+		}
+
+		return c.loadPoliciesPage(c.api.PolicyPager(), attachedPolicies)()
+	}
+}
+
+func (c *Controller) loadPoliciesPage(pager *aws.Pager[types.Policy], attachedPolicies []string) tea.Cmd {
+	return func() tea.Msg {
+		var items []list.Item
+
+		// Attach offers the policies that are not attached yet, detach and edit the attached ones,
+		// any policy can be a permissions boundary
+		attached := c.State.operation.Id == DetachPolicySlug.String() || c.State.operation.Id == EditPolicySlug.String()
+		offered := func(name string) bool {
+			return c.State.operation.Id == SetBoundarySlug.String() || slices.Contains(attachedPolicies, name) == attached
+		}
+
+		policies, err := pager.NextPage(context.Background())
+		if err != nil {
+			return FailedMsg{Err: err}
+		}
+
+		for _, policy := range policies {
+			if offered(*policy.PolicyName) {
+				items = append(items, models.Policy{
+					Name: *policy.PolicyName,
+					Arn:  *policy.Arn,
+				})
 			}
+		}
 
-			for _, policy := range managedPolicies {
-				if slices.Contains(attachedPolicies, policy.Name) {
-					items = append(items, models.Policy{
-						Name: policy.Name,
-						Arn:  policy.Arn,
-					})
-				}
+		if pager.HasMorePages() {
+			return PolicyLoadedMsg{List: items, Next: c.loadPoliciesPage(pager, attachedPolicies)}
+		}
+
+		// AWS managed policies cannot be edited
+		if c.State.operation.Id == EditPolicySlug.String() {
+			return PolicyLoadedMsg{List: items}
+		}
+
+		mp := requirements.ManagedPolicies{}
+		managedPolicies, err := mp.GetPolicies()
+		if err != nil {
+			return FailedMsg{Err: err}
+		}
+
+		for _, policy := range managedPolicies {
+			if offered(policy.Name) {
+				items = append(items, models.Policy{
+					Name: policy.Name,
+					Arn:  policy.Arn,
+				})
 			}
 		}
 
@@ -195,19 +429,399 @@ func (c *Controller) LoadPolicies() tea.Cmd {
 	}
 }
 
+// InspectPolicy loads the versions of the selected policy and the document of its default version into the state.
+func (c *Controller) InspectPolicy(ctx context.Context) error {
+	policy := c.State.GetPolicy()
+	if strings.Contains(policy.Arn, ":aws:policy/") {
+		return fmt.Errorf("%s is an AWS managed policy, only customer managed policies can be edited", policy.Name)
+	}
+
+	versions, err := c.api.ListPolicyVersions(ctx, policy.Arn)
+	if err != nil {
+		return fmt.Errorf("failed to list the versions of %s: %w", policy.Name, err)
+	}
+
+	defaultVersion := aws.DefaultPolicyVersion(versions)
+	document, err := c.api.GetPolicyDocument(ctx, policy.Arn, defaultVersion)
+	if err != nil {
+		return fmt.Errorf("failed to read the default version of %s: %w", policy.Name, err)
+	}
+
+	c.State.SetPolicyVersions(&models.PolicyVersions{
+		Default:  defaultVersion,
+		Document: document,
+		Oldest:   aws.OldestPolicyVersion(versions),
+		Count:    len(versions),
+	})
+	return nil
+}
+
+// BoundaryLoadedMsg signals that the permissions boundary of the role is loaded.
+type BoundaryLoadedMsg struct{}
+
+// LoadBoundary loads the permissions boundary of the role.
+func (c *Controller) LoadBoundary() tea.Cmd {
+	return func() tea.Msg {
+		if err := c.InspectBoundary(context.Background()); err != nil {
+			return FailedMsg{Err: err}
+		}
+		return BoundaryLoadedMsg{}
+	}
+}
+
+// InspectBoundary loads the permissions boundary of the role into the state. When the operation
+// attaches a policy, the actions it allows beyond the boundary are listed too.
+func (c *Controller) InspectBoundary(ctx context.Context) error {
+	role := c.State.GetRole()
+	boundaryArn, err := c.api.GetRolePermissionsBoundary(ctx, role.Name)
+	if err != nil {
+		return fmt.Errorf("failed to read the permissions boundary of %s: %w", role.Name, err)
+	}
+
+	boundary := &models.Boundary{Arn: boundaryArn}
+	if boundaryArn != "" && c.attaching() {
+		if boundary.Outside, err = c.actionsOutside(ctx, boundaryArn); err != nil {
+			return err
+		}
+	}
+
+	c.State.SetBoundary(boundary)
+	return nil
+}
+
+// attaching reports whether the operation grants the selected policy to the role.
+func (c *Controller) attaching() bool {
+	if c.State.operation == nil || c.State.policy == nil {
+		return false
+	}
+	switch c.State.operation.Id {
+	case AttachPolicySlug.String(), AttachCustomPolicySlug.String(), AttachInlinePolicySlug.String():
+		return true
+	default:
+		return false
+	}
+}
+
+// actionsOutside returns the actions the selected policy allows beyond the boundary.
+func (c *Controller) actionsOutside(ctx context.Context, boundaryArn string) ([]string, error) {
+	boundaryDocument, err := c.api.GetDefaultPolicyDocument(ctx, boundaryArn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the permissions boundary %s: %w", boundaryArn, err)
+	}
+
+	// Custom policies do not exist yet, their document is in the state
+	document := c.policyDocument()
+	if document == "" {
+		if document, err = c.api.GetDefaultPolicyDocument(ctx, c.State.policy.Arn); err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", c.State.policy.Name, err)
+		}
+	}
+
+	var boundary, policy ai.IAMPolicy
+	if err := json.Unmarshal([]byte(boundaryDocument), &boundary); err != nil {
+		return nil, fmt.Errorf("failed to parse the permissions boundary %s: %w", boundaryArn, err)
+	}
+	if err := json.Unmarshal([]byte(document), &policy); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", c.State.policy.Name, err)
+	}
+
+	return policy.ActionsOutside(boundary), nil
+}
+
+// SimulationLoadedMsg signals that the policy simulation has run.
+type SimulationLoadedMsg struct{}
+
+// LoadSimulation runs the policy simulator for the selected policy.
+func (c *Controller) LoadSimulation() tea.Cmd {
+	return func() tea.Msg {
+		if err := c.Simulate(context.Background()); err != nil {
+			return FailedMsg{Err: fmt.Errorf("access was not simulated: %w", err)}
+		}
+		return SimulationLoadedMsg{}
+	}
+}
+
+// Simulate runs the policy simulator for the actions the selected policy allows, on the selected
+// resources if there are any. A new policy document is simulated with SimulateCustomPolicy, limited by
+// the permissions boundary of the role. An existing policy is simulated with SimulatePrincipalPolicy as
+// if it were attached, so the other policies, the boundary and service control policies apply too.
+func (c *Controller) Simulate(ctx context.Context) error {
+	if !c.attaching() {
+		return nil
+	}
+
+	// Custom policies do not exist yet, their document is in the state
+	document := c.policyDocument()
+	custom := document != ""
+	if !custom {
+		var err error
+		if document, err = c.api.GetDefaultPolicyDocument(ctx, c.State.policy.Arn); err != nil {
+			return fmt.Errorf("failed to read %s: %w", c.State.policy.Name, err)
+		}
+	}
+
+	policy, err := ai.ParsePolicy(document)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", c.State.policy.Name, err)
+	}
+
+	actions := policy.SimulatableActions()
+	if len(actions) == 0 {
+		c.State.SetSimulation([]aws.Simulation{})
+		return nil
+	}
+
+	resources := c.resourceArns()
+
+	var simulation []aws.Simulation
+	if custom {
+		boundaries, berr := c.boundaryDocuments(ctx)
+		if berr != nil {
+			return berr
+		}
+		simulation, err = c.api.SimulateCustomPolicy(ctx, []string{document}, boundaries, actions, resources)
+	} else {
+		simulation, err = c.api.SimulatePrincipalPolicy(ctx, c.State.GetRole().Arn, actions, resources, []string{document})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to simulate %s: %w", c.State.policy.Name, err)
+	}
+
+	c.State.SetSimulation(simulation)
+	return nil
+}
+
+// boundaryDocuments returns the document of the permissions boundary of the role, if it has one.
+func (c *Controller) boundaryDocuments(ctx context.Context) ([]string, error) {
+	boundaryArn, err := c.api.GetRolePermissionsBoundary(ctx, c.State.GetRole().Name)
+	if err != nil || boundaryArn == "" {
+		return nil, err
+	}
+
+	document, err := c.api.GetDefaultPolicyDocument(ctx, boundaryArn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the permissions boundary %s: %w", boundaryArn, err)
+	}
+	return []string{document}, nil
+}
+
+// TrustPolicyLoadedMsg signals that the trust policy of the role is loaded.
+type TrustPolicyLoadedMsg struct{}
+
+// LoadTrustPolicy loads the trust policy of the role.
+func (c *Controller) LoadTrustPolicy() tea.Cmd {
+	return func() tea.Msg {
+		if err := c.InspectTrustPolicy(context.Background()); err != nil {
+			return FailedMsg{Err: err}
+		}
+		return TrustPolicyLoadedMsg{}
+	}
+}
+
+// InspectTrustPolicy loads the trust policy of the role into the state.
+func (c *Controller) InspectTrustPolicy(ctx context.Context) error {
+	document, err := c.api.GetAssumeRolePolicy(ctx, c.State.GetRole().Name)
+	if err != nil {
+		return fmt.Errorf("failed to read the trust policy of %s: %w", c.State.GetRole().Name, err)
+	}
+
+	c.State.SetTrustPolicy(&models.TrustPolicy{Current: indentDocument(document)})
+	return nil
+}
+
+// ChangeTrustPrincipal adds the principal to the trust policy in the state, or removes it.
+// Changes accumulate on top of the previous ones.
+func (c *Controller) ChangeTrustPrincipal(add bool, principalType, principal string) error {
+	policy, err := c.trustPolicy()
+	if err != nil {
+		return err
+	}
+
+	if add && !policy.AddTrustPrincipal(principalType, principal) {
+		return fmt.Errorf("%s %s can already assume the role", principalType, principal)
+	}
+	if !add && !policy.RemoveTrustPrincipal(principalType, principal) {
+		return fmt.Errorf("%s %s is not a principal of the trust policy", principalType, principal)
+	}
+
+	return c.setTrustDocument(policy)
+}
+
+// GenerateTrustPolicy changes the trust policy in the state as described in natural language.
+func (c *Controller) GenerateTrustPolicy(change string) error {
+	policy, err := ai.GenerateTrustPolicy(c.provider, c.trustDocument(), change)
+	if err != nil {
+		return err
+	}
+	return c.setTrustDocument(policy)
+}
+
+// trustDocument returns the changed trust policy, or the current one when nothing has changed yet.
+func (c *Controller) trustDocument() string {
+	trust := c.State.GetTrustPolicy()
+	if trust.Document != "" {
+		return trust.Document
+	}
+	return trust.Current
+}
+
+func (c *Controller) trustPolicy() (*ai.IAMPolicy, error) {
+	policy, err := ai.ParsePolicy(c.trustDocument())
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the trust policy: %w", err)
+	}
+	return &policy, nil
+}
+
+func (c *Controller) setTrustDocument(policy any) error {
+	document, err := json.Marshal(policy)
+	if err != nil {
+		return err
+	}
+	c.State.GetTrustPolicy().Document = indentDocument(string(document))
+	return nil
+}
+
+// indentDocument formats a policy document with sorted keys, so documents from IAM and from
+// targe can be compared and diffed line by line.
+func indentDocument(document string) string {
+	var v interface{}
+	if err := json.Unmarshal([]byte(document), &v); err != nil {
+		return document
+	}
+	indented, err := json.MarshalIndent(v, "", "\t")
+	if err != nil {
+		return document
+	}
+	return string(indented)
+}
+
 type PolicyOptionLoadedMsg struct{ List []list.Item }
 
+// Findings lints the custom policy document the operation writes.
+func (c *Controller) Findings() []lint.Finding {
+	if c.State.operation == nil || c.State.policy == nil || c.State.policy.Document == "" {
+		return nil
+	}
+	switch c.State.operation.Id {
+	case AttachCustomPolicySlug.String(), AttachInlinePolicySlug.String(), EditPolicySlug.String():
+	default:
+		return nil
+	}
+
+	policy, err := ai.ParsePolicy(c.State.policy.Document)
+	if err != nil {
+		return nil
+	}
+
+	// Actions are checked against the catalog when it can be loaded
+	var catalog lint.Catalog
+	if loaded, err := requirements.LoadCatalog(); err == nil {
+		catalog = loaded
+	}
+	return lint.Lint(policy, catalog)
+}
+
+// CatalogService returns the actions of the selected service from the action catalog.
+func (c *Controller) CatalogService() (requirements.ServiceActions, error) {
+	catalog, err := requirements.LoadCatalog()
+	if err != nil {
+		return requirements.ServiceActions{}, err
+	}
+
+	prefix := requirements.ServicePrefix(c.State.service.Name)
+	service, ok := catalog.Service(prefix)
+	if !ok {
+		return service, fmt.Errorf("the action catalog does not describe %s, install the requirements with network access to get every service", prefix)
+	}
+	return service, nil
+}
+
+// BuildPolicy creates the custom policy from the actions picked in the builder, the selected
+// resources and the conditions, see ai.ParseConditions. Actions that do not apply to a resource
+// type are allowed on "*" instead of the resources.
+func (c *Controller) BuildPolicy(name, conditions string) error {
+	condition, err := ai.ParseConditions(conditions)
+	if err != nil {
+		return err
+	}
+
+	resources := c.resourceArns()
+	if len(resources) == 0 {
+		resources = []string{"*"}
+	}
+
+	var actions, unscoped []string
+	catalog, _ := requirements.LoadCatalog()
+	for _, action := range c.State.actions {
+		if catalog != nil && !slices.Contains(resources, "*") && catalog.Unscoped(action) {
+			unscoped = append(unscoped, action)
+		} else {
+			actions = append(actions, action)
+		}
+	}
+
+	document, err := json.MarshalIndent(ai.BuildPolicy(name, actions, resources, unscoped, condition), "", "\t")
+	if err != nil {
+		return err
+	}
+
+	c.SetCustomPolicy(name, string(document))
+	return nil
+}
+
+// SetCustomPolicy selects a new policy with the document, inline when the operation attaches an inline policy.
+func (c *Controller) SetCustomPolicy(name, document string) {
+	c.State.SetPolicy(&models.Policy{
+		Arn:      "new",
+		Name:     name,
+		Document: document,
+		Inline:   c.State.operation.Id == AttachInlinePolicySlug.String(),
+	})
+}
+
+// resourceArns returns the ARNs of the selected resources.
+func (c *Controller) resourceArns() []string {
+	var arns []string
+	for _, resource := range c.State.resources {
+		arns = append(arns, resource.Arn)
+	}
+	return arns
+}
+
+// Principal returns the kind, the name and the ARN of the selected role for the titles of the policy forms.
+func (c *Controller) Principal() (kind, name, arn string) {
+	if c.State.role == nil {
+		return "Role", "", ""
+	}
+	return "Role", c.State.role.Name, c.State.role.Arn
+}
+
+// Provider returns the AI provider, nil when none is configured.
+func (c *Controller) Provider() ai.Provider {
+	return c.provider
+}
+
 // LoadPolicyOptions loads operations.
 func (c *Controller) LoadPolicyOptions() tea.Cmd {
 	return func() tea.Msg {
 		items := []list.Item{
 			models.PolicyOption{Id: WithoutResourceSlug.String(), Name: ReachablePolicyOptions[WithoutResourceSlug].Name, Desc: ReachablePolicyOptions[WithoutResourceSlug].Desc},
 			models.PolicyOption{Id: WithResourceSlug.String(), Name: ReachablePolicyOptions[WithResourceSlug].Name, Desc: ReachablePolicyOptions[WithResourceSlug].Desc},
+			models.PolicyOption{Id: BuildSlug.String(), Name: ReachablePolicyOptions[BuildSlug].Name, Desc: ReachablePolicyOptions[BuildSlug].Desc},
+		}
+
+		// Policies are only generated when an AI provider is configured, the builder works without one
+		if c.provider == nil {
+			items = items[2:]
 		}
 		return PolicyOptionLoadedMsg{List: items}
 	}
 }
 
+// CreateRoleOperation is the operation recorded in the journal and the audit log when a role is created.
+const CreateRoleOperation = "create_role"
+
 type OperationType string
 
 // Constants representing role actions and their slugs
@@ -215,12 +829,21 @@ const (
 	AttachPolicySlug       OperationType = "attach_policy"
 	DetachPolicySlug       OperationType = "detach_policy"
 	AttachCustomPolicySlug OperationType = "attach_custom_policy"
+	EditPolicySlug         OperationType = "edit_policy"
+	AttachInlinePolicySlug OperationType = "attach_inline_policy"
+	RemoveInlinePolicySlug OperationType = "remove_inline_policy"
+	EditTrustPolicySlug    OperationType = "edit_trust_policy"
+	SetBoundarySlug        OperationType = "set_permissions_boundary"
+	RemoveBoundarySlug     OperationType = "remove_permissions_boundary"
 )
 
 func (o OperationType) String() string {
 	return string(o)
 }
 
+// TemporaryOperations lists the operations that can grant access for a limited time.
+var TemporaryOperations = []OperationType{AttachPolicySlug, AttachCustomPolicySlug, AttachInlinePolicySlug}
+
 // ReachableOperations Predefined list of actions with their names and descriptions
 var ReachableOperations = map[OperationType]models.Operation{
 	AttachPolicySlug: {
@@ -238,6 +861,36 @@ var ReachableOperations = map[OperationType]models.Operation{
 		Name: "Attach Custom Policy (attach_custom_policy)",
 		Desc: "Create and attach a custom policy.",
 	},
+	EditPolicySlug: {
+		Id:   EditPolicySlug.String(),
+		Name: "Edit Existing Policy (edit_policy)",
+		Desc: "Change an attached customer managed policy.",
+	},
+	AttachInlinePolicySlug: {
+		Id:   AttachInlinePolicySlug.String(),
+		Name: "Attach Inline Policy (attach_inline_policy)",
+		Desc: "Embed a custom policy in the role.",
+	},
+	RemoveInlinePolicySlug: {
+		Id:   RemoveInlinePolicySlug.String(),
+		Name: "Remove Inline Policy (remove_inline_policy)",
+		Desc: "Delete an inline policy from the role.",
+	},
+	EditTrustPolicySlug: {
+		Id:   EditTrustPolicySlug.String(),
+		Name: "Edit Trust Policy (edit_trust_policy)",
+		Desc: "Change who can assume the role.",
+	},
+	SetBoundarySlug: {
+		Id:   SetBoundarySlug.String(),
+		Name: "Set Permissions Boundary (set_permissions_boundary)",
+		Desc: "Set or change the policy that limits the role's permissions.",
+	},
+	RemoveBoundarySlug: {
+		Id:   RemoveBoundarySlug.String(),
+		Name: "Remove Permissions Boundary (remove_permissions_boundary)",
+		Desc: "Remove the permissions boundary from the role.",
+	},
 }
 
 type PolicyOptionType string
@@ -246,6 +899,7 @@ type PolicyOptionType string
 const (
 	WithoutResourceSlug PolicyOptionType = "without_resource"
 	WithResourceSlug    PolicyOptionType = "with_resource"
+	BuildSlug           PolicyOptionType = "build"
 )
 
 func (o PolicyOptionType) String() string {
@@ -264,6 +918,11 @@ var ReachablePolicyOptions = map[PolicyOptionType]models.PolicyOption{
 		Name: "With Resource (with_resource)",
 		Desc: "Scoped to a specific resource.",
 	},
+	BuildSlug: {
+		Id:   BuildSlug.String(),
+		Name: "Build Manually (build)",
+		Desc: "Pick the actions, resource and conditions yourself.",
+	},
 }
 
 // Next determines the next step based on the current state.
@@ -278,8 +937,21 @@ func (c *Controller) Next() tea.Model {
 		return NewOperationList(c)
 	}
 
-	// Handle specific action: AttachCustomPolicySlug
-	if c.State.operation.Id == AttachCustomPolicySlug.String() {
+	// Removing the permissions boundary needs no policy
+	if c.State.operation.Id == RemoveBoundarySlug.String() {
+		return NewResult(c)
+	}
+
+	// Handle trust policy changes, which need no permission policy
+	if c.State.operation.Id == EditTrustPolicySlug.String() {
+		if c.State.trustPolicy == nil || c.State.trustPolicy.Document == "" {
+			return NewTrustPolicy(c)
+		}
+		return NewResult(c)
+	}
+
+	// Handle custom policies, created as managed or inline policies
+	if c.State.operation.Id == AttachCustomPolicySlug.String() || c.State.operation.Id == AttachInlinePolicySlug.String() {
 
 		if c.State.policy != nil {
 			return NewResult(c)
@@ -289,12 +961,12 @@ func (c *Controller) Next() tea.Model {
 		if c.State.policyOption != nil {
 			switch c.State.policyOption.Id {
 			case WithoutResourceSlug.String():
-				return NewCreatePolicy(c)
+				return policyforms.NewCreatePolicy(c, c.State)
 
 			case WithResourceSlug.String():
-				// Handle case where resource is defined
-				if c.State.resource != nil {
-					return NewCreatePolicy(c)
+				// Handle case where resources are defined
+				if len(c.State.resources) > 0 {
+					return policyforms.NewCreatePolicy(c, c.State)
 				}
 
 				// Handle case where service is defined
@@ -303,11 +975,22 @@ func (c *Controller) Next() tea.Model {
 				}
 				// If service is not defined
 				return NewServiceList(c)
+
+			case BuildSlug.String():
+				if c.State.service == nil {
+					return NewServiceList(c)
+				}
+
+				// The actions of the service are picked before the resources, the conditions after them
+				if len(c.State.actions) == 0 || len(c.State.resources) > 0 {
+					return policyforms.NewPolicyBuilder(c, c.State)
+				}
+				return NewResourceList(c)
 			}
 		} else {
-			// Handle case where resource is defined
-			if c.State.resource != nil {
-				return NewCreatePolicy(c)
+			// Handle case where resources are defined
+			if len(c.State.resources) > 0 {
+				return policyforms.NewCreatePolicy(c, c.State)
 			}
 
 			// Handle case where service is defined
@@ -324,27 +1007,474 @@ func (c *Controller) Next() tea.Model {
 		return NewPolicyList(c)
 	}
 
+	// Handle case where the change to the selected policy is not described yet
+	if c.State.operation.Id == EditPolicySlug.String() && c.State.policy.Document == "" {
+		return policyforms.NewEditPolicy(c, c.State)
+	}
+
 	// Default fallback
 	return NewResult(c)
 }
 
+// Done applies the operation, records its inverse in the journal so it can be undone,
+// and writes the outcome to the audit log.
 func (c *Controller) Done() error {
+	// Errors of the linter block the policy unless it is forced
+	if !c.Force && lint.Blocking(c.Findings()) {
+		return errors.New("the policy has lint errors, fix them or apply it with --force")
+	}
+
+	if len(c.Accounts) == 0 {
+		return c.done(aws.Account{Api: c.api})
+	}
+
+	// A failure in one account does not stop the others
+	c.results = nil
+	var errs []error
+	for _, account := range c.Accounts {
+		err := c.done(account)
+		c.results = append(c.results, aws.AccountResult{Account: account, Err: err})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("account %s: %w", account.Id, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Results returns the outcome per account once Done has run for several accounts.
+func (c *Controller) Results() []aws.AccountResult {
+	return c.results
+}
+
+// done applies the operation in a single account and records it.
+func (c *Controller) done(account aws.Account) error {
+	applied, err := c.apply(account)
+
+	// Partially applied operations are journaled too, so they can be cleaned up with undo.
+	var journaled journal.Entry
+	if len(applied) > 0 {
+		var jerr error
+		journaled, jerr = journal.Append(journal.Entry{
+			Flow:      "roles",
+			Operation: c.State.operation.Id,
+			Principal: c.State.GetRole().Name,
+			Inverse:   aws.Inverse(applied),
+			RoleArn:   account.RoleArn,
+		})
+		if jerr != nil {
+			err = errors.Join(err, fmt.Errorf("operation could not be recorded for undo: %w", jerr))
+		}
+	}
+
+	if err == nil && c.leased() {
+		if lerr := c.addLease(applied, account, journaled.Id); lerr != nil {
+			err = fmt.Errorf("access granted but the lease could not be recorded, revoke it manually: %w", lerr)
+		}
+	}
+
+	if aerr := audit.Record(c.auditEntry(account.Api, applied, err)); aerr != nil {
+		err = errors.Join(err, aerr)
+	}
+
+	return err
+}
+
+// leased reports whether the operation is a temporary grant that has to be revoked by a lease.
+// Custom policies expire through a condition in their document instead.
+func (c *Controller) leased() bool {
+	return c.State.expiresAt != nil && (c.State.operation.Id == AttachPolicySlug.String())
+}
+
+// addLease records the temporary grant so it can be revoked once it expires. The lease refers to the
+// journal entry of the grant, so undoing the grant also retires the lease.
+func (c *Controller) addLease(applied []aws.Call, account aws.Account, journalId string) error {
+	target := aws.RebaseArn(c.State.GetPolicy().Arn, account.Id)
+
+	_, err := lease.Add(lease.Lease{
+		Flow:      "roles",
+		Operation: c.State.operation.Id,
+		Principal: c.State.GetRole().Name,
+		Target:    target,
+		ExpiresAt: *c.State.expiresAt,
+		Revoke:    aws.Inverse(applied),
+		RoleArn:   account.RoleArn,
+		JournalId: journalId,
+	})
+	return err
+}
+
+// policyDocument returns the custom policy document. When the access is temporary,
+// every Allow statement is limited to the expiry time.
+func (c *Controller) policyDocument() string {
+	if c.State.policy == nil {
+		return ""
+	}
+
+	document := c.State.policy.Document
+	if c.State.expiresAt == nil || document == "" {
+		return document
+	}
+
+	var policy ai.IAMPolicy
+	if err := json.Unmarshal([]byte(document), &policy); err != nil {
+		return document
+	}
+	policy.ExpireAt(*c.State.expiresAt)
+
+	expiring, err := json.MarshalIndent(policy, "", "\t")
+	if err != nil {
+		return document
+	}
+	return string(expiring)
+}
+
+// auditEntry describes the operation for the audit log.
+func (c *Controller) auditEntry(api *aws.Api, applied []aws.Call, err error) audit.Entry {
+	entry := audit.Entry{
+		Caller:    "unknown",
+		Flow:      "roles",
+		Operation: c.State.operation.Id,
+		Principal: c.State.GetRole().Name,
+		Calls:     applied,
+		Outcome:   audit.OutcomeSuccess,
+	}
+
+	if arn, account, ierr := api.CallerIdentity(context.Background()); ierr == nil {
+		entry.Caller, entry.Account = arn, account
+	}
+	if c.State.trustPolicy != nil {
+		entry.PolicyDocument = c.State.trustPolicy.Document
+	}
+	if c.State.policy != nil {
+		if !c.State.policy.Inline {
+			entry.PolicyArn = aws.RebaseArn(c.State.policy.Arn, entry.Account)
+		}
+		entry.PolicyDocument = c.policyDocument()
+	}
+	for _, call := range applied {
+		switch call.Action {
+		case aws.CreatePolicyAction:
+			entry.PolicyArn = call.Param("PolicyArn")
+		case aws.DeleteRolePolicyAction:
+			// The document of a removed inline policy is only known once it has been read
+			entry.PolicyDocument = call.Param("PolicyDocument")
+		case aws.DeleteRoleBoundaryAction:
+			entry.PolicyArn = call.Param("PermissionsBoundary")
+		}
+	}
+	if err != nil {
+		entry.Outcome = audit.OutcomeFailure
+		entry.Error = err.Error()
+	}
+
+	return entry
+}
+
+// apply performs the operation in the account and returns the calls that were applied, including
+// the ones that succeeded before a failure.
+func (c *Controller) apply(account aws.Account) ([]aws.Call, error) {
+	roleName := c.State.GetRole().Name
+
 	switch c.State.operation.Id {
 	case AttachPolicySlug.String():
-		return c.api.AttachPolicyToRole(context.Background(), c.State.GetPolicy().Arn, c.State.GetRole().Name)
+		policyArn := aws.RebaseArn(c.State.GetPolicy().Arn, account.Id)
+
+		// Attaching an attached policy changes nothing, undoing it or its lease would revoke access targe did not grant
+		attached, err := account.Api.IsPolicyAttachedToRole(context.Background(), policyArn, roleName)
+		if err != nil {
+			return nil, err
+		}
+		if attached {
+			return nil, fmt.Errorf("policy %s is already attached to role %s", policyArn, roleName)
+		}
+
+		call := aws.NewCall(aws.AttachRolePolicyAction, "PolicyArn", policyArn, "RoleName", roleName)
+		return calls(call, account.Api.AttachPolicyToRole(context.Background(), policyArn, roleName))
 	case DetachPolicySlug.String():
-		return c.api.DetachPolicyFromRole(context.Background(), c.State.GetPolicy().Arn, c.State.GetRole().Name)
+		call := aws.NewCall(aws.DetachRolePolicyAction, "PolicyArn", aws.RebaseArn(c.State.GetPolicy().Arn, account.Id), "RoleName", roleName)
+		return calls(call, account.Api.DetachPolicyFromRole(context.Background(), aws.RebaseArn(c.State.GetPolicy().Arn, account.Id), roleName))
 	case AttachCustomPolicySlug.String():
-		output, err := c.api.CreatePolicy(context.Background(), c.State.GetPolicy().Name, c.State.GetPolicy().Document)
+		output, err := account.Api.CreatePolicy(context.Background(), c.State.GetPolicy().Name, c.policyDocument())
 		if err != nil {
-			return err
+			return nil, err
+		}
+
+		policyArn := *output.Policy.Arn
+		applied := []aws.Call{aws.NewCall(aws.CreatePolicyAction, "PolicyName", c.State.GetPolicy().Name, "PolicyArn", policyArn)}
+
+		attach := aws.NewCall(aws.AttachRolePolicyAction, "PolicyArn", policyArn, "RoleName", roleName)
+		if err := account.Api.AttachPolicyToRole(context.Background(), policyArn, roleName); err != nil {
+			return applied, fmt.Errorf("policy %s created but not attached: %w", policyArn, err)
+		}
+
+		return append(applied, attach), nil
+	case AttachInlinePolicySlug.String():
+		policyName, document := c.State.GetPolicy().Name, c.policyDocument()
+
+		// PutUserPolicy overwrites an inline policy of the same name, which undo could not restore
+		existing, err := account.Api.ListRoleInlinePolicies(context.Background(), roleName)
+		if err != nil {
+			return nil, err
+		}
+		if slices.Contains(existing, policyName) {
+			return nil, fmt.Errorf("role %s already has an inline policy named %s", roleName, policyName)
+		}
+
+		call := aws.NewCall(aws.PutRolePolicyAction, "PolicyName", policyName, "PolicyDocument", document, "RoleName", roleName)
+		return calls(call, account.Api.PutInlinePolicyToRole(context.Background(), policyName, document, roleName))
+	case RemoveInlinePolicySlug.String():
+		policyName := c.State.GetPolicy().Name
+
+		// The document is kept in the call so undo can put the policy back
+		document, err := account.Api.GetRoleInlinePolicy(context.Background(), policyName, roleName)
+		if err != nil {
+			return nil, err
+		}
+
+		call := aws.NewCall(aws.DeleteRolePolicyAction, "PolicyName", policyName, "PolicyDocument", document, "RoleName", roleName)
+		return calls(call, account.Api.DeleteInlinePolicyFromRole(context.Background(), policyName, roleName))
+	case EditTrustPolicySlug.String():
+		trust := c.State.GetTrustPolicy()
+
+		// The edited trust policy is the one of the current account
+		if len(c.Accounts) > 0 {
+			return nil, fmt.Errorf("%s is only applied in a single account, not with --accounts", EditTrustPolicySlug)
+		}
+
+		// The trust policy is replaced as a whole, so it must not have changed since it was edited
+		current, err := account.Api.GetAssumeRolePolicy(context.Background(), roleName)
+		if err != nil {
+			return nil, err
+		}
+		if indentDocument(current) != trust.Current {
+			return nil, fmt.Errorf("the trust policy of %s differs from the one that was edited, load it again", roleName)
+		}
+
+		call := aws.NewCall(aws.UpdateAssumeRoleAction, "RoleName", roleName, "PolicyDocument", trust.Document, "PreviousPolicyDocument", trust.Current)
+		return calls(call, account.Api.UpdateAssumeRolePolicy(context.Background(), roleName, trust.Document))
+	case SetBoundarySlug.String():
+		boundaryArn := aws.RebaseArn(c.State.GetPolicy().Arn, account.Id)
+
+		// The replaced boundary is kept in the call so undo can restore it
+		previous, err := account.Api.GetRolePermissionsBoundary(context.Background(), roleName)
+		if err != nil {
+			return nil, err
+		}
+
+		call := aws.NewCall(aws.PutRoleBoundaryAction, "RoleName", roleName, "PermissionsBoundary", boundaryArn, "PreviousPermissionsBoundary", previous)
+		return calls(call, account.Api.PutRolePermissionsBoundary(context.Background(), roleName, boundaryArn))
+	case RemoveBoundarySlug.String():
+		// The removed boundary is kept in the call so undo can put it back
+		current, err := account.Api.GetRolePermissionsBoundary(context.Background(), roleName)
+		if err != nil {
+			return nil, err
 		}
-		return c.api.AttachPolicyToRole(context.Background(), *output.Policy.Arn, c.State.GetRole().Name)
+		if current == "" {
+			return nil, fmt.Errorf("role %s has no permissions boundary", roleName)
+		}
+
+		call := aws.NewCall(aws.DeleteRoleBoundaryAction, "RoleName", roleName, "PermissionsBoundary", current)
+		return calls(call, account.Api.DeleteRolePermissionsBoundary(context.Background(), roleName))
+	case EditPolicySlug.String():
+		return account.Api.UpdatePolicy(context.Background(), aws.RebaseArn(c.State.GetPolicy().Arn, account.Id), c.State.GetPolicy().Document, c.State.GetPruneVersion())
 	default:
-		return errors.New("operation not supported")
+		return nil, errors.New("operation not supported")
 	}
+}
 
-	return nil
+// calls returns the call as applied when err is nil.
+func calls(call aws.Call, err error) ([]aws.Call, error) {
+	if err != nil {
+		return nil, err
+	}
+	return []aws.Call{call}, nil
+}
+
+// Plan returns the IAM API calls that Done would perform for the current state, one plan for each
+// of the accounts with the ARNs of that account.
+func (c *Controller) Plan() []aws.AccountPlan {
+	if len(c.Accounts) == 0 {
+		return []aws.AccountPlan{{Calls: c.plan("")}}
+	}
+
+	var plans []aws.AccountPlan
+	for _, account := range c.Accounts {
+		plans = append(plans, aws.AccountPlan{Account: account.Id, Calls: c.plan(account.Id)})
+	}
+	return plans
+}
+
+// plan returns the IAM API calls that Done would perform in the account with the given id.
+func (c *Controller) plan(accountId string) []aws.Call {
+	switch c.State.operation.Id {
+	case AttachPolicySlug.String():
+		return []aws.Call{aws.NewCall(aws.AttachRolePolicyAction, "PolicyArn", aws.RebaseArn(c.State.GetPolicy().Arn, accountId), "RoleName", c.State.GetRole().Name)}
+	case DetachPolicySlug.String():
+		return []aws.Call{aws.NewCall(aws.DetachRolePolicyAction, "PolicyArn", aws.RebaseArn(c.State.GetPolicy().Arn, accountId), "RoleName", c.State.GetRole().Name)}
+	case AttachCustomPolicySlug.String():
+		return []aws.Call{
+			aws.NewCall(aws.CreatePolicyAction, "PolicyName", c.State.GetPolicy().Name, "Description", aws.PolicyDescription, "PolicyDocument", c.policyDocument()),
+			aws.NewCall(aws.AttachRolePolicyAction, "PolicyArn", aws.CreatedPolicyArn, "RoleName", c.State.GetRole().Name),
+		}
+	case AttachInlinePolicySlug.String():
+		return []aws.Call{aws.NewCall(aws.PutRolePolicyAction, "PolicyName", c.State.GetPolicy().Name, "PolicyDocument", c.policyDocument(), "RoleName", c.State.GetRole().Name)}
+	case RemoveInlinePolicySlug.String():
+		return []aws.Call{aws.NewCall(aws.DeleteRolePolicyAction, "PolicyName", c.State.GetPolicy().Name, "RoleName", c.State.GetRole().Name)}
+	case EditTrustPolicySlug.String():
+		return []aws.Call{aws.NewCall(aws.UpdateAssumeRoleAction, "RoleName", c.State.GetRole().Name, "PolicyDocument", c.State.GetTrustPolicy().Document)}
+	case SetBoundarySlug.String():
+		return []aws.Call{aws.NewCall(aws.PutRoleBoundaryAction, "RoleName", c.State.GetRole().Name, "PermissionsBoundary", aws.RebaseArn(c.State.GetPolicy().Arn, accountId))}
+	case RemoveBoundarySlug.String():
+		return []aws.Call{aws.NewCall(aws.DeleteRoleBoundaryAction, "RoleName", c.State.GetRole().Name)}
+	case EditPolicySlug.String():
+		return c.editPlan(accountId)
+	default:
+		return nil
+	}
+}
+
+// editPlan returns the calls that make the edited document the default version of the policy,
+// pruning the oldest version first when the policy is at the version limit.
+func (c *Controller) editPlan(accountId string) []aws.Call {
+	var plan []aws.Call
+	policyArn := aws.RebaseArn(c.State.GetPolicy().Arn, accountId)
+
+	if versions := c.State.GetPolicyVersions(); versions != nil && versions.Count >= aws.MaxPolicyVersions && c.State.GetPruneVersion() {
+		plan = append(plan, aws.NewCall(aws.DeletePolicyVersionAction, "PolicyArn", policyArn, "VersionId", versions.Oldest))
+	}
+
+	return append(plan, aws.NewCall(aws.CreatePolicyVersionAction, "PolicyArn", policyArn, "PolicyDocument", c.State.GetPolicy().Document, "SetAsDefault", "true"))
+}
+
+// Missing lists the inputs that are still required before Done can apply the operation.
+func (c *Controller) Missing() []string {
+	var missing []string
+
+	if c.State.role == nil {
+		missing = append(missing, "role (--role)")
+	}
+
+	if c.State.operation == nil {
+		return append(missing, "operation (--operation)")
+	}
+
+	switch c.State.operation.Id {
+	case AttachCustomPolicySlug.String(), AttachInlinePolicySlug.String():
+		if c.State.policy == nil || c.State.policy.Document == "" {
+			missing = append(missing, "policy document (--policy-file)")
+		}
+	case EditTrustPolicySlug.String():
+		if c.State.trustPolicy == nil || c.State.trustPolicy.Document == "" {
+			missing = append(missing, "trust policy change (--trust-add, --trust-remove)")
+		}
+	case EditPolicySlug.String():
+		if c.State.policy == nil {
+			missing = append(missing, "policy (--policy)")
+		}
+		if c.State.policy == nil || c.State.policy.Document == "" {
+			missing = append(missing, "policy document (--policy-file)")
+		}
+	case RemoveBoundarySlug.String():
+		// The boundary to remove is read when the operation is applied
+	default:
+		if c.State.policy == nil {
+			missing = append(missing, "policy (--policy)")
+		}
+	}
+
+	return missing
+}
+
+// Overview returns the rows that describe the current state.
+func (c *Controller) Overview() [][]string {
+	var rows [][]string
+
+	if c.State.role != nil {
+		rows = append(rows, []string{"Role", c.State.role.Name, c.State.role.Arn})
+	}
+	if c.State.operation != nil {
+		rows = append(rows, []string{"Operation", c.State.operation.Name, c.State.operation.Desc})
+	}
+	if c.State.service != nil {
+		rows = append(rows, []string{"Service", c.State.service.Name, c.State.service.Desc})
+	}
+	for _, resource := range c.State.resources {
+		rows = append(rows, []string{"Resource", resource.Name, resource.Arn})
+	}
+	if c.State.expiresAt != nil && c.State.operation != nil && slices.Contains(TemporaryOperations, OperationType(c.State.operation.Id)) {
+		rows = append(rows, []string{"Expires", c.State.expiresAt.Local().Format("2006-01-02 15:04"), time.Until(*c.State.expiresAt).Round(time.Minute).String()})
+	}
+	if c.State.policy != nil {
+		switch {
+		case c.State.policy.Inline:
+			rows = append(rows, []string{"Policy", c.State.policy.Name, "inline"})
+		case len(c.State.policy.Document) > 0 && (c.State.operation == nil || c.State.operation.Id != EditPolicySlug.String()):
+			rows = append(rows, []string{"Policy", c.State.policy.Name, "new"})
+		default:
+			rows = append(rows, []string{"Policy", c.State.policy.Name, c.State.policy.Arn})
+		}
+	}
+
+	if boundary := c.State.boundary; boundary != nil {
+		if boundary.Arn == "" {
+			rows = append(rows, []string{"Boundary", "(none)", "no permissions boundary"})
+		} else {
+			rows = append(rows, []string{"Boundary", boundary.Arn[strings.LastIndex(boundary.Arn, "/")+1:], boundary.Arn})
+		}
+		if len(boundary.Outside) > 0 {
+			rows = append(rows, []string{"Warning", fmt.Sprintf("%d actions outside the boundary", len(boundary.Outside)), strings.Join(boundary.Outside, ", ")})
+		}
+	}
+
+	if versions := c.State.versions; versions != nil && c.State.operation != nil && c.State.operation.Id == EditPolicySlug.String() {
+		detail := "default " + versions.Default
+		if versions.Count >= aws.MaxPolicyVersions {
+			detail += ", at the limit"
+			if c.State.prune {
+				detail += ", prune " + versions.Oldest
+			}
+		}
+		rows = append(rows, []string{"Versions", fmt.Sprintf("%d of %d", versions.Count, aws.MaxPolicyVersions), detail})
+	}
+
+	if trust := c.State.trustPolicy; trust != nil && trust.Document != "" {
+		added, removed := 0, 0
+		for _, line := range diff.Lines(trust.Current, trust.Document) {
+			switch line.Kind {
+			case diff.Added:
+				added++
+			case diff.Removed:
+				removed++
+			}
+		}
+		rows = append(rows, []string{"Trust Policy", fmt.Sprintf("+%d -%d lines", added, removed), "replaced with UpdateAssumeRolePolicy"})
+	}
+
+	for _, finding := range c.Findings() {
+		rows = append(rows, []string{"Lint", finding.Severity, finding.Message + " (statement " + finding.Statement + ")"})
+	}
+
+	if len(c.Accounts) > 0 {
+		var ids []string
+		for _, account := range c.Accounts {
+			ids = append(ids, account.Id)
+		}
+		rows = append(rows, []string{"Accounts", fmt.Sprintf("%d accounts", len(c.Accounts)), strings.Join(ids, ", ")})
+	}
+	for _, result := range c.notCreated {
+		rows = append(rows, []string{"Warning", "role not created in " + result.Account.String(), result.Err.Error()})
+	}
+
+	return rows
+}
+
+// pageTitle returns a list title that shows the number of loaded items while more pages are loading.
+func pageTitle(title string, loaded int, loading bool) string {
+	if loading {
+		return fmt.Sprintf("%s (loading %d…)", title, loaded)
+	}
+	return title
 }
 
 // Switch handles window size changes and updates the model accordingly.
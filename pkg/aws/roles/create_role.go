@@ -0,0 +1,214 @@
+package roles
+
+import (
+	"errors"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Permify/targe/internal/aws"
+)
+
+type CreateRole struct {
+	controller *Controller
+	lg         *lipgloss.Renderer
+	styles     *Styles
+	form       *huh.Form
+	err        error
+	width      int
+	creating   bool
+	name       *string
+	path       *string
+	tags       *string
+	template   *string
+	create     *bool
+}
+
+func NewCreateRole(controller *Controller) CreateRole {
+	m := CreateRole{controller: controller, width: maxWidth}
+	m.lg = lipgloss.DefaultRenderer()
+	m.styles = NewStyles(m.lg)
+
+	name, path, tags := "", aws.DefaultPath, ""
+	m.name, m.path, m.tags = &name, &path, &tags
+	template := TrustTemplates[0].Id
+	m.template = &template
+
+	m.reinitializeForm()
+
+	return m
+}
+
+func (m CreateRole) Init() tea.Cmd {
+	return m.form.Init()
+}
+
+func (m CreateRole) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = min(msg.Width, maxWidth) - m.styles.Base.GetHorizontalFrameSize()
+
+	case RoleCreatedMsg:
+		m.controller.State.SetRole(&msg.Role)
+		return Switch(m.controller.Next(), 0, 0)
+
+	case FailedMsg:
+		m.creating = false
+		m.err = msg.Err
+		m.reinitializeForm()
+		return m, m.form.Init()
+
+	case tea.KeyMsg:
+		if msg.String() == "esc" || msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+	}
+
+	if m.creating {
+		return m, nil
+	}
+
+	var cmds []tea.Cmd
+
+	// Process the form
+	form, cmd := m.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.form = f
+		cmds = append(cmds, cmd)
+	}
+
+	// The form completes on "Create" or "Cancel"
+	if m.form.State == huh.StateCompleted {
+		if !*m.create {
+			return Switch(NewRoleList(m.controller), 0, 0)
+		}
+
+		name := strings.TrimSpace(*m.name)
+		tags, err := aws.ParseTags(*m.tags)
+		switch {
+		case name == "":
+			m.err = errors.New("Please enter a name for the role")
+		case err != nil:
+			m.err = err
+		default:
+			m.err = aws.ValidatePath(*m.path)
+		}
+		if m.err == nil {
+			m.creating = true
+			return m, m.controller.CreateRole(name, *m.path, *m.template, tags)
+		}
+
+		m.reinitializeForm()
+		cmds = append(cmds, m.form.Init())
+	}
+
+	return m, tea.Batch(cmds...)
+}
+
+func (m CreateRole) View() string {
+	s := m.styles
+
+	v := strings.TrimSuffix(m.form.View(), "\n\n")
+	form := m.lg.NewStyle().Margin(1, 0).Render(v)
+
+	// Status (right side)
+	var status string
+	{
+		const statusWidth = 60
+		statusMarginLeft := m.width - statusWidth - lipgloss.Width(form) - s.Status.GetMarginRight()
+		status = s.Status.
+			Height(lipgloss.Height(form)).
+			Width(statusWidth).
+			MarginLeft(statusMarginLeft).
+			Render(s.StatusHeader.Render("New Role") + "\n" +
+				m.statusView())
+	}
+
+	header := m.appBoundaryView("Create Role")
+	if m.err != nil {
+		header = m.appErrorBoundaryView(m.err.Error())
+	}
+	body := lipgloss.JoinHorizontal(lipgloss.Top, form, status)
+
+	footer := m.appBoundaryView(m.form.Help().ShortHelpView(m.form.KeyBinds()))
+
+	return s.Base.Render(header + "\n" + body + "\n\n" + footer)
+}
+
+// statusView describes where the role is created.
+func (m CreateRole) statusView() string {
+	if m.creating {
+		return "Creating the role…"
+	}
+	if len(m.controller.Accounts) > 0 {
+		return "The role is created in each of the selected accounts and tagged as created by targe."
+	}
+	return "The role is created in the current account and tagged as created by targe."
+}
+
+func (m CreateRole) appBoundaryView(text string) string {
+	return lipgloss.PlaceHorizontal(
+		m.width,
+		lipgloss.Left,
+		m.styles.HeaderText.Render(text),
+		lipgloss.WithWhitespaceChars("/"),
+		lipgloss.WithWhitespaceForeground(indigo),
+	)
+}
+
+func (m CreateRole) appErrorBoundaryView(text string) string {
+	return lipgloss.PlaceHorizontal(
+		m.width,
+		lipgloss.Left,
+		m.styles.ErrorHeaderText.Render(text),
+		lipgloss.WithWhitespaceChars("/"),
+		lipgloss.WithWhitespaceForeground(red),
+	)
+}
+
+func (m *CreateRole) reinitializeForm() {
+	var templates []huh.Option[string]
+	for _, template := range TrustTemplates {
+		templates = append(templates, huh.NewOption(template.Name, template.Id))
+	}
+
+	createInitialValue := true
+	m.create = &createInitialValue
+
+	// Preserve the entered values
+	m.form = huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Key("name").
+				Title("Name").
+				Value(m.name),
+			huh.NewInput().
+				Key("path").
+				Title("Path").
+				Value(m.path),
+			huh.NewInput().
+				Key("tags").
+				Title("Tags").
+				Description("Comma separated key=value pairs.").
+				Placeholder("team=platform").
+				Value(m.tags),
+			huh.NewSelect[string]().
+				Key("template").
+				Title("Trusted by").
+				Description("The trust policy can be changed later with edit_trust_policy.").
+				Options(templates...).
+				Value(m.template),
+			huh.NewConfirm().
+				Key("create").
+				Title("Create the role?").
+				Value(m.create).
+				Affirmative("Create").
+				Negative("Cancel"),
+		),
+	).
+		WithWidth(45).
+		WithShowHelp(false).
+		WithShowErrors(false)
+}
@@ -45,8 +45,7 @@ func (m PolicyList) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "ctrl+c":
 			return m, tea.Quit
 		case "enter":
-			if !m.loading {
-				policy := m.list.SelectedItem().(models.Policy)
+			if policy, ok := m.list.SelectedItem().(models.Policy); ok && !m.loading {
 				m.controller.State.SetPolicy(&policy)
 				return Switch(m.controller.Next(), m.list.Width(), m.list.Height())
 			}
@@ -55,8 +54,13 @@ func (m PolicyList) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		h, v := listStyle.GetFrameSize()
 		m.list.SetSize(msg.Width-h, msg.Height-v)
 	case PolicyLoadedMsg:
+		// Append the loaded page, more pages may follow
 		m.loading = false
-		m.list.SetItems(msg.List)
+		m.list.SetItems(append(m.list.Items(), msg.List...))
+		m.list.Title = pageTitle("Policies", len(m.list.Items()), msg.Next != nil)
+		if msg.Next != nil {
+			return m, msg.Next
+		}
 	case FailedMsg:
 		// Handle error
 		m.loading = false
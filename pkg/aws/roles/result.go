@@ -9,7 +9,16 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/lipgloss/table"
 
-	"github.com/Permify/targe/pkg/aws/models"
+	"github.com/Permify/targe/internal/aws"
+	"github.com/Permify/targe/internal/diff"
+	"github.com/Permify/targe/internal/lint"
+)
+
+// Choices offered on the result screen.
+const (
+	applyChoice   = "apply"
+	previewChoice = "preview"
+	cancelChoice  = "cancel"
 )
 
 type Result struct {
@@ -18,8 +27,13 @@ type Result struct {
 	styles     *Styles
 	form       *huh.Form
 	width      int
-	value      *bool
-	error      error
+	value      *string
+	prune      *bool
+	applied    bool
+	// error is the error of applying the operation
+	error error
+	// loadErrs are the failures to load the boundary or the simulation, shown as warnings
+	loadErrs []error
 }
 
 func NewResult(controller *Controller) Result {
@@ -34,17 +48,39 @@ func NewResult(controller *Controller) Result {
 	result.styles = NewStyles(result.lg)
 
 	// Initialize value pointer
-	initialValue := false
+	initialValue := applyChoice
+	if controller.DryRun {
+		initialValue = previewChoice
+	}
 	result.value = &initialValue
 
+	// Ask before pruning a version when the edited policy is at the version limit
+	if versions := controller.State.GetPolicyVersions(); versions != nil && versions.Count >= aws.MaxPolicyVersions &&
+		controller.State.GetOperation().Id == EditPolicySlug.String() {
+		prune := controller.State.GetPruneVersion()
+		result.prune = &prune
+	}
+
 	// Configure the form
-	result.form = createForm(result.value)
+	result.form = createForm(result.value, result.prune, controller.DryRun)
 
 	return result
 }
 
 func (m Result) Init() tea.Cmd {
-	return m.form.Init()
+	cmds := []tea.Cmd{m.form.Init()}
+
+	// The overview shows the permissions boundary in effect, which is loaded once
+	if m.controller.State.GetBoundary() == nil {
+		cmds = append(cmds, m.controller.LoadBoundary())
+	}
+
+	// Granted access is checked with the policy simulator before it is applied
+	if m.controller.State.GetSimulation() == nil {
+		cmds = append(cmds, m.controller.LoadSimulation())
+	}
+
+	return tea.Batch(cmds...)
 }
 
 func min(x, y int) int {
@@ -58,6 +94,12 @@ func (m Result) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.width = min(msg.Width, 80) - m.styles.Base.GetHorizontalFrameSize()
+	case BoundaryLoadedMsg, SimulationLoadedMsg:
+		return m, nil
+	case FailedMsg:
+		// The operation can still be applied without the boundary or the simulation
+		m.loadErrs = append(m.loadErrs, msg.Err)
+		return m, nil
 	case tea.KeyMsg:
 		if msg.String() == "esc" || msg.String() == "ctrl+c" || msg.String() == "q" {
 			return m, tea.Quit
@@ -78,15 +120,22 @@ func (m Result) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		cmds = append(cmds, cmd)
 	}
 
-	// Handle form completion
-	if m.form.State == huh.StateCompleted {
-		if *m.value {
+	// Handle form completion once
+	if m.form.State == huh.StateCompleted && !m.applied {
+		m.applied = true
+		if m.prune != nil {
+			m.controller.State.SetPruneVersion(*m.prune)
+		}
+		switch *m.value {
+		case applyChoice:
 			if err := m.controller.Done(); err != nil {
 				// Handle error without quitting
 				m.error = err
 				return m, nil // Return updated model without quitting
 			}
-		} else {
+		case previewChoice:
+			// The plan is rendered by View, nothing is applied
+		default:
 			cmds = append(cmds, tea.Quit)
 		}
 	}
@@ -95,6 +144,14 @@ func (m Result) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m Result) View() string {
+	if m.form.State == huh.StateCompleted && *m.value == previewChoice {
+		return m.planView()
+	}
+
+	if m.form.State == huh.StateCompleted && *m.value == applyChoice && len(m.controller.Results()) > 0 {
+		return m.accountsView()
+	}
+
 	if m.form.State == huh.StateCompleted && m.error == nil {
 		// Success Message with Exit Footer
 		successMessage := fmt.Sprintf(
@@ -118,13 +175,19 @@ func (m Result) View() string {
 	}
 
 	// When not in completed state, display other UI elements
-	rows := m.collectOverviewRows()
+	rows := m.controller.Overview()
 	t := m.createTable(rows)
 	formView := m.lg.NewStyle().Margin(1, 0).Render(strings.TrimSuffix(m.form.View(), "\n\n"))
 	header := m.renderHeader()
 	footer := m.renderFooter()
 
-	body := lipgloss.JoinVertical(lipgloss.Top, t.Render(), formView)
+	body := lipgloss.JoinVertical(lipgloss.Top, t.Render(), m.simulationView(), m.warningView(), formView)
+
+	// Show the trust policy before and after the change
+	if trust := m.controller.State.GetTrustPolicy(); trust != nil && trust.Document != "" {
+		changes := m.lg.NewStyle().Margin(0, 0, 1, 1).Render(renderDiff(diff.Lines(trust.Current, trust.Document)))
+		body = lipgloss.JoinVertical(lipgloss.Top, t.Render(), changes, formView)
+	}
 
 	// Add error message if present
 	if m.error != nil {
@@ -145,34 +208,97 @@ func (m Result) View() string {
 	return m.styles.Base.Render(header + "\n" + body + "\n\n" + footer)
 }
 
-func (m Result) collectOverviewRows() [][]string {
-	var rows [][]string
-	state := m.controller.State
-
-	if state.role != nil {
-		rows = append(rows, []string{"Role", state.role.Name, state.role.Arn})
-	}
-	if state.operation != nil {
-		rows = append(rows, []string{"Operation", state.operation.Name, state.operation.Desc})
+// warningView renders the failures to load the boundary or the simulation.
+func (m Result) warningView() string {
+	if len(m.loadErrs) == 0 {
+		return ""
 	}
-	if state.service != nil {
-		rows = append(rows, []string{"Service", state.service.Name, state.service.Desc})
+
+	var lines []string
+	for _, err := range m.loadErrs {
+		lines = append(lines, lipgloss.NewStyle().Foreground(lipgloss.Color("11")).Render("⚠ "+err.Error()))
 	}
-	if state.resource != nil {
-		rows = append(rows, []string{"Resource", state.resource.Name, state.resource.Arn})
+	return m.lg.NewStyle().MarginTop(1).Render(strings.Join(lines, "\n"))
+}
+
+// simulationView renders the decision of the policy simulator for each action of the policy.
+func (m Result) simulationView() string {
+	simulation := m.controller.State.GetSimulation()
+	if len(simulation) == 0 {
+		return ""
 	}
-	if state.policy != nil {
-		rows = append(rows, m.formatPolicyRow(state.policy))
+
+	var lines []string
+	for _, result := range simulation {
+		line := result.Action
+		if result.Resource != "" && result.Resource != "*" {
+			line += " on " + result.Resource
+		}
+
+		switch result.Decision {
+		case aws.DecisionAllowed:
+			line = lipgloss.NewStyle().Foreground(lipgloss.Color("10")).Render("✔ " + line + " allowed")
+		case aws.DecisionExplicitDeny:
+			line = lipgloss.NewStyle().Foreground(lipgloss.Color("9")).Render("✖ " + line + " explicitly denied")
+		default:
+			line = lipgloss.NewStyle().Foreground(lipgloss.Color("11")).Render("✖ " + line + " implicitly denied")
+		}
+		if result.DeniedBy != "" {
+			line += " by the " + result.DeniedBy
+		}
+		lines = append(lines, line)
 	}
 
-	return rows
+	return m.lg.NewStyle().MarginTop(1).Render(m.styles.StatusHeader.Render("Simulation") + "\n" + strings.Join(lines, "\n"))
 }
 
-func (m Result) formatPolicyRow(policy *models.Policy) []string {
-	if len(policy.Document) > 0 {
-		return []string{"Policy", policy.Name, "new"}
+// planView renders the IAM calls that would be performed without applying them.
+func (m Result) planView() string {
+	plan := m.lg.NewStyle().Margin(1, 0, 0, 2).Render(aws.FormatAccountPlans(m.controller.Plan()))
+
+	exitFooter := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("8")).
+		Italic(true).
+		Render("Nothing has been applied. Press Enter to exit.")
+
+	return m.styles.Base.Render(m.appBoundaryView("Plan") + "\n" + m.createTable(m.controller.Overview()).Render() + "\n" + plan + "\n\n" + exitFooter)
+}
+
+// accountsView renders the outcome of the operation in each account.
+func (m Result) accountsView() string {
+	var rows [][]string
+	failed := 0
+	for _, result := range m.controller.Results() {
+		if result.Err != nil {
+			failed++
+			rows = append(rows, []string{result.Account.String(), "✖ " + result.Err.Error()})
+		} else {
+			rows = append(rows, []string{result.Account.String(), "✔ success"})
+		}
 	}
-	return []string{"Policy", policy.Name, policy.Arn}
+
+	t := table.New().
+		Border(lipgloss.HiddenBorder()).
+		StyleFunc(func(row, col int) lipgloss.Style {
+			if col == 0 {
+				return m.styles.Base.Foreground(lipgloss.Color("205")).Bold(true)
+			}
+			if row >= 0 && row < len(m.controller.Results()) && m.controller.Results()[row].Err != nil {
+				return m.styles.Base.Foreground(lipgloss.Color("9"))
+			}
+			return m.styles.Base.Foreground(lipgloss.Color("10"))
+		}).
+		Width(m.width).
+		Rows(rows...)
+
+	summary := fmt.Sprintf("%d of %d accounts succeeded.", len(rows)-failed, len(rows))
+
+	exitFooter := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("8")).
+		Italic(true).
+		Render("Press Enter to exit.")
+
+	return m.styles.Base.Render(m.appBoundaryView("Accounts") + "\n" + t.Render() + "\n" + summary + "\n\n" + exitFooter)
 }
 
 func (m Result) createTable(rows [][]string) *table.Table {
@@ -183,6 +309,16 @@ func (m Result) createTable(rows [][]string) *table.Table {
 			if col == 0 {
 				return m.styles.Base.Foreground(lipgloss.Color("205")).Bold(true)
 			}
+			// Findings of the linter are colored by their severity
+			if row >= 0 && row < len(rows) && rows[row][0] == "Lint" {
+				if rows[row][1] == lint.SeverityError {
+					return m.styles.Base.Foreground(lipgloss.Color("9"))
+				}
+				return m.styles.Base.Foreground(lipgloss.Color("11"))
+			}
+			if row >= 0 && row < len(rows) && rows[row][0] == "Warning" {
+				return m.styles.Base.Foreground(lipgloss.Color("11"))
+			}
 			return m.styles.Base
 		}).
 		Rows(rows...)
@@ -232,19 +368,34 @@ func (m Result) appErrorBoundaryView(text string) string {
 	)
 }
 
-func createForm(value *bool) *huh.Form {
-	confirm := huh.NewConfirm().
+func createForm(value *string, prune *bool, dryRun bool) *huh.Form {
+	options := []huh.Option[string]{
+		huh.NewOption("Yes", applyChoice),
+		huh.NewOption("Preview only", previewChoice),
+		huh.NewOption("No", cancelChoice),
+	}
+	if dryRun {
+		options = options[1:]
+	}
+
+	confirm := huh.NewSelect[string]().
 		Key("done").
 		Title("All done?").
-		Validate(func(v bool) error {
-			return nil
-		}).
-		Affirmative("Yes").
-		Negative("No").
+		Options(options...).
 		Value(value)
 
+	fields := []huh.Field{confirm}
+	if prune != nil {
+		fields = append([]huh.Field{huh.NewConfirm().
+			Key("prune").
+			Title(fmt.Sprintf("The policy has %d versions. Delete the oldest one?", aws.MaxPolicyVersions)).
+			Value(prune).
+			Affirmative("Delete").
+			Negative("Keep")}, fields...)
+	}
+
 	return huh.NewForm(
-		huh.NewGroup(confirm),
+		huh.NewGroup(fields...),
 	).
 		WithWidth(45).
 		WithShowHelp(false).
@@ -1,17 +1,29 @@
 package roles
 
 import (
+	"time"
+
+	"github.com/Permify/targe/internal/aws"
 	"github.com/Permify/targe/pkg/aws/models"
 )
 
 // State represents the roles flow state.
 type State struct {
-	role         *models.Role
-	operation    *models.Operation
-	policyOption *models.PolicyOption
-	service      *models.Service
-	resource     *models.Resource
-	policy       *models.Policy
+	role          *models.Role
+	operation     *models.Operation
+	policyOption  *models.PolicyOption
+	service       *models.Service
+	resources     []models.Resource
+	actions       []string
+	prompt        string
+	resourceQuery *models.ResourceQuery
+	policy        *models.Policy
+	versions      *models.PolicyVersions
+	prune         bool
+	simulation    []aws.Simulation
+	boundary      *models.Boundary
+	trustPolicy   *models.TrustPolicy
+	expiresAt     *time.Time
 }
 
 // Getters
@@ -36,9 +48,24 @@ func (s *State) GetService() *models.Service {
 	return s.service
 }
 
-// GetResource retrieves the resource from the state.
-func (s *State) GetResource() *models.Resource {
-	return s.resource
+// GetResourceQuery retrieves the query that narrows down the resource list.
+func (s *State) GetResourceQuery() *models.ResourceQuery {
+	return s.resourceQuery
+}
+
+// GetResources retrieves the resources the custom policy is scoped to.
+func (s *State) GetResources() []models.Resource {
+	return s.resources
+}
+
+// GetActions retrieves the actions picked in the policy builder.
+func (s *State) GetActions() []string {
+	return s.actions
+}
+
+// GetPrompt retrieves the description the custom policy is generated from.
+func (s *State) GetPrompt() string {
+	return s.prompt
 }
 
 // GetPolicy retrieves the policy from the state.
@@ -46,6 +73,36 @@ func (s *State) GetPolicy() *models.Policy {
 	return s.policy
 }
 
+// GetPolicyVersions retrieves the versions of the policy that is being edited.
+func (s *State) GetPolicyVersions() *models.PolicyVersions {
+	return s.versions
+}
+
+// GetPruneVersion reports whether the oldest policy version may be deleted to make room for a new one.
+func (s *State) GetPruneVersion() bool {
+	return s.prune
+}
+
+// GetTrustPolicy retrieves the trust policy of the role that is being changed.
+func (s *State) GetTrustPolicy() *models.TrustPolicy {
+	return s.trustPolicy
+}
+
+// GetBoundary retrieves the permissions boundary in effect, nil until it is loaded.
+func (s *State) GetBoundary() *models.Boundary {
+	return s.boundary
+}
+
+// GetSimulation retrieves the policy simulator decisions, nil until the simulation has run.
+func (s *State) GetSimulation() []aws.Simulation {
+	return s.simulation
+}
+
+// GetExpiresAt retrieves the time at which a granted access expires.
+func (s *State) GetExpiresAt() *time.Time {
+	return s.expiresAt
+}
+
 // Setters
 
 // SetRole updates the role in the state.
@@ -68,12 +125,57 @@ func (s *State) SetService(service *models.Service) {
 	s.service = service
 }
 
-// SetResource updates the resource in the state.
-func (s *State) SetResource(resource *models.Resource) {
-	s.resource = resource
+// SetResourceQuery updates the query that narrows down the resource list.
+func (s *State) SetResourceQuery(query *models.ResourceQuery) {
+	s.resourceQuery = query
+}
+
+// SetResources updates the resources the custom policy is scoped to.
+func (s *State) SetResources(resources []models.Resource) {
+	s.resources = resources
+}
+
+// SetActions updates the actions picked in the policy builder.
+func (s *State) SetActions(actions []string) {
+	s.actions = actions
+}
+
+// SetPrompt updates the description the custom policy is generated from.
+func (s *State) SetPrompt(prompt string) {
+	s.prompt = prompt
 }
 
 // SetPolicy updates the policy in the state.
 func (s *State) SetPolicy(policy *models.Policy) {
 	s.policy = policy
 }
+
+// SetPolicyVersions updates the versions of the policy that is being edited.
+func (s *State) SetPolicyVersions(versions *models.PolicyVersions) {
+	s.versions = versions
+}
+
+// SetPruneVersion updates whether the oldest policy version may be deleted to make room for a new one.
+func (s *State) SetPruneVersion(prune bool) {
+	s.prune = prune
+}
+
+// SetTrustPolicy updates the trust policy of the role that is being changed.
+func (s *State) SetTrustPolicy(trustPolicy *models.TrustPolicy) {
+	s.trustPolicy = trustPolicy
+}
+
+// SetBoundary updates the permissions boundary in effect.
+func (s *State) SetBoundary(boundary *models.Boundary) {
+	s.boundary = boundary
+}
+
+// SetSimulation updates the policy simulator decisions.
+func (s *State) SetSimulation(simulation []aws.Simulation) {
+	s.simulation = simulation
+}
+
+// SetExpiresAt updates the time at which a granted access expires.
+func (s *State) SetExpiresAt(expiresAt *time.Time) {
+	s.expiresAt = expiresAt
+}
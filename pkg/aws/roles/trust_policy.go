@@ -0,0 +1,274 @@
+package roles
+
+import (
+	"errors"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Permify/targe/internal/ai"
+	"github.com/Permify/targe/internal/diff"
+)
+
+// Changes offered by the trust policy editor.
+const (
+	addPrincipalChange    = "add"
+	removePrincipalChange = "remove"
+	generateChange        = "generate"
+)
+
+type TrustPolicy struct {
+	controller    *Controller
+	lg            *lipgloss.Renderer
+	styles        *Styles
+	form          *huh.Form
+	err           error
+	width         int
+	loading       bool
+	change        *string
+	principalType *string
+	value         *string
+	done          *bool
+}
+
+func NewTrustPolicy(controller *Controller) TrustPolicy {
+	m := TrustPolicy{controller: controller, width: maxWidth}
+	m.lg = lipgloss.DefaultRenderer()
+	m.styles = NewStyles(m.lg)
+
+	m.loading = controller.State.GetTrustPolicy() == nil
+
+	change := addPrincipalChange
+	m.change = &change
+	principalType := ai.PrincipalAWS
+	m.principalType = &principalType
+
+	m.reinitializeForm()
+
+	return m
+}
+
+func (m TrustPolicy) Init() tea.Cmd {
+	if m.loading {
+		return tea.Batch(m.form.Init(), m.controller.LoadTrustPolicy())
+	}
+	return m.form.Init()
+}
+
+func (m TrustPolicy) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = min(msg.Width, maxWidth) - m.styles.Base.GetHorizontalFrameSize()
+
+	case TrustPolicyLoadedMsg:
+		m.loading = false
+		return m, nil
+
+	case FailedMsg:
+		m.loading = false
+		m.err = msg.Err
+		return m, nil
+
+	case tea.KeyMsg:
+		if msg.String() == "esc" || msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+	}
+
+	var cmds []tea.Cmd
+
+	// Process the form
+	form, cmd := m.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.form = f
+		cmds = append(cmds, cmd)
+	}
+
+	// The form completes on "Yes" or "Refresh"
+	if m.form.State == huh.StateCompleted && !m.loading && m.controller.State.GetTrustPolicy() != nil {
+		if *m.done {
+			if m.controller.State.GetTrustPolicy().Document == "" {
+				m.err = errors.New("Make a change and refresh before continuing")
+			} else {
+				return Switch(m.controller.Next(), 0, 0)
+			}
+		} else {
+			m.err = m.applyChange()
+		}
+
+		m.reinitializeForm()
+		cmds = append(cmds, m.form.Init())
+	}
+
+	return m, tea.Batch(cmds...)
+}
+
+// applyChange applies the change selected in the form to the trust policy in the state.
+func (m *TrustPolicy) applyChange() error {
+	value := strings.TrimSpace(*m.value)
+	if value == "" {
+		return errors.New("Please enter a principal or describe the change")
+	}
+
+	var err error
+	switch *m.change {
+	case generateChange:
+		err = m.controller.GenerateTrustPolicy(value)
+	default:
+		err = m.controller.ChangeTrustPrincipal(*m.change == addPrincipalChange, *m.principalType, value)
+	}
+	if err != nil {
+		return err
+	}
+
+	*m.value = ""
+	return nil
+}
+
+func (m TrustPolicy) View() string {
+	s := m.styles
+
+	v := strings.TrimSuffix(m.form.View(), "\n\n")
+	form := m.lg.NewStyle().Margin(1, 0).Render(v)
+
+	var title string
+	if m.controller.State.GetRole() != nil {
+		title = lipgloss.NewStyle().
+			MarginTop(1).
+			Render(s.StateHeader.Render("Role Name: " + m.controller.State.GetRole().Name))
+	}
+
+	// Status (right side)
+	var status string
+	{
+		const statusWidth = 60
+		statusMarginLeft := m.width - statusWidth - lipgloss.Width(form) - s.Status.GetMarginRight()
+		status = s.Status.
+			Height(lipgloss.Height(form)).
+			Width(statusWidth).
+			MarginLeft(statusMarginLeft).
+			Render(s.StatusHeader.Render("Trust Policy") + "\n" +
+				m.trustView())
+	}
+
+	header := lipgloss.JoinVertical(lipgloss.Top,
+		m.appBoundaryView("Trust Policy Editor"),
+		title,
+	)
+	if m.err != nil {
+		header = m.appErrorBoundaryView(m.err.Error())
+	}
+	body := lipgloss.JoinHorizontal(lipgloss.Top, form, status)
+
+	footer := m.appBoundaryView(m.form.Help().ShortHelpView(m.form.KeyBinds()))
+
+	return s.Base.Render(header + "\n" + body + "\n\n" + footer)
+}
+
+// trustView renders the current trust policy, or its diff to the changed one.
+func (m TrustPolicy) trustView() string {
+	if m.loading {
+		return "Loading the trust policy…"
+	}
+
+	trust := m.controller.State.GetTrustPolicy()
+	if trust == nil {
+		return "(None)"
+	}
+	if trust.Document == "" {
+		return trust.Current
+	}
+	return renderDiff(diff.Lines(trust.Current, trust.Document))
+}
+
+// renderDiff colors added lines green and removed lines red.
+func renderDiff(lines []diff.Line) string {
+	if !diff.Changed(lines) {
+		return "(No changes)"
+	}
+
+	var rendered []string
+	for _, line := range lines {
+		switch line.Kind {
+		case diff.Added:
+			rendered = append(rendered, lipgloss.NewStyle().Foreground(lipgloss.Color("10")).Render(line.String()))
+		case diff.Removed:
+			rendered = append(rendered, lipgloss.NewStyle().Foreground(lipgloss.Color("9")).Render(line.String()))
+		default:
+			rendered = append(rendered, line.String())
+		}
+	}
+	return strings.Join(rendered, "\n")
+}
+
+func (m TrustPolicy) appBoundaryView(text string) string {
+	return lipgloss.PlaceHorizontal(
+		m.width,
+		lipgloss.Left,
+		m.styles.HeaderText.Render(text),
+		lipgloss.WithWhitespaceChars("/"),
+		lipgloss.WithWhitespaceForeground(indigo),
+	)
+}
+
+func (m TrustPolicy) appErrorBoundaryView(text string) string {
+	return lipgloss.PlaceHorizontal(
+		m.width,
+		lipgloss.Left,
+		m.styles.ErrorHeaderText.Render(text),
+		lipgloss.WithWhitespaceChars("/"),
+		lipgloss.WithWhitespaceForeground(red),
+	)
+}
+
+func (m *TrustPolicy) reinitializeForm() {
+	doneInitialValue := false
+	m.done = &doneInitialValue
+
+	// Preserve the current value
+	if m.value == nil {
+		valueInitialValue := ""
+		m.value = &valueInitialValue
+	}
+
+	var principalTypes []huh.Option[string]
+	for _, t := range ai.PrincipalTypes {
+		principalTypes = append(principalTypes, huh.NewOption(t, t))
+	}
+
+	m.form = huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Key("change").
+				Title("Change").
+				Options(
+					huh.NewOption("Add principal", addPrincipalChange),
+					huh.NewOption("Remove principal", removePrincipalChange),
+					huh.NewOption("Describe with AI", generateChange),
+				).
+				Value(m.change),
+			huh.NewSelect[string]().
+				Key("type").
+				Title("Principal type").
+				Description("Ignored when describing with AI.").
+				Options(principalTypes...).
+				Value(m.principalType),
+			huh.NewInput().
+				Key("value").
+				Title("Principal or description").
+				Placeholder("ec2.amazonaws.com").
+				Value(m.value),
+			huh.NewConfirm().
+				Key("done").
+				Title("All done?").
+				Value(m.done).
+				Affirmative("Yes").
+				Negative("Refresh"),
+		),
+	).
+		WithWidth(45).
+		WithShowHelp(false).
+		WithShowErrors(false)
+}
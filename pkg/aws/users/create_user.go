@@ -0,0 +1,200 @@
+package users
+
+import (
+	"errors"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Permify/targe/internal/aws"
+)
+
+type CreateUser struct {
+	controller *Controller
+	lg         *lipgloss.Renderer
+	styles     *Styles
+	form       *huh.Form
+	err        error
+	width      int
+	creating   bool
+	name       *string
+	path       *string
+	tags       *string
+	create     *bool
+}
+
+func NewCreateUser(controller *Controller) CreateUser {
+	m := CreateUser{controller: controller, width: maxWidth}
+	m.lg = lipgloss.DefaultRenderer()
+	m.styles = NewStyles(m.lg)
+
+	name, path, tags := "", aws.DefaultPath, ""
+	m.name, m.path, m.tags = &name, &path, &tags
+
+	m.reinitializeForm()
+
+	return m
+}
+
+func (m CreateUser) Init() tea.Cmd {
+	return m.form.Init()
+}
+
+func (m CreateUser) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = min(msg.Width, maxWidth) - m.styles.Base.GetHorizontalFrameSize()
+
+	case UserCreatedMsg:
+		m.controller.State.SetUser(&msg.User)
+		return Switch(m.controller.Next(), 0, 0)
+
+	case FailedMsg:
+		m.creating = false
+		m.err = msg.Err
+		m.reinitializeForm()
+		return m, m.form.Init()
+
+	case tea.KeyMsg:
+		if msg.String() == "esc" || msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+	}
+
+	if m.creating {
+		return m, nil
+	}
+
+	var cmds []tea.Cmd
+
+	// Process the form
+	form, cmd := m.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.form = f
+		cmds = append(cmds, cmd)
+	}
+
+	// The form completes on "Create" or "Cancel"
+	if m.form.State == huh.StateCompleted {
+		if !*m.create {
+			return Switch(NewUserList(m.controller), 0, 0)
+		}
+
+		name := strings.TrimSpace(*m.name)
+		tags, err := aws.ParseTags(*m.tags)
+		switch {
+		case name == "":
+			m.err = errors.New("Please enter a name for the user")
+		case err != nil:
+			m.err = err
+		default:
+			m.err = aws.ValidatePath(*m.path)
+		}
+		if m.err == nil {
+			m.creating = true
+			return m, m.controller.CreateUser(name, *m.path, tags)
+		}
+
+		m.reinitializeForm()
+		cmds = append(cmds, m.form.Init())
+	}
+
+	return m, tea.Batch(cmds...)
+}
+
+func (m CreateUser) View() string {
+	s := m.styles
+
+	v := strings.TrimSuffix(m.form.View(), "\n\n")
+	form := m.lg.NewStyle().Margin(1, 0).Render(v)
+
+	// Status (right side)
+	var status string
+	{
+		const statusWidth = 60
+		statusMarginLeft := m.width - statusWidth - lipgloss.Width(form) - s.Status.GetMarginRight()
+		status = s.Status.
+			Height(lipgloss.Height(form)).
+			Width(statusWidth).
+			MarginLeft(statusMarginLeft).
+			Render(s.StatusHeader.Render("New User") + "\n" +
+				m.statusView())
+	}
+
+	header := m.appBoundaryView("Create User")
+	if m.err != nil {
+		header = m.appErrorBoundaryView(m.err.Error())
+	}
+	body := lipgloss.JoinHorizontal(lipgloss.Top, form, status)
+
+	footer := m.appBoundaryView(m.form.Help().ShortHelpView(m.form.KeyBinds()))
+
+	return s.Base.Render(header + "\n" + body + "\n\n" + footer)
+}
+
+// statusView describes where the user is created.
+func (m CreateUser) statusView() string {
+	if m.creating {
+		return "Creating the user…"
+	}
+	if len(m.controller.Accounts) > 0 {
+		return "The user is created in each of the selected accounts and tagged as created by targe."
+	}
+	return "The user is created in the current account and tagged as created by targe."
+}
+
+func (m CreateUser) appBoundaryView(text string) string {
+	return lipgloss.PlaceHorizontal(
+		m.width,
+		lipgloss.Left,
+		m.styles.HeaderText.Render(text),
+		lipgloss.WithWhitespaceChars("/"),
+		lipgloss.WithWhitespaceForeground(indigo),
+	)
+}
+
+func (m CreateUser) appErrorBoundaryView(text string) string {
+	return lipgloss.PlaceHorizontal(
+		m.width,
+		lipgloss.Left,
+		m.styles.ErrorHeaderText.Render(text),
+		lipgloss.WithWhitespaceChars("/"),
+		lipgloss.WithWhitespaceForeground(red),
+	)
+}
+
+func (m *CreateUser) reinitializeForm() {
+	createInitialValue := true
+	m.create = &createInitialValue
+
+	// Preserve the entered values
+	m.form = huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Key("name").
+				Title("Name").
+				Value(m.name),
+			huh.NewInput().
+				Key("path").
+				Title("Path").
+				Value(m.path),
+			huh.NewInput().
+				Key("tags").
+				Title("Tags").
+				Description("Comma separated key=value pairs.").
+				Placeholder("team=platform").
+				Value(m.tags),
+			huh.NewConfirm().
+				Key("create").
+				Title("Create the user?").
+				Value(m.create).
+				Affirmative("Create").
+				Negative("Cancel"),
+		),
+	).
+		WithWidth(45).
+		WithShowHelp(false).
+		WithShowErrors(false)
+}
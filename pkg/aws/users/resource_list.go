@@ -1,6 +1,7 @@
 package users
 
 import (
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
@@ -29,6 +30,9 @@ func NewResourceList(controller *Controller) ResourceList {
 
 	view.list = list.New([]list.Item{}, list.NewDefaultDelegate(), 0, 0)
 	view.list.Title = "Resources"
+	view.list.AdditionalShortHelpKeys = func() []key.Binding {
+		return []key.Binding{key.NewBinding(key.WithKeys(" "), key.WithHelp("space", "select several"))}
+	}
 	return view
 }
 
@@ -44,10 +48,15 @@ func (m ResourceList) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		switch msg.String() {
 		case "ctrl+c":
 			return m, tea.Quit
+		case " ":
+			// Space selects the resource as one of several, unless it is typed into the filter
+			if resource, ok := m.list.SelectedItem().(models.Resource); ok && !m.loading && m.list.FilterState() != list.Filtering {
+				resource.Selected = !resource.Selected
+				return m, m.list.SetItem(m.itemIndex(resource.Arn), resource)
+			}
 		case "enter":
-			if !m.loading {
-				resource := m.list.SelectedItem().(models.Resource)
-				m.controller.State.SetResource(&resource)
+			if resource, ok := m.list.SelectedItem().(models.Resource); ok && !m.loading {
+				m.controller.State.SetResources(m.selected(resource))
 				return Switch(m.controller.Next(), m.list.Width(), m.list.Height())
 			}
 		}
@@ -55,9 +64,13 @@ func (m ResourceList) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		h, v := listStyle.GetFrameSize()
 		m.list.SetSize(msg.Width-h, msg.Height-v)
 	case ResourceLoadedMsg:
-		// Update list with loaded users
+		// Append the loaded page, more pages may follow
 		m.loading = false
-		m.list.SetItems(msg.List)
+		m.list.SetItems(append(m.list.Items(), msg.List...))
+		m.list.Title = pageTitle("Resources", len(m.list.Items()), msg.Next != nil)
+		if msg.Next != nil {
+			return m, msg.Next
+		}
 	case FailedMsg:
 		// Handle error
 		m.loading = false
@@ -75,6 +88,39 @@ func (m ResourceList) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// itemIndex returns the index of the resource with the ARN among all items, the list index only
+// counts the items that match the filter.
+func (m ResourceList) itemIndex(arn string) int {
+	for i, item := range m.list.Items() {
+		if resource, ok := item.(models.Resource); ok && resource.Arn == arn {
+			return i
+		}
+	}
+	return -1
+}
+
+// selected returns the resources selected with space, or the current resource when none is.
+// All resources include every other, so selecting them drops the rest.
+func (m ResourceList) selected(current models.Resource) []models.Resource {
+	var resources []models.Resource
+	for _, item := range m.list.Items() {
+		resource, ok := item.(models.Resource)
+		if !ok || !resource.Selected {
+			continue
+		}
+		resource.Selected = false
+		if resource.Arn == "*" {
+			return []models.Resource{resource}
+		}
+		resources = append(resources, resource)
+	}
+	if len(resources) == 0 {
+		current.Selected = false
+		return []models.Resource{current}
+	}
+	return resources
+}
+
 func (m ResourceList) View() string {
 	if m.err != nil {
 		return listStyle.Render(m.err.Error())
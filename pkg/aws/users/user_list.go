@@ -45,8 +45,10 @@ func (m UserList) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "ctrl+c":
 			return m, tea.Quit
 		case "enter":
-			if !m.loading {
-				user := m.list.SelectedItem().(models.User)
+			if _, ok := m.list.SelectedItem().(models.CreateNew); ok && !m.loading {
+				return Switch(NewCreateUser(m.controller), m.list.Width(), m.list.Height())
+			}
+			if user, ok := m.list.SelectedItem().(models.User); ok && !m.loading {
 				m.controller.State.SetUser(&user)
 				return Switch(m.controller.Next(), m.list.Width(), m.list.Height())
 			}
@@ -55,9 +57,13 @@ func (m UserList) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		h, v := listStyle.GetFrameSize()
 		m.list.SetSize(msg.Width-h, msg.Height-v)
 	case UserLoadedMsg:
-		// Update list with loaded users
+		// Append the loaded page, more pages may follow
 		m.loading = false
-		m.list.SetItems(msg.List)
+		m.list.SetItems(append(m.list.Items(), msg.List...))
+		m.list.Title = pageTitle("Users", len(m.list.Items()), msg.Next != nil)
+		if msg.Next != nil {
+			return m, msg.Next
+		}
 	case FailedMsg:
 		// Handle error
 		m.loading = false
@@ -17,6 +17,9 @@ func NewAwsCommand(cfg *config.Config) *cobra.Command {
 	command.AddCommand(NewUsersCommand(cfg))
 	command.AddCommand(NewRolesCommand(cfg))
 	command.AddCommand(NewGroupsCommand(cfg))
+	command.AddCommand(NewPoliciesCommand())
+	command.AddCommand(NewInspectCommand())
+	command.AddCommand(NewSimulateCommand())
 
 	return command
 }
@@ -25,9 +25,45 @@ func RegisterUsersFlags(flags *pflag.FlagSet) {
 	if err = viper.BindPFlag("service", flags.Lookup("service")); err != nil {
 		panic(err)
 	}
+	if err = viper.BindPFlag("resource_query", flags.Lookup("resource-query")); err != nil {
+		panic(err)
+	}
+	if err = viper.BindPFlag("resource_tag", flags.Lookup("resource-tag")); err != nil {
+		panic(err)
+	}
 	if err = viper.BindPFlag("policy_option", flags.Lookup("policy-option")); err != nil {
 		panic(err)
 	}
+	if err = viper.BindPFlag("policy_file", flags.Lookup("policy-file")); err != nil {
+		panic(err)
+	}
+	if err = viper.BindPFlag("prune_oldest_version", flags.Lookup("prune-oldest-version")); err != nil {
+		panic(err)
+	}
+	if err = viper.BindPFlag("yes", flags.Lookup("yes")); err != nil {
+		panic(err)
+	}
+	if err = viper.BindPFlag("output", flags.Lookup("output")); err != nil {
+		panic(err)
+	}
+	if err = viper.BindPFlag("dry_run", flags.Lookup("dry-run")); err != nil {
+		panic(err)
+	}
+	if err = viper.BindPFlag("force", flags.Lookup("force")); err != nil {
+		panic(err)
+	}
+	if err = viper.BindPFlag("accounts", flags.Lookup("accounts")); err != nil {
+		panic(err)
+	}
+	if err = viper.BindPFlag("account_role", flags.Lookup("account-role")); err != nil {
+		panic(err)
+	}
+	if err = viper.BindPFlag("expires_in", flags.Lookup("expires-in")); err != nil {
+		panic(err)
+	}
+	if err = viper.BindPFlag("until", flags.Lookup("until")); err != nil {
+		panic(err)
+	}
 }
 
 func RegisterRolesFlags(flags *pflag.FlagSet) {
@@ -47,9 +83,51 @@ func RegisterRolesFlags(flags *pflag.FlagSet) {
 	if err = viper.BindPFlag("service", flags.Lookup("service")); err != nil {
 		panic(err)
 	}
+	if err = viper.BindPFlag("resource_query", flags.Lookup("resource-query")); err != nil {
+		panic(err)
+	}
+	if err = viper.BindPFlag("resource_tag", flags.Lookup("resource-tag")); err != nil {
+		panic(err)
+	}
 	if err = viper.BindPFlag("policy_option", flags.Lookup("policy-option")); err != nil {
 		panic(err)
 	}
+	if err = viper.BindPFlag("policy_file", flags.Lookup("policy-file")); err != nil {
+		panic(err)
+	}
+	if err = viper.BindPFlag("prune_oldest_version", flags.Lookup("prune-oldest-version")); err != nil {
+		panic(err)
+	}
+	if err = viper.BindPFlag("trust_add", flags.Lookup("trust-add")); err != nil {
+		panic(err)
+	}
+	if err = viper.BindPFlag("trust_remove", flags.Lookup("trust-remove")); err != nil {
+		panic(err)
+	}
+	if err = viper.BindPFlag("yes", flags.Lookup("yes")); err != nil {
+		panic(err)
+	}
+	if err = viper.BindPFlag("output", flags.Lookup("output")); err != nil {
+		panic(err)
+	}
+	if err = viper.BindPFlag("dry_run", flags.Lookup("dry-run")); err != nil {
+		panic(err)
+	}
+	if err = viper.BindPFlag("force", flags.Lookup("force")); err != nil {
+		panic(err)
+	}
+	if err = viper.BindPFlag("accounts", flags.Lookup("accounts")); err != nil {
+		panic(err)
+	}
+	if err = viper.BindPFlag("account_role", flags.Lookup("account-role")); err != nil {
+		panic(err)
+	}
+	if err = viper.BindPFlag("expires_in", flags.Lookup("expires-in")); err != nil {
+		panic(err)
+	}
+	if err = viper.BindPFlag("until", flags.Lookup("until")); err != nil {
+		panic(err)
+	}
 }
 
 func RegisterGroupsFlags(flags *pflag.FlagSet) {
@@ -69,7 +147,100 @@ func RegisterGroupsFlags(flags *pflag.FlagSet) {
 	if err = viper.BindPFlag("service", flags.Lookup("service")); err != nil {
 		panic(err)
 	}
+	if err = viper.BindPFlag("resource_query", flags.Lookup("resource-query")); err != nil {
+		panic(err)
+	}
+	if err = viper.BindPFlag("resource_tag", flags.Lookup("resource-tag")); err != nil {
+		panic(err)
+	}
 	if err = viper.BindPFlag("policy_option", flags.Lookup("policy-option")); err != nil {
 		panic(err)
 	}
+	if err = viper.BindPFlag("policy_file", flags.Lookup("policy-file")); err != nil {
+		panic(err)
+	}
+	if err = viper.BindPFlag("prune_oldest_version", flags.Lookup("prune-oldest-version")); err != nil {
+		panic(err)
+	}
+	if err = viper.BindPFlag("yes", flags.Lookup("yes")); err != nil {
+		panic(err)
+	}
+	if err = viper.BindPFlag("output", flags.Lookup("output")); err != nil {
+		panic(err)
+	}
+	if err = viper.BindPFlag("dry_run", flags.Lookup("dry-run")); err != nil {
+		panic(err)
+	}
+	if err = viper.BindPFlag("force", flags.Lookup("force")); err != nil {
+		panic(err)
+	}
+	if err = viper.BindPFlag("accounts", flags.Lookup("accounts")); err != nil {
+		panic(err)
+	}
+	if err = viper.BindPFlag("account_role", flags.Lookup("account-role")); err != nil {
+		panic(err)
+	}
+	if err = viper.BindPFlag("expires_in", flags.Lookup("expires-in")); err != nil {
+		panic(err)
+	}
+	if err = viper.BindPFlag("until", flags.Lookup("until")); err != nil {
+		panic(err)
+	}
+}
+
+func RegisterPoliciesFlags(flags *pflag.FlagSet) {
+	var err error
+	if err = viper.BindPFlag("policy", flags.Lookup("policy")); err != nil {
+		panic(err)
+	}
+	if err = viper.BindPFlag("yes", flags.Lookup("yes")); err != nil {
+		panic(err)
+	}
+	if err = viper.BindPFlag("output", flags.Lookup("output")); err != nil {
+		panic(err)
+	}
+	if err = viper.BindPFlag("dry_run", flags.Lookup("dry-run")); err != nil {
+		panic(err)
+	}
+}
+
+func RegisterInspectFlags(flags *pflag.FlagSet) {
+	var err error
+	if err = viper.BindPFlag("user", flags.Lookup("user")); err != nil {
+		panic(err)
+	}
+	if err = viper.BindPFlag("role", flags.Lookup("role")); err != nil {
+		panic(err)
+	}
+	if err = viper.BindPFlag("group", flags.Lookup("group")); err != nil {
+		panic(err)
+	}
+	if err = viper.BindPFlag("output", flags.Lookup("output")); err != nil {
+		panic(err)
+	}
+}
+
+func RegisterSimulateFlags(flags *pflag.FlagSet) {
+	var err error
+	if err = viper.BindPFlag("user", flags.Lookup("user")); err != nil {
+		panic(err)
+	}
+	if err = viper.BindPFlag("role", flags.Lookup("role")); err != nil {
+		panic(err)
+	}
+	if err = viper.BindPFlag("group", flags.Lookup("group")); err != nil {
+		panic(err)
+	}
+	if err = viper.BindPFlag("action", flags.Lookup("action")); err != nil {
+		panic(err)
+	}
+	if err = viper.BindPFlag("resource", flags.Lookup("resource")); err != nil {
+		panic(err)
+	}
+	if err = viper.BindPFlag("policy_file", flags.Lookup("policy-file")); err != nil {
+		panic(err)
+	}
+	if err = viper.BindPFlag("output", flags.Lookup("output")); err != nil {
+		panic(err)
+	}
 }
@@ -4,17 +4,19 @@ import (
 	"context"
 	"fmt"
 	"os"
-
-	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"slices"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
+	"github.com/Permify/targe/internal/ai"
 	internalaws "github.com/Permify/targe/internal/aws"
 	"github.com/Permify/targe/internal/config"
+	requirements "github.com/Permify/targe/internal/requirements/aws"
 	pkggroups "github.com/Permify/targe/pkg/aws/groups"
 	"github.com/Permify/targe/pkg/aws/models"
+	"github.com/Permify/targe/pkg/cmd/common"
 )
 
 type Groups struct {
@@ -48,7 +50,19 @@ func NewGroupsCommand(cfg *config.Config) *cobra.Command {
 	f.String("policy", "", "policy")
 	f.String("resource", "", "resource")
 	f.String("service", "", "service")
+	f.String("resource-query", "", "free text narrowing down the resource list")
+	f.StringSlice("resource-tag", nil, "only list resources with the tag key=value (repeatable)")
 	f.String("policy-option", "", "policy option")
+	f.String("policy-file", "", "path to a policy document for attach_custom_policy and attach_inline_policy, or the new document for edit_policy")
+	f.Bool("prune-oldest-version", false, "delete the oldest version when edit_policy hits the limit of 5 policy versions")
+	f.BoolP("yes", "y", false, "apply the operation without the interactive TUI")
+	f.String("output", OutputText, "output format for --yes (text or json)")
+	f.Bool("dry-run", false, "preview the IAM calls without applying them")
+	f.Bool("force", false, "apply a custom policy even when the linter reports errors")
+	f.StringSlice("accounts", nil, "apply the operation to these account ids, or 'all' accounts of the organization")
+	f.String("account-role", internalaws.DefaultAccountRole, "role assumed in each account of --accounts")
+	f.String("expires-in", "", "grant temporary access for a duration, e.g. 4h")
+	f.String("until", "", "grant temporary access until a time (RFC3339 or YYYY-MM-DD HH:MM)")
 
 	// SilenceUsage is set to true to suppress usage when an error occurs
 	command.SilenceUsage = true
@@ -62,84 +76,133 @@ func NewGroupsCommand(cfg *config.Config) *cobra.Command {
 
 func groups(cfg *config.Config) func(cmd *cobra.Command, args []string) error {
 	return func(cmd *cobra.Command, args []string) error {
+		// Check if the requirements folder exists, headless runs do not need it
+		if !viper.GetBool("yes") && !folderExists(requirements.Folder) {
+			if _, err := tea.NewProgram(common.NewRequirements()).Run(); err != nil {
+				fmt.Println("Error running program:", err)
+				os.Exit(1)
+			}
+		}
+
 		// get min coverage from viper
 		group := viper.GetString("group")
 		operation := viper.GetString("operation")
 		policy := viper.GetString("policy")
 		resource := viper.GetString("resource")
 		service := viper.GetString("service")
-		policyOption := viper.GetString("policy-option")
+		resourceQuery := viper.GetString("resource_query")
+		resourceTags := viper.GetStringSlice("resource_tag")
+		policyOption := viper.GetString("policy_option")
+		policyFile := viper.GetString("policy_file")
+		pruneOldestVersion := viper.GetBool("prune_oldest_version")
+		yes := viper.GetBool("yes")
+		output := viper.GetString("output")
+		dryRun := viper.GetBool("dry_run")
+		force := viper.GetBool("force")
+		accounts := viper.GetStringSlice("accounts")
+		accountRole := viper.GetString("account_role")
+		expiresIn := viper.GetString("expires_in")
+		until := viper.GetString("until")
+
+		if err := validateOutput(output); err != nil {
+			return err
+		}
 
 		// Load the AWS configuration
-		awscfg, err := awsconfig.LoadDefaultConfig(context.Background())
+		awscfg, err := common.LoadAwsConfig(context.Background())
 		if err != nil {
 			return err
 		}
 
 		api := internalaws.NewApi(awscfg)
-		state := &pkggroups.State{}
+		state, err := newGroupsState(api, Request{
+			Kind:          KindGroups,
+			Principal:     group,
+			Operation:     operation,
+			Policy:        policy,
+			Service:       service,
+			Resource:      resource,
+			ResourceQuery: resourceQuery,
+			ResourceTags:  resourceTags,
+			PolicyOption:  policyOption,
+		})
+		if err != nil {
+			return err
+		}
+
+		inline := state.GetOperation() != nil && (state.GetOperation().Id == pkggroups.AttachInlinePolicySlug.String() ||
+			state.GetOperation().Id == pkggroups.RemoveInlinePolicySlug.String())
 
-		if group != "" {
-			awsgroup, err := api.FindGroup(context.Background(), group)
+		editing := state.GetOperation() != nil && state.GetOperation().Id == pkggroups.EditPolicySlug.String()
+
+		if policyFile != "" {
+			policy, err := readPolicyFile(policyFile)
 			if err != nil {
 				return err
 			}
 
-			state.SetGroup(&models.Group{
-				Name: *awsgroup.Group.GroupName,
-				Arn:  *awsgroup.Group.Arn,
-			})
-		}
+			// edit_policy replaces the document of the selected policy instead of creating a new one
+			if editing {
+				existing := state.GetPolicy()
+				if existing == nil {
+					return fmt.Errorf("edit_policy needs --policy to select the policy the --policy-file document replaces")
+				}
+				policy.Arn, policy.Name = existing.Arn, existing.Name
+			}
 
-		if operation != "" {
-			// Check if the operation exists in the ReachableOperations map
-			op, exists := pkggroups.ReachableOperations[pkggroups.OperationType(operation)]
-			if !exists {
-				return fmt.Errorf("Operation '%s' does not exist in ReachableOperations\n", operation)
+			// attach_inline_policy names the inline policy after --policy when it is given
+			if inline {
+				if existing := state.GetPolicy(); existing != nil {
+					policy.Name = existing.Name
+				}
+				policy.Inline = true
 			}
 
-			state.SetOperation(&op)
+			state.SetPolicy(policy)
 		}
 
-		if policy != "" {
-			awspolicy, err := api.FindPolicy(context.Background(), policy)
-			if err != nil {
-				return err
-			}
+		state.SetPruneVersion(pruneOldestVersion)
 
-			state.SetPolicy(&models.Policy{
-				Name: *awspolicy.Policy.PolicyName,
-				Arn:  *awspolicy.Policy.Arn,
-			})
+		expiresAt, err := parseExpiry(expiresIn, until)
+		if err != nil {
+			return err
 		}
 
-		if resource != "" {
-			resourceName := parseResourceNameFromArn(resource)
-			state.SetResource(&models.Resource{
-				Name: resourceName,
-				Arn:  resource,
-			})
+		if expiresAt != nil {
+			if op := state.GetOperation(); op != nil && !slices.Contains(pkggroups.TemporaryOperations, pkggroups.OperationType(op.Id)) {
+				return fmt.Errorf("temporary access is only supported for attach_policy, attach_custom_policy and attach_inline_policy")
+			}
+
+			state.SetExpiresAt(expiresAt)
 		}
 
-		if service != "" {
-			state.SetService(&models.Service{
-				Name: service,
-			})
+		provider, err := ai.NewProvider(cfg)
+		if err != nil {
+			return err
 		}
 
-		if policyOption != "" {
-			// Check if the operation exists in the ReachableOperations map
-			op, exists := pkggroups.ReachablePolicyOptions[pkggroups.PolicyOptionType(policyOption)]
-			if !exists {
-				return fmt.Errorf("Policy options '%s' does not exist in ReachableCustomPolicyOptions\n", policyOption)
+		controller := pkggroups.NewController(api, provider, state)
+		controller.DryRun = dryRun
+		controller.Force = force
+
+		if len(accounts) > 0 {
+			controller.Accounts, err = api.Accounts(context.Background(), accounts, accountRole, common.AwsConfigOptions())
+			if err != nil {
+				return err
 			}
+		}
 
-			state.SetPolicyOption(&op)
+		if editing && state.GetPolicy() != nil {
+			if err := controller.InspectPolicy(context.Background()); err != nil {
+				return err
+			}
 		}
 
-		controller := pkggroups.NewController(api, cfg.OpenaiApiKey, state)
+		if yes {
+			return runHeadless(cmd.OutOrStdout(), controller, output, dryRun)
+		}
 
-		p := tea.NewProgram(RootModel(controller.Next()), tea.WithAltScreen())
+		p := tea.NewProgram(RootModel(controller.Next(), accountHeader(api, controller.Accounts)), tea.WithAltScreen())
 		if _, err := p.Run(); err != nil {
 			fmt.Println("Error running program:", err)
 			os.Exit(1)
@@ -148,3 +211,101 @@ func groups(cfg *config.Config) func(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 }
+
+// newGroupsState seeds the state of the groups flow with the request.
+func newGroupsState(api *internalaws.Api, req Request) (*pkggroups.State, error) {
+	state := &pkggroups.State{}
+
+	if req.Principal != "" {
+		awsgroup, err := api.FindGroup(context.Background(), req.Principal)
+		if err != nil {
+			return nil, err
+		}
+
+		state.SetGroup(&models.Group{
+			Name: *awsgroup.Group.GroupName,
+			Arn:  *awsgroup.Group.Arn,
+		})
+	}
+
+	if req.Operation != "" {
+		// Check if the operation exists in the ReachableOperations map
+		op, exists := pkggroups.ReachableOperations[pkggroups.OperationType(req.Operation)]
+		if !exists {
+			return nil, fmt.Errorf("Operation '%s' does not exist in ReachableOperations\n", req.Operation)
+		}
+
+		state.SetOperation(&op)
+	}
+
+	inline := state.GetOperation() != nil && (state.GetOperation().Id == pkggroups.AttachInlinePolicySlug.String() ||
+		state.GetOperation().Id == pkggroups.RemoveInlinePolicySlug.String())
+
+	// Inline policies are looked up on the principal when the operation is applied
+	if req.Policy != "" && inline {
+		state.SetPolicy(&models.Policy{
+			Name:   req.Policy,
+			Inline: true,
+		})
+	}
+
+	if req.Policy != "" && !inline {
+		awspolicy, err := api.FindPolicy(context.Background(), req.Policy)
+		if err != nil {
+			return nil, err
+		}
+
+		state.SetPolicy(&models.Policy{
+			Name: *awspolicy.Policy.PolicyName,
+			Arn:  *awspolicy.Policy.Arn,
+		})
+	}
+
+	if req.ResourceQuery != "" || len(req.ResourceTags) > 0 {
+		tags, err := parseResourceTags(req.ResourceTags)
+		if err != nil {
+			return nil, err
+		}
+		state.SetResourceQuery(&models.ResourceQuery{
+			Text: req.ResourceQuery,
+			Tags: tags,
+		})
+	}
+
+	if req.Resource != "" {
+		resourceName := parseResourceNameFromArn(req.Resource)
+		state.SetResources([]models.Resource{{
+			Name: resourceName,
+			Arn:  req.Resource,
+		}})
+	}
+
+	if req.Service != "" {
+		state.SetService(&models.Service{
+			Name: req.Service,
+		})
+	}
+
+	if req.PolicyOption != "" {
+		// Check if the operation exists in the ReachableOperations map
+		op, exists := pkggroups.ReachablePolicyOptions[pkggroups.PolicyOptionType(req.PolicyOption)]
+		if !exists {
+			return nil, fmt.Errorf("Policy options '%s' does not exist in ReachableCustomPolicyOptions\n", req.PolicyOption)
+		}
+
+		state.SetPolicyOption(&op)
+	}
+
+	// A policy the request comes with is used as it is
+	policy, err := requestPolicy(req, inline, state.GetPolicy())
+	if err != nil {
+		return nil, err
+	}
+	if policy != nil {
+		state.SetPolicy(policy)
+	}
+
+	state.SetPrompt(req.Prompt)
+
+	return state, nil
+}
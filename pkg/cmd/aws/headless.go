@@ -0,0 +1,147 @@
+package aws
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	internalaws "github.com/Permify/targe/internal/aws"
+)
+
+// Output formats supported by the --output flag.
+const (
+	OutputText = "text"
+	OutputJSON = "json"
+)
+
+// Flow is implemented by the users, groups and roles controllers.
+type Flow interface {
+	Missing() []string
+	Overview() [][]string
+	Plan() []internalaws.AccountPlan
+	Done() error
+	Results() []internalaws.AccountResult
+}
+
+// Summary is the machine readable result of a non-interactive run.
+type Summary struct {
+	Status   string                    `json:"status"`
+	Overview []SummaryField            `json:"overview,omitempty"`
+	Missing  []string                  `json:"missing,omitempty"`
+	Plan     []internalaws.AccountPlan `json:"plan,omitempty"`
+	Accounts []AccountSummary          `json:"accounts,omitempty"`
+	Error    string                    `json:"error,omitempty"`
+}
+
+// AccountSummary is the outcome of the operation in a single account.
+type AccountSummary struct {
+	Account string `json:"account"`
+	Name    string `json:"name,omitempty"`
+	Status  string `json:"status"`
+	Error   string `json:"error,omitempty"`
+}
+
+// SummaryField is a single row of the overview.
+type SummaryField struct {
+	Field  string `json:"field"`
+	Name   string `json:"name"`
+	Detail string `json:"detail"`
+}
+
+func validateOutput(output string) error {
+	if output != OutputText && output != OutputJSON {
+		return fmt.Errorf("invalid output format '%s', expected '%s' or '%s'", output, OutputText, OutputJSON)
+	}
+	return nil
+}
+
+// runHeadless applies a fully specified flow without starting the TUI.
+// With dryRun set it only prints the planned IAM calls. It returns an error
+// when inputs are missing or the operation fails, so the command exits with
+// a non-zero code.
+func runHeadless(w io.Writer, flow Flow, output string, dryRun bool) error {
+	summary := Summary{}
+	for _, row := range flow.Overview() {
+		summary.Overview = append(summary.Overview, SummaryField{Field: row[0], Name: row[1], Detail: row[2]})
+	}
+
+	if missing := flow.Missing(); len(missing) > 0 {
+		summary.Status = "invalid"
+		summary.Missing = missing
+		if err := printSummary(w, summary, output); err != nil {
+			return err
+		}
+		return fmt.Errorf("missing required inputs: %s", strings.Join(missing, ", "))
+	}
+
+	if dryRun {
+		summary.Status = "planned"
+		summary.Plan = flow.Plan()
+		return printSummary(w, summary, output)
+	}
+
+	err := flow.Done()
+	if err != nil {
+		summary.Status = "failed"
+		summary.Error = err.Error()
+	} else {
+		summary.Status = "success"
+	}
+
+	for _, result := range flow.Results() {
+		account := AccountSummary{Account: result.Account.Id, Name: result.Account.Name, Status: "success"}
+		if result.Err != nil {
+			account.Status = "failed"
+			account.Error = result.Err.Error()
+		}
+		summary.Accounts = append(summary.Accounts, account)
+	}
+
+	if perr := printSummary(w, summary, output); perr != nil {
+		return perr
+	}
+	return err
+}
+
+func printSummary(w io.Writer, summary Summary, output string) error {
+	if output == OutputJSON {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(summary)
+	}
+
+	switch summary.Status {
+	case "success":
+		fmt.Fprintln(w, "✔ Operation executed successfully!")
+	case "planned":
+		fmt.Fprintln(w, "Dry run, nothing has been applied.")
+	case "failed":
+		fmt.Fprintln(w, "✖ Operation failed")
+	case "invalid":
+		fmt.Fprintln(w, "✖ Missing required inputs:")
+		for _, m := range summary.Missing {
+			fmt.Fprintf(w, "  - %s\n", m)
+		}
+	}
+
+	for _, row := range summary.Overview {
+		fmt.Fprintf(w, "%-10s %s (%s)\n", row.Field+":", row.Name, row.Detail)
+	}
+
+	if len(summary.Accounts) > 0 {
+		fmt.Fprintln(w, "\nAccounts:")
+		for _, account := range summary.Accounts {
+			if account.Error != "" {
+				fmt.Fprintf(w, "  ✖ %s %s\n", account.Account, account.Error)
+			} else {
+				fmt.Fprintf(w, "  ✔ %s\n", account.Account)
+			}
+		}
+	}
+
+	if len(summary.Plan) > 0 {
+		fmt.Fprintf(w, "\nPlanned IAM calls:\n%s\n", internalaws.FormatAccountPlans(summary.Plan))
+	}
+	return nil
+}
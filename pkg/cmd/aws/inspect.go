@@ -0,0 +1,149 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	internalaws "github.com/Permify/targe/internal/aws"
+	"github.com/Permify/targe/internal/permissions"
+	"github.com/Permify/targe/pkg/cmd/common"
+)
+
+// NewInspectCommand - returns a new cobra command that shows the effective permissions of a principal
+func NewInspectCommand() *cobra.Command {
+	command := &cobra.Command{
+		Use:   "inspect",
+		Short: "Show what a user, group or role can do and where each permission comes from",
+		Args:  cobra.NoArgs,
+		RunE:  inspect(),
+	}
+
+	f := command.Flags()
+
+	f.String("user", "", "user to inspect, including the policies of its groups")
+	f.String("role", "", "role to inspect")
+	f.String("group", "", "group to inspect")
+	f.String("output", OutputText, "output format (text or json)")
+
+	// SilenceUsage is set to true to suppress usage when an error occurs
+	command.SilenceUsage = true
+
+	command.PreRun = func(cmd *cobra.Command, args []string) {
+		RegisterInspectFlags(f)
+	}
+
+	return command
+}
+
+func inspect() func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		user := viper.GetString("user")
+		role := viper.GetString("role")
+		group := viper.GetString("group")
+		output := viper.GetString("output")
+
+		if err := validateOutput(output); err != nil {
+			return err
+		}
+
+		given := 0
+		for _, principal := range []string{user, role, group} {
+			if principal != "" {
+				given++
+			}
+		}
+		if given != 1 {
+			return errors.New("inspect needs exactly one of --user, --role or --group")
+		}
+
+		// Load the AWS configuration
+		awscfg, err := common.LoadAwsConfig(context.Background())
+		if err != nil {
+			return err
+		}
+
+		api := internalaws.NewApi(awscfg)
+
+		var policies []internalaws.SourcedPolicy
+		switch {
+		case user != "":
+			policies, err = api.UserPolicies(context.Background(), user)
+		case role != "":
+			policies, err = api.RolePolicies(context.Background(), role)
+		default:
+			policies, err = api.GroupPolicies(context.Background(), group)
+		}
+		if err != nil {
+			return err
+		}
+
+		merged, err := permissions.Merge(policies)
+		if err != nil {
+			return err
+		}
+
+		return printPermissions(cmd.OutOrStdout(), merged, output)
+	}
+}
+
+// printPermissions prints the merged statement table.
+func printPermissions(w io.Writer, merged []permissions.Permission, output string) error {
+	if output == OutputJSON {
+		if merged == nil {
+			merged = []permissions.Permission{}
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(merged)
+	}
+
+	if len(merged) == 0 {
+		fmt.Fprintln(w, "No policies found.")
+		return nil
+	}
+
+	var rows [][]string
+	for _, permission := range merged {
+		rows = append(rows, []string{
+			permission.Service,
+			permission.Action,
+			permission.Effect,
+			strings.Join(permission.Resources, "\n"),
+			permission.Condition,
+			strings.Join(permission.Sources, "\n"),
+		})
+	}
+
+	t := table.New().
+		Border(lipgloss.NormalBorder()).
+		BorderStyle(lipgloss.NewStyle().Foreground(lipgloss.Color("99"))).
+		StyleFunc(func(row, col int) lipgloss.Style {
+			if row == table.HeaderRow {
+				return lipgloss.NewStyle().Foreground(lipgloss.Color("205")).Bold(true).Padding(0, 1)
+			}
+			style := lipgloss.NewStyle().Padding(0, 1)
+			if col == 2 {
+				switch merged[row].Effect {
+				case "Deny":
+					style = style.Foreground(lipgloss.Color("9"))
+				case permissions.EffectBoundary:
+					style = style.Foreground(lipgloss.Color("11"))
+				}
+			}
+			return style
+		}).
+		Headers("Service", "Action", "Effect", "Resource", "Condition", "Source").
+		Rows(rows...)
+
+	fmt.Fprintln(w, t.Render())
+	return nil
+}
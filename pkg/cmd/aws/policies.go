@@ -0,0 +1,120 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	internalaws "github.com/Permify/targe/internal/aws"
+	pkgpolicies "github.com/Permify/targe/pkg/aws/policies"
+	"github.com/Permify/targe/pkg/cmd/common"
+)
+
+// NewPoliciesCommand - returns a new cobra command for the policies created by targe
+func NewPoliciesCommand() *cobra.Command {
+	command := &cobra.Command{
+		Use:   "policies",
+		Short: "List and delete the custom policies created by targe",
+		RunE:  policies(),
+	}
+
+	f := command.Flags()
+
+	f.String("policy", "", "name or ARN of the policy to delete")
+	f.BoolP("yes", "y", false, "delete the policy without the interactive TUI")
+	f.String("output", OutputText, "output format for --yes (text or json)")
+	f.Bool("dry-run", false, "preview the IAM calls without applying them")
+
+	// SilenceUsage is set to true to suppress usage when an error occurs
+	command.SilenceUsage = true
+
+	command.PreRun = func(cmd *cobra.Command, args []string) {
+		RegisterPoliciesFlags(f)
+	}
+
+	return command
+}
+
+func policies() func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		policy := viper.GetString("policy")
+		yes := viper.GetBool("yes")
+		output := viper.GetString("output")
+		dryRun := viper.GetBool("dry_run")
+
+		if err := validateOutput(output); err != nil {
+			return err
+		}
+
+		// Load the AWS configuration
+		awscfg, err := common.LoadAwsConfig(context.Background())
+		if err != nil {
+			return err
+		}
+
+		api := internalaws.NewApi(awscfg)
+		state := &pkgpolicies.State{}
+
+		if policy != "" {
+			managed, err := findTargePolicy(context.Background(), api, policy)
+			if err != nil {
+				return err
+			}
+
+			item := pkgpolicies.NewManagedPolicy(*managed)
+			state.SetPolicy(&item)
+		}
+
+		controller := pkgpolicies.NewController(api, state)
+		controller.DryRun = dryRun
+
+		if yes {
+			if state.GetPolicy() != nil {
+				if err := controller.Inspect(context.Background()); err != nil {
+					return err
+				}
+			}
+			return runHeadless(cmd.OutOrStdout(), controller, output, dryRun)
+		}
+
+		p := tea.NewProgram(RootModel(controller.Next(), accountHeader(api, nil)), tea.WithAltScreen())
+		if _, err := p.Run(); err != nil {
+			fmt.Println("Error running program:", err)
+			os.Exit(1)
+		}
+
+		return nil
+	}
+}
+
+// findTargePolicy looks up a policy by name or ARN and makes sure targe created it.
+func findTargePolicy(ctx context.Context, api *internalaws.Api, policy string) (*internalaws.TargePolicy, error) {
+	arn := policy
+	if !strings.HasPrefix(policy, "arn:") {
+		callerArn, account, err := api.CallerIdentity(ctx)
+		if err != nil {
+			return nil, err
+		}
+		arn = fmt.Sprintf("arn:%s:iam::%s:policy/%s", internalaws.ArnPartition(callerArn), account, policy)
+	}
+
+	found, err := api.GetPolicy(ctx, arn)
+	if err != nil {
+		return nil, err
+	}
+	if !internalaws.CreatedByTarge(*found) {
+		return nil, fmt.Errorf("policy %s was not created by targe", arn)
+	}
+
+	versions, err := api.ListPolicyVersions(ctx, arn)
+	if err != nil {
+		return nil, err
+	}
+
+	return &internalaws.TargePolicy{Policy: *found, Versions: versions}, nil
+}
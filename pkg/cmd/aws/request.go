@@ -0,0 +1,269 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/Permify/targe/internal/ai"
+	internalaws "github.com/Permify/targe/internal/aws"
+	"github.com/Permify/targe/internal/config"
+	requirements "github.com/Permify/targe/internal/requirements/aws"
+	pkggroups "github.com/Permify/targe/pkg/aws/groups"
+	"github.com/Permify/targe/pkg/aws/models"
+	pkgroles "github.com/Permify/targe/pkg/aws/roles"
+	pkgusers "github.com/Permify/targe/pkg/aws/users"
+	"github.com/Permify/targe/pkg/cmd/common"
+)
+
+// Kinds of principals a request is for, named after their commands.
+const (
+	KindUsers  = "users"
+	KindGroups = "groups"
+	KindRoles  = "roles"
+)
+
+// Kinds lists the kinds of principals.
+var Kinds = []string{KindUsers, KindGroups, KindRoles}
+
+// Request is what the users, groups and roles flows start from, read from the flags of their
+// commands or suggested by `targe -m`. Empty fields are chosen in the flow.
+type Request struct {
+	// Kind is users, groups or roles.
+	Kind string
+	// Principal is the name of the user, group or role.
+	Principal string
+	Operation string
+	// Group is the group a user is added to or removed from.
+	Group         string
+	Policy        string
+	Service       string
+	Resource      string
+	ResourceQuery string
+	// ResourceTags are key=value pairs that narrow down the resource list.
+	ResourceTags []string
+	PolicyOption string
+	// PolicyDocument is the document of the custom or inline policy to attach.
+	PolicyDocument string
+	// PolicyFile is the file the document is saved to, see SavePolicyDocument.
+	PolicyFile string
+	// Prompt describes the custom or inline policy to generate.
+	Prompt string
+}
+
+// RequestFromResponse maps the response to the request it suggests. The prompt the response was
+// given for describes the custom policy to generate when the response has none. A bucket named
+// without its ARN gets the ARN of the partition, or is searched by name when the partition is empty.
+func RequestFromResponse(response ai.GPTResponse, prompt, partition string) Request {
+	req := Request{
+		Kind:      response.Principal["type"],
+		Principal: response.Principal["name"],
+		Operation: response.Action,
+	}
+
+	if req.Kind == KindUsers {
+		req.Group = response.Group
+	}
+
+	custom := response.Action == pkgusers.AttachCustomPolicySlug.String() || response.Action == pkgusers.AttachInlinePolicySlug.String()
+
+	// A policy that is neither managed nor an ARN describes a custom policy
+	if response.Action == pkgusers.AttachPolicySlug.String() && response.Policy != "" && !response.IsManagedPolicy && !strings.HasPrefix(response.Policy, "arn:") {
+		req.Operation, custom = pkgusers.AttachCustomPolicySlug.String(), true
+	}
+
+	if !custom {
+		req.Policy = response.Policy
+		return req
+	}
+
+	// The suggested policy is either the document or the description to generate it from
+	if policy, err := ai.ParsePolicy(response.Policy); err == nil && len(policy.Statement) > 0 {
+		req.PolicyDocument = response.Policy
+	} else if response.Policy != "" && !strings.HasPrefix(response.Policy, "arn:") {
+		req.Prompt = response.Policy
+	} else {
+		req.Prompt = prompt
+	}
+
+	req.Service = response.RequestedResourceType
+	switch resource := response.RequestedResource; {
+	case strings.HasPrefix(resource, "arn:"):
+		req.Resource = resource
+	case resource != "" && req.Service == "AWS::S3::Bucket" && partition != "":
+		// Bucket names are global within the partition, so is the ARN of a bucket
+		req.Resource = "arn:" + partition + ":s3:::" + resource
+	case resource != "":
+		req.ResourceQuery = resource
+	}
+
+	if req.PolicyDocument == "" {
+		req.PolicyOption = pkgusers.WithoutResourceSlug.String()
+		if req.Resource != "" || req.ResourceQuery != "" {
+			req.PolicyOption = pkgusers.WithResourceSlug.String()
+		}
+	}
+
+	return req
+}
+
+// SavePolicyDocument writes the policy document of the request to a temporary file, so the command
+// line of Args passes it with --policy-file.
+func (r *Request) SavePolicyDocument() error {
+	if r.PolicyDocument == "" {
+		return nil
+	}
+
+	file, err := os.CreateTemp("", "targe-policy-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to save the suggested policy: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.WriteString(r.PolicyDocument); err != nil {
+		return fmt.Errorf("failed to save the suggested policy: %w", err)
+	}
+
+	r.PolicyFile = file.Name()
+	return nil
+}
+
+// Args returns the command line that starts the flow of the request. The document is passed with
+// --policy-file once it is saved. The prompt has no flag, see Note.
+func (r Request) Args() []string {
+	args := []string{"aws", r.Kind}
+	add := func(flag, value string) {
+		if value != "" {
+			args = append(args, "--"+flag, value)
+		}
+	}
+
+	add(strings.TrimSuffix(r.Kind, "s"), r.Principal)
+	add("operation", r.Operation)
+	add("group", r.Group)
+	add("policy", r.Policy)
+	add("service", r.Service)
+	add("resource", r.Resource)
+	add("resource-query", r.ResourceQuery)
+	for _, tag := range r.ResourceTags {
+		add("resource-tag", tag)
+	}
+	add("policy-option", r.PolicyOption)
+	add("policy-file", r.PolicyFile)
+
+	return args
+}
+
+// Note returns what the command line of Args leaves out, or an empty string when running it
+// again starts the same flow.
+func (r Request) Note() string {
+	if r.Prompt == "" {
+		return ""
+	}
+	return "The policy description has no flag, running this command again asks for it."
+}
+
+// shellSafe matches the arguments that need no quotes.
+var shellSafe = regexp.MustCompile(`^[A-Za-z0-9_@%+=:,./-]+$`)
+
+// ShellQuote quotes the argument for POSIX shells when it contains anything but safe characters.
+func ShellQuote(arg string) string {
+	if shellSafe.MatchString(arg) {
+		return arg
+	}
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
+
+// RunRequest starts the flow of the request, as its command does without --yes.
+func RunRequest(cfg *config.Config, req Request) error {
+	if !slices.Contains(Kinds, req.Kind) {
+		return fmt.Errorf("invalid principal type '%s', expected one of: %s", req.Kind, strings.Join(Kinds, ", "))
+	}
+
+	if !folderExists(requirements.Folder) {
+		if _, err := tea.NewProgram(common.NewRequirements()).Run(); err != nil {
+			return err
+		}
+	}
+
+	awscfg, err := common.LoadAwsConfig(context.Background())
+	if err != nil {
+		return err
+	}
+	api := internalaws.NewApi(awscfg)
+
+	provider, err := ai.NewProvider(cfg)
+	if err != nil {
+		return err
+	}
+
+	// Without a provider the custom policy can only be built by hand
+	if provider == nil && req.PolicyDocument == "" && req.PolicyOption != "" {
+		req.PolicyOption = pkgusers.BuildSlug.String()
+	}
+
+	var model tea.Model
+	switch req.Kind {
+	case KindUsers:
+		state, err := newUsersState(api, req)
+		if err != nil {
+			return err
+		}
+		model = pkgusers.NewController(api, provider, state).Next()
+	case KindGroups:
+		state, err := newGroupsState(api, req)
+		if err != nil {
+			return err
+		}
+		model = pkggroups.NewController(api, provider, state).Next()
+	case KindRoles:
+		state, err := newRolesState(api, req)
+		if err != nil {
+			return err
+		}
+		model = pkgroles.NewController(api, provider, state).Next()
+	}
+
+	_, err = tea.NewProgram(RootModel(model, accountHeader(api, nil)), tea.WithAltScreen()).Run()
+	return err
+}
+
+// requestPolicy returns the policy of the request's document, named after the inline policy of
+// the request, nil when the request has no document.
+func requestPolicy(req Request, inline bool, existing *models.Policy) (*models.Policy, error) {
+	if req.PolicyDocument == "" {
+		return nil, nil
+	}
+
+	policy, err := ai.ParsePolicy(req.PolicyDocument)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the suggested policy: %w", err)
+	}
+
+	document, err := json.MarshalIndent(policy, "", "\t")
+	if err != nil {
+		return nil, err
+	}
+
+	name := policy.Id
+	if inline && existing != nil {
+		name = existing.Name
+	}
+	if name == "" {
+		name = "targe-" + time.Now().Format("20060102150405")
+	}
+
+	return &models.Policy{
+		Arn:      "new",
+		Name:     name,
+		Document: string(document),
+		Inline:   inline,
+	}, nil
+}
@@ -0,0 +1,153 @@
+package aws
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/Permify/targe/internal/ai"
+)
+
+func TestShellQuote(t *testing.T) {
+	tests := map[string]string{
+		"omer":                                   "omer",
+		"arn:aws:iam::aws:policy/ReadOnlyAccess": "arn:aws:iam::aws:policy/ReadOnlyAccess",
+		"key=value,team@example.com":             "key=value,team@example.com",
+		"Omer Yilmaz":                            "'Omer Yilmaz'",
+		"arn:aws:s3:::reports/*":                 "'arn:aws:s3:::reports/*'",
+		"it's":                                   `'it'\''s'`,
+		"$HOME":                                  "'$HOME'",
+		"":                                       "''",
+	}
+
+	for arg, want := range tests {
+		if got := ShellQuote(arg); got != want {
+			t.Errorf("ShellQuote(%q) = %s, want %s", arg, got, want)
+		}
+	}
+}
+
+func TestRequestFromResponse(t *testing.T) {
+	const document = `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"*"}]}`
+
+	tests := []struct {
+		name      string
+		response  ai.GPTResponse
+		partition string
+		want      Request
+	}{
+		{
+			name: "managed policy",
+			response: ai.GPTResponse{
+				Action:          "attach_policy",
+				Principal:       map[string]string{"type": "roles", "name": "deploy"},
+				Policy:          "arn:aws:iam::aws:policy/ReadOnlyAccess",
+				IsManagedPolicy: true,
+			},
+			want: Request{Kind: KindRoles, Principal: "deploy", Operation: "attach_policy", Policy: "arn:aws:iam::aws:policy/ReadOnlyAccess"},
+		},
+		{
+			name: "group of a user",
+			response: ai.GPTResponse{
+				Action:    "add_to_group",
+				Principal: map[string]string{"type": "users", "name": "omer"},
+				Group:     "developers",
+			},
+			want: Request{Kind: KindUsers, Principal: "omer", Operation: "add_to_group", Group: "developers"},
+		},
+		{
+			name: "policy that is not managed describes a custom policy",
+			response: ai.GPTResponse{
+				Action:    "attach_policy",
+				Principal: map[string]string{"type": "users", "name": "omer"},
+				Policy:    "read the reports bucket",
+			},
+			want: Request{Kind: KindUsers, Principal: "omer", Operation: "attach_custom_policy", Prompt: "read the reports bucket", PolicyOption: "without_resource"},
+		},
+		{
+			name: "custom policy with a document",
+			response: ai.GPTResponse{
+				Action:    "attach_inline_policy",
+				Principal: map[string]string{"type": "groups", "name": "ops"},
+				Policy:    document,
+			},
+			want: Request{Kind: KindGroups, Principal: "ops", Operation: "attach_inline_policy", PolicyDocument: document},
+		},
+		{
+			name: "bucket in the partition of the caller",
+			response: ai.GPTResponse{
+				Action:                "attach_custom_policy",
+				Principal:             map[string]string{"type": "roles", "name": "deploy"},
+				RequestedResourceType: "AWS::S3::Bucket",
+				RequestedResource:     "reports",
+			},
+			partition: "aws-cn",
+			want: Request{Kind: KindRoles, Principal: "deploy", Operation: "attach_custom_policy", Prompt: "grant role deploy access to bucket reports",
+				Service: "AWS::S3::Bucket", Resource: "arn:aws-cn:s3:::reports", PolicyOption: "with_resource"},
+		},
+		{
+			name: "bucket without a partition is searched by name",
+			response: ai.GPTResponse{
+				Action:                "attach_custom_policy",
+				Principal:             map[string]string{"type": "roles", "name": "deploy"},
+				RequestedResourceType: "AWS::S3::Bucket",
+				RequestedResource:     "reports",
+			},
+			want: Request{Kind: KindRoles, Principal: "deploy", Operation: "attach_custom_policy", Prompt: "grant role deploy access to bucket reports",
+				Service: "AWS::S3::Bucket", ResourceQuery: "reports", PolicyOption: "with_resource"},
+		},
+		{
+			name: "resource ARN",
+			response: ai.GPTResponse{
+				Action:                "attach_custom_policy",
+				Principal:             map[string]string{"type": "roles", "name": "deploy"},
+				RequestedResourceType: "AWS::DynamoDB::Table",
+				RequestedResource:     "arn:aws:dynamodb:eu-west-1:123456789012:table/orders",
+			},
+			partition: "aws",
+			want: Request{Kind: KindRoles, Principal: "deploy", Operation: "attach_custom_policy", Prompt: "grant role deploy access to bucket reports",
+				Service: "AWS::DynamoDB::Table", Resource: "arn:aws:dynamodb:eu-west-1:123456789012:table/orders", PolicyOption: "with_resource"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RequestFromResponse(tt.response, "grant role deploy access to bucket reports", tt.partition)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("RequestFromResponse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequestArgs(t *testing.T) {
+	req := Request{Kind: KindUsers, Principal: "Omer Yilmaz", Operation: "attach_policy", Policy: "arn:aws:iam::aws:policy/ReadOnlyAccess"}
+	want := []string{"aws", "users", "--user", "Omer Yilmaz", "--operation", "attach_policy", "--policy", "arn:aws:iam::aws:policy/ReadOnlyAccess"}
+	if got := req.Args(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Args() = %v, want %v", got, want)
+	}
+	if note := req.Note(); note != "" {
+		t.Errorf("Note() = %q, want none", note)
+	}
+
+	// A document is passed as a file, a prompt is noted as left out
+	req = Request{Kind: KindRoles, Principal: "deploy", Operation: "attach_inline_policy", PolicyDocument: `{"Version":"2012-10-17","Statement":[]}`}
+	if err := req.SavePolicyDocument(); err != nil {
+		t.Fatalf("SavePolicyDocument() error = %v", err)
+	}
+	defer os.Remove(req.PolicyFile)
+
+	saved, err := os.ReadFile(req.PolicyFile)
+	if err != nil || string(saved) != req.PolicyDocument {
+		t.Fatalf("SavePolicyDocument() saved %q, %v, want the document", saved, err)
+	}
+	want = []string{"aws", "roles", "--role", "deploy", "--operation", "attach_inline_policy", "--policy-file", req.PolicyFile}
+	if got := req.Args(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Args() = %v, want %v", got, want)
+	}
+
+	req = Request{Kind: KindRoles, Principal: "deploy", Operation: "attach_custom_policy", Prompt: "read the reports bucket", PolicyOption: "without_resource"}
+	if note := req.Note(); note == "" {
+		t.Error("Note() is empty for a request with a prompt")
+	}
+}
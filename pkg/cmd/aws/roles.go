@@ -4,18 +4,22 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"slices"
+	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
-	awsconfig "github.com/aws/aws-sdk-go-v2/config"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
+	"github.com/Permify/targe/internal/ai"
 	internalaws "github.com/Permify/targe/internal/aws"
 	"github.com/Permify/targe/internal/config"
+	requirements "github.com/Permify/targe/internal/requirements/aws"
 	"github.com/Permify/targe/pkg/aws/models"
 	pkgroles "github.com/Permify/targe/pkg/aws/roles"
+	"github.com/Permify/targe/pkg/cmd/common"
 )
 
 type Roles struct {
@@ -49,7 +53,21 @@ func NewRolesCommand(cfg *config.Config) *cobra.Command {
 	f.String("policy", "", "policy")
 	f.String("resource", "", "resource")
 	f.String("service", "", "service")
+	f.String("resource-query", "", "free text narrowing down the resource list")
+	f.StringSlice("resource-tag", nil, "only list resources with the tag key=value (repeatable)")
 	f.String("policy-option", "", "policy option")
+	f.String("policy-file", "", "path to a policy document for attach_custom_policy and attach_inline_policy, or the new document for edit_policy")
+	f.Bool("prune-oldest-version", false, "delete the oldest version when edit_policy hits the limit of 5 policy versions")
+	f.StringSlice("trust-add", nil, "allow a principal to assume the role with edit_trust_policy, e.g. Service=ec2.amazonaws.com (repeatable)")
+	f.StringSlice("trust-remove", nil, "remove a principal from the trust policy with edit_trust_policy, e.g. AWS=arn:aws:iam::123456789012:root (repeatable)")
+	f.BoolP("yes", "y", false, "apply the operation without the interactive TUI")
+	f.String("output", OutputText, "output format for --yes (text or json)")
+	f.Bool("dry-run", false, "preview the IAM calls without applying them")
+	f.Bool("force", false, "apply a custom policy even when the linter reports errors")
+	f.StringSlice("accounts", nil, "apply the operation to these account ids, or 'all' accounts of the organization")
+	f.String("account-role", internalaws.DefaultAccountRole, "role assumed in each account of --accounts")
+	f.String("expires-in", "", "grant temporary access for a duration, e.g. 4h")
+	f.String("until", "", "grant temporary access until a time (RFC3339 or YYYY-MM-DD HH:MM)")
 
 	// SilenceUsage is set to true to suppress usage when an error occurs
 	command.SilenceUsage = true
@@ -63,84 +81,159 @@ func NewRolesCommand(cfg *config.Config) *cobra.Command {
 
 func roles(cfg *config.Config) func(cmd *cobra.Command, args []string) error {
 	return func(cmd *cobra.Command, args []string) error {
+		// Check if the requirements folder exists, headless runs do not need it
+		if !viper.GetBool("yes") && !folderExists(requirements.Folder) {
+			if _, err := tea.NewProgram(common.NewRequirements()).Run(); err != nil {
+				fmt.Println("Error running program:", err)
+				os.Exit(1)
+			}
+		}
+
 		// get min coverage from viper
 		role := viper.GetString("role")
 		operation := viper.GetString("operation")
 		policy := viper.GetString("policy")
 		resource := viper.GetString("resource")
 		service := viper.GetString("service")
-		policyOption := viper.GetString("policy-option")
+		resourceQuery := viper.GetString("resource_query")
+		resourceTags := viper.GetStringSlice("resource_tag")
+		policyOption := viper.GetString("policy_option")
+		policyFile := viper.GetString("policy_file")
+		pruneOldestVersion := viper.GetBool("prune_oldest_version")
+		trustAdd := viper.GetStringSlice("trust_add")
+		trustRemove := viper.GetStringSlice("trust_remove")
+		yes := viper.GetBool("yes")
+		output := viper.GetString("output")
+		dryRun := viper.GetBool("dry_run")
+		force := viper.GetBool("force")
+		accounts := viper.GetStringSlice("accounts")
+		accountRole := viper.GetString("account_role")
+		expiresIn := viper.GetString("expires_in")
+		until := viper.GetString("until")
+
+		if err := validateOutput(output); err != nil {
+			return err
+		}
+
+		// A trust policy names the principals of its own account
+		if len(accounts) > 0 && operation == pkgroles.EditTrustPolicySlug.String() {
+			return fmt.Errorf("--accounts cannot be used with --operation %s, the trust policy is edited in a single account", operation)
+		}
 
 		// Load the AWS configuration
-		awscfg, err := awsconfig.LoadDefaultConfig(context.Background())
+		awscfg, err := common.LoadAwsConfig(context.Background())
 		if err != nil {
 			return err
 		}
 
 		api := internalaws.NewApi(awscfg)
-		state := &pkgroles.State{}
+		state, err := newRolesState(api, Request{
+			Kind:          KindRoles,
+			Principal:     role,
+			Operation:     operation,
+			Policy:        policy,
+			Service:       service,
+			Resource:      resource,
+			ResourceQuery: resourceQuery,
+			ResourceTags:  resourceTags,
+			PolicyOption:  policyOption,
+		})
+		if err != nil {
+			return err
+		}
 
-		if role != "" {
-			awsrole, err := api.FindRole(context.Background(), role)
+		inline := state.GetOperation() != nil && (state.GetOperation().Id == pkgroles.AttachInlinePolicySlug.String() ||
+			state.GetOperation().Id == pkgroles.RemoveInlinePolicySlug.String())
+
+		editing := state.GetOperation() != nil && state.GetOperation().Id == pkgroles.EditPolicySlug.String()
+
+		if policyFile != "" {
+			policy, err := readPolicyFile(policyFile)
 			if err != nil {
 				return err
 			}
 
-			state.SetRole(&models.Role{
-				Name: aws.ToString(awsrole.Role.RoleName),
-				Arn:  aws.ToString(awsrole.Role.Arn),
-			})
+			// edit_policy replaces the document of the selected policy instead of creating a new one
+			if editing {
+				existing := state.GetPolicy()
+				if existing == nil {
+					return fmt.Errorf("edit_policy needs --policy to select the policy the --policy-file document replaces")
+				}
+				policy.Arn, policy.Name = existing.Arn, existing.Name
+			}
+
+			// attach_inline_policy names the inline policy after --policy when it is given
+			if inline {
+				if existing := state.GetPolicy(); existing != nil {
+					policy.Name = existing.Name
+				}
+				policy.Inline = true
+			}
+
+			state.SetPolicy(policy)
+		}
+
+		state.SetPruneVersion(pruneOldestVersion)
+
+		expiresAt, err := parseExpiry(expiresIn, until)
+		if err != nil {
+			return err
 		}
 
-		if operation != "" {
-			// Check if the operation exists in the ReachableOperations map
-			op, exists := pkgroles.ReachableOperations[pkgroles.OperationType(operation)]
-			if !exists {
-				return fmt.Errorf("Operation '%s' does not exist in ReachableOperations\n", operation)
+		if expiresAt != nil {
+			if op := state.GetOperation(); op != nil && !slices.Contains(pkgroles.TemporaryOperations, pkgroles.OperationType(op.Id)) {
+				return fmt.Errorf("temporary access is only supported for attach_policy, attach_custom_policy and attach_inline_policy")
 			}
 
-			state.SetOperation(&op)
+			state.SetExpiresAt(expiresAt)
+		}
+
+		provider, err := ai.NewProvider(cfg)
+		if err != nil {
+			return err
 		}
 
-		if policy != "" {
-			awspolicy, err := api.FindPolicy(context.Background(), policy)
+		controller := pkgroles.NewController(api, provider, state)
+		controller.DryRun = dryRun
+		controller.Force = force
+
+		if len(accounts) > 0 {
+			controller.Accounts, err = api.Accounts(context.Background(), accounts, accountRole, common.AwsConfigOptions())
 			if err != nil {
 				return err
 			}
-
-			state.SetPolicy(&models.Policy{
-				Name: aws.ToString(awspolicy.Policy.PolicyName),
-				Arn:  aws.ToString(awspolicy.Policy.Arn),
-			})
 		}
 
-		if service != "" {
-			state.SetService(&models.Service{
-				Name: service,
-			})
+		if editing && state.GetPolicy() != nil {
+			if err := controller.InspectPolicy(context.Background()); err != nil {
+				return err
+			}
 		}
 
-		if resource != "" {
-			resourceName := parseResourceNameFromArn(resource)
-			state.SetResource(&models.Resource{
-				Name: resourceName,
-				Arn:  resource,
-			})
+		if len(trustAdd) > 0 || len(trustRemove) > 0 {
+			if state.GetRole() == nil || state.GetOperation() == nil || state.GetOperation().Id != pkgroles.EditTrustPolicySlug.String() {
+				return fmt.Errorf("--trust-add and --trust-remove need --role and --operation edit_trust_policy")
+			}
+			if err := controller.InspectTrustPolicy(context.Background()); err != nil {
+				return err
+			}
+			if err := changeTrustPrincipals(controller, trustAdd, trustRemove); err != nil {
+				return err
+			}
 		}
 
-		if policyOption != "" {
-			// Check if the operation exists in the ReachableOperations map
-			op, exists := pkgroles.ReachablePolicyOptions[pkgroles.PolicyOptionType(policyOption)]
-			if !exists {
-				return fmt.Errorf("Policy options '%s' does not exist in ReachableCustomPolicyOptions\n", policyOption)
+		// The summary shows the permissions boundary in effect
+		if yes && state.GetRole() != nil {
+			if err := controller.InspectBoundary(context.Background()); err != nil {
+				return err
 			}
-
-			state.SetPolicyOption(&op)
 		}
 
-		controller := pkgroles.NewController(api, cfg.OpenaiApiKey, state)
+		if yes {
+			return runHeadless(cmd.OutOrStdout(), controller, output, dryRun)
+		}
 
-		p := tea.NewProgram(RootModel(controller.Next()), tea.WithAltScreen())
+		p := tea.NewProgram(RootModel(controller.Next(), accountHeader(api, controller.Accounts)), tea.WithAltScreen())
 		if _, err := p.Run(); err != nil {
 			fmt.Println("Error running program:", err)
 			os.Exit(1)
@@ -149,3 +242,125 @@ func roles(cfg *config.Config) func(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 }
+
+// changeTrustPrincipals applies the --trust-add and --trust-remove principals, given as type=principal.
+func changeTrustPrincipals(controller *pkgroles.Controller, add, remove []string) error {
+	for _, change := range []struct {
+		add   bool
+		flag  string
+		pairs []string
+	}{{true, "--trust-add", add}, {false, "--trust-remove", remove}} {
+		for _, pair := range change.pairs {
+			name, principal, ok := strings.Cut(pair, "=")
+			if !ok || principal == "" {
+				return fmt.Errorf("invalid %s %q, expected type=principal", change.flag, pair)
+			}
+			principalType, err := ai.PrincipalType(name)
+			if err != nil {
+				return err
+			}
+			if err := controller.ChangeTrustPrincipal(change.add, principalType, principal); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// newRolesState seeds the state of the roles flow with the request.
+func newRolesState(api *internalaws.Api, req Request) (*pkgroles.State, error) {
+	state := &pkgroles.State{}
+
+	if req.Principal != "" {
+		awsrole, err := api.FindRole(context.Background(), req.Principal)
+		if err != nil {
+			return nil, err
+		}
+
+		state.SetRole(&models.Role{
+			Name: aws.ToString(awsrole.Role.RoleName),
+			Arn:  aws.ToString(awsrole.Role.Arn),
+		})
+	}
+
+	if req.Operation != "" {
+		// Check if the operation exists in the ReachableOperations map
+		op, exists := pkgroles.ReachableOperations[pkgroles.OperationType(req.Operation)]
+		if !exists {
+			return nil, fmt.Errorf("Operation '%s' does not exist in ReachableOperations\n", req.Operation)
+		}
+
+		state.SetOperation(&op)
+	}
+
+	inline := state.GetOperation() != nil && (state.GetOperation().Id == pkgroles.AttachInlinePolicySlug.String() ||
+		state.GetOperation().Id == pkgroles.RemoveInlinePolicySlug.String())
+
+	// Inline policies are looked up on the principal when the operation is applied
+	if req.Policy != "" && inline {
+		state.SetPolicy(&models.Policy{
+			Name:   req.Policy,
+			Inline: true,
+		})
+	}
+
+	if req.Policy != "" && !inline {
+		awspolicy, err := api.FindPolicy(context.Background(), req.Policy)
+		if err != nil {
+			return nil, err
+		}
+
+		state.SetPolicy(&models.Policy{
+			Name: aws.ToString(awspolicy.Policy.PolicyName),
+			Arn:  aws.ToString(awspolicy.Policy.Arn),
+		})
+	}
+
+	if req.Service != "" {
+		state.SetService(&models.Service{
+			Name: req.Service,
+		})
+	}
+
+	if req.ResourceQuery != "" || len(req.ResourceTags) > 0 {
+		tags, err := parseResourceTags(req.ResourceTags)
+		if err != nil {
+			return nil, err
+		}
+		state.SetResourceQuery(&models.ResourceQuery{
+			Text: req.ResourceQuery,
+			Tags: tags,
+		})
+	}
+
+	if req.Resource != "" {
+		resourceName := parseResourceNameFromArn(req.Resource)
+		state.SetResources([]models.Resource{{
+			Name: resourceName,
+			Arn:  req.Resource,
+		}})
+	}
+
+	if req.PolicyOption != "" {
+		// Check if the operation exists in the ReachableOperations map
+		op, exists := pkgroles.ReachablePolicyOptions[pkgroles.PolicyOptionType(req.PolicyOption)]
+		if !exists {
+			return nil, fmt.Errorf("Policy options '%s' does not exist in ReachableCustomPolicyOptions\n", req.PolicyOption)
+		}
+
+		state.SetPolicyOption(&op)
+	}
+
+	// A policy the request comes with is used as it is
+	policy, err := requestPolicy(req, inline, state.GetPolicy())
+	if err != nil {
+		return nil, err
+	}
+	if policy != nil {
+		state.SetPolicy(policy)
+	}
+
+	state.SetPrompt(req.Prompt)
+
+	return state, nil
+}
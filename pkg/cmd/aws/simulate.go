@@ -0,0 +1,223 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/Permify/targe/internal/ai"
+	internalaws "github.com/Permify/targe/internal/aws"
+	"github.com/Permify/targe/pkg/cmd/common"
+)
+
+// NewSimulateCommand - returns a new cobra command that runs the IAM policy simulator
+func NewSimulateCommand() *cobra.Command {
+	command := &cobra.Command{
+		Use:   "simulate",
+		Short: "Check whether actions are allowed before access is granted",
+		Args:  cobra.NoArgs,
+		RunE:  simulate(),
+	}
+
+	f := command.Flags()
+
+	f.String("user", "", "user to simulate")
+	f.String("role", "", "role to simulate")
+	f.String("group", "", "group to simulate")
+	f.StringSlice("action", nil, "action to simulate, e.g. s3:GetObject (repeatable)")
+	f.StringSlice("resource", nil, "resource ARN to simulate the actions on (repeatable)")
+	f.String("policy-file", "", "simulate a new policy document instead of the policies of the principal")
+	f.String("output", OutputText, "output format (text or json)")
+
+	// SilenceUsage is set to true to suppress usage when an error occurs
+	command.SilenceUsage = true
+
+	command.PreRun = func(cmd *cobra.Command, args []string) {
+		RegisterSimulateFlags(f)
+	}
+
+	return command
+}
+
+func simulate() func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		user := viper.GetString("user")
+		role := viper.GetString("role")
+		group := viper.GetString("group")
+		actions := viper.GetStringSlice("action")
+		resources := viper.GetStringSlice("resource")
+		policyFile := viper.GetString("policy_file")
+		output := viper.GetString("output")
+
+		if err := validateOutput(output); err != nil {
+			return err
+		}
+
+		given := 0
+		for _, principal := range []string{user, role, group} {
+			if principal != "" {
+				given++
+			}
+		}
+		if given > 1 || (given == 0 && policyFile == "") {
+			return errors.New("simulate needs one of --user, --role or --group, or a --policy-file")
+		}
+
+		// Load the AWS configuration
+		awscfg, err := common.LoadAwsConfig(context.Background())
+		if err != nil {
+			return err
+		}
+
+		api := internalaws.NewApi(awscfg)
+		ctx := context.Background()
+
+		var simulation []internalaws.Simulation
+		if policyFile != "" {
+			policy, err := readPolicyFile(policyFile)
+			if err != nil {
+				return err
+			}
+
+			// The actions of the document are simulated unless others are given
+			if len(actions) == 0 {
+				parsed, err := ai.ParsePolicy(policy.Document)
+				if err != nil {
+					return err
+				}
+				actions = parsed.SimulatableActions()
+			}
+			if len(actions) == 0 {
+				return errors.New("the policy allows no actions without wildcards, name them with --action")
+			}
+
+			boundaries, err := principalBoundary(ctx, api, user, role)
+			if err != nil {
+				return err
+			}
+
+			simulation, err = api.SimulateCustomPolicy(ctx, []string{policy.Document}, boundaries, actions, resources)
+			if err != nil {
+				return err
+			}
+		} else {
+			if len(actions) == 0 {
+				return errors.New("simulate needs at least one --action")
+			}
+
+			principalArn, err := principalArn(ctx, api, user, role, group)
+			if err != nil {
+				return err
+			}
+
+			simulation, err = api.SimulatePrincipalPolicy(ctx, principalArn, actions, resources, nil)
+			if err != nil {
+				return err
+			}
+		}
+
+		return printSimulation(cmd.OutOrStdout(), simulation, output)
+	}
+}
+
+// principalArn looks up the ARN of the given user, role or group.
+func principalArn(ctx context.Context, api *internalaws.Api, user, role, group string) (string, error) {
+	switch {
+	case user != "":
+		found, err := api.FindUser(ctx, user)
+		if err != nil {
+			return "", err
+		}
+		return *found.User.Arn, nil
+	case role != "":
+		found, err := api.FindRole(ctx, role)
+		if err != nil {
+			return "", err
+		}
+		return *found.Role.Arn, nil
+	default:
+		found, err := api.FindGroup(ctx, group)
+		if err != nil {
+			return "", err
+		}
+		return *found.Group.Arn, nil
+	}
+}
+
+// principalBoundary returns the document of the permissions boundary of the user or role, if any.
+func principalBoundary(ctx context.Context, api *internalaws.Api, user, role string) ([]string, error) {
+	var boundaryArn string
+	var err error
+	switch {
+	case user != "":
+		boundaryArn, err = api.GetUserPermissionsBoundary(ctx, user)
+	case role != "":
+		boundaryArn, err = api.GetRolePermissionsBoundary(ctx, role)
+	}
+	if err != nil || boundaryArn == "" {
+		return nil, err
+	}
+
+	document, err := api.GetDefaultPolicyDocument(ctx, boundaryArn)
+	if err != nil {
+		return nil, err
+	}
+	return []string{document}, nil
+}
+
+// printSimulation prints the decision of the simulator for each action and resource.
+func printSimulation(w io.Writer, simulation []internalaws.Simulation, output string) error {
+	if output == OutputJSON {
+		if simulation == nil {
+			simulation = []internalaws.Simulation{}
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(simulation)
+	}
+
+	var rows [][]string
+	for _, result := range simulation {
+		rows = append(rows, []string{
+			result.Action,
+			result.Resource,
+			result.Decision,
+			result.DeniedBy,
+			strings.Join(result.Missing, ", "),
+		})
+	}
+
+	t := table.New().
+		Border(lipgloss.NormalBorder()).
+		BorderStyle(lipgloss.NewStyle().Foreground(lipgloss.Color("99"))).
+		StyleFunc(func(row, col int) lipgloss.Style {
+			if row == table.HeaderRow {
+				return lipgloss.NewStyle().Foreground(lipgloss.Color("205")).Bold(true).Padding(0, 1)
+			}
+			style := lipgloss.NewStyle().Padding(0, 1)
+			if col == 2 {
+				switch simulation[row].Decision {
+				case internalaws.DecisionAllowed:
+					style = style.Foreground(lipgloss.Color("10"))
+				case internalaws.DecisionExplicitDeny:
+					style = style.Foreground(lipgloss.Color("9"))
+				default:
+					style = style.Foreground(lipgloss.Color("11"))
+				}
+			}
+			return style
+		}).
+		Headers("Action", "Resource", "Decision", "Denied By", "Missing Context").
+		Rows(rows...)
+
+	fmt.Fprintln(w, t.Render())
+	return nil
+}
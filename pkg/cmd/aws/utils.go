@@ -1,16 +1,55 @@
 package aws
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Permify/targe/internal/ai"
+	internalaws "github.com/Permify/targe/internal/aws"
+	"github.com/Permify/targe/pkg/aws/models"
 )
 
-func RootModel(m tea.Model) Users {
+func RootModel(m tea.Model, header string) Users {
 	return Users{
-		model: m,
+		model:  m,
+		header: header,
+	}
+}
+
+var accountHeaderStyle = lipgloss.NewStyle().
+	Foreground(lipgloss.Color("#FFFDF5")).
+	Background(lipgloss.Color("#874BFD")).
+	Padding(0, 1).
+	MarginLeft(2)
+
+// accountHeader renders the account the flow operates on, so nobody grants access in the wrong account.
+func accountHeader(api *internalaws.Api, accounts []internalaws.Account) string {
+	ctx := context.Background()
+
+	account := "unknown account"
+	if _, id, err := api.CallerIdentity(ctx); err == nil {
+		account = "Account " + id
+	}
+	if alias, err := api.AccountAlias(ctx); err == nil && alias != "" {
+		account += " (" + alias + ")"
+	}
+	if region := api.Region(); region != "" {
+		account += " · " + region
 	}
+
+	if len(accounts) > 0 {
+		account += fmt.Sprintf(" · applying to %d accounts", len(accounts))
+	}
+
+	return accountHeaderStyle.Render(account)
 }
 
 func folderExists(folderPath string) bool {
@@ -29,3 +68,80 @@ func parseResourceNameFromArn(arn string) string {
 	}
 	return arn
 }
+
+// readPolicyFile loads a policy document from disk for the attach_custom_policy operation.
+// The policy name is taken from the document Id, or from the file name when the Id is empty.
+func readPolicyFile(path string) (*models.Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy file: %w", err)
+	}
+
+	var policy ai.IAMPolicy
+	if err := json.Unmarshal(data, &policy); err != nil {
+		return nil, fmt.Errorf("failed to parse policy file %s: %w", path, err)
+	}
+
+	document, err := json.MarshalIndent(policy, "", "\t")
+	if err != nil {
+		return nil, err
+	}
+
+	name := policy.Id
+	if name == "" {
+		name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+
+	return &models.Policy{
+		Arn:      "new",
+		Name:     name,
+		Document: string(document),
+	}, nil
+}
+
+// parseExpiry resolves the --expires-in and --until flags to the time at which a grant expires.
+func parseExpiry(expiresIn, until string) (*time.Time, error) {
+	if expiresIn != "" && until != "" {
+		return nil, fmt.Errorf("--expires-in and --until cannot be used together")
+	}
+
+	var expiresAt time.Time
+	switch {
+	case expiresIn != "":
+		d, err := time.ParseDuration(expiresIn)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --expires-in '%s': %w", expiresIn, err)
+		}
+		expiresAt = time.Now().Add(d)
+	case until != "":
+		t, err := time.Parse(time.RFC3339, until)
+		if err != nil {
+			t, err = time.ParseInLocation("2006-01-02 15:04", until, time.Local)
+			if err != nil {
+				return nil, fmt.Errorf("invalid --until '%s', expected RFC3339 or YYYY-MM-DD HH:MM", until)
+			}
+		}
+		expiresAt = t
+	default:
+		return nil, nil
+	}
+
+	if !expiresAt.After(time.Now()) {
+		return nil, fmt.Errorf("expiry %s is in the past", expiresAt.Format(time.RFC3339))
+	}
+
+	return &expiresAt, nil
+}
+
+// parseResourceTags parses the key=value pairs of the --resource-tag flag.
+func parseResourceTags(pairs []string) (map[string]string, error) {
+	tags := map[string]string{}
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --resource-tag %q, expected key=value", pair)
+		}
+		tags[key] = value
+	}
+	return tags, nil
+}
@@ -0,0 +1,27 @@
+package common
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/spf13/viper"
+
+	internalaws "github.com/Permify/targe/internal/aws"
+)
+
+// AwsConfigOptions returns the AWS configuration options selected by the global
+// --profile, --region, --assume-role-arn, --external-id and --session-name flags.
+func AwsConfigOptions() internalaws.ConfigOptions {
+	return internalaws.ConfigOptions{
+		Profile:       viper.GetString("profile"),
+		Region:        viper.GetString("region"),
+		AssumeRoleArn: viper.GetString("assume_role_arn"),
+		ExternalId:    viper.GetString("external_id"),
+		SessionName:   viper.GetString("session_name"),
+	}
+}
+
+// LoadAwsConfig loads the AWS configuration selected by the global flags.
+func LoadAwsConfig(ctx context.Context) (aws.Config, error) {
+	return internalaws.LoadConfig(ctx, AwsConfigOptions())
+}
@@ -26,6 +26,7 @@ var (
 	currentPkgNameStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("211"))
 	doneStyle           = lipgloss.NewStyle().Margin(1, 2)
 	checkMark           = lipgloss.NewStyle().Foreground(lipgloss.Color("42")).SetString("✓")
+	crossMark           = lipgloss.NewStyle().Foreground(lipgloss.Color("9")).SetString("✗")
 )
 
 func NewRequirements() RequirementsManager {
@@ -77,9 +78,24 @@ func (m RequirementsManager) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			install(m.requirements[m.index]),    // download the next package
 		)
 	case installErrorMsg:
-		// Update state for errors
-		tea.Printf("Error installing package: %v", msg.Err)
-		return m, nil
+		// The failed requirement is reported and the installation goes on with the next one
+		pkg := m.requirements[m.index]
+		if m.index >= len(m.requirements)-1 {
+			m.done = true
+			return m, tea.Sequence(
+				tea.Printf("%s %s: %v", crossMark, pkg.GetName(), msg.Err),
+				tea.Quit,
+			)
+		}
+
+		m.index++
+		progressCmd := m.progress.SetPercent(float64(m.index) / float64(len(m.requirements)))
+
+		return m, tea.Batch(
+			progressCmd,
+			tea.Printf("%s %s: %v", crossMark, pkg.GetName(), msg.Err),
+			install(m.requirements[m.index]),
+		)
 	case spinner.TickMsg:
 		var cmd tea.Cmd
 		m.spinner, cmd = m.spinner.Update(msg)
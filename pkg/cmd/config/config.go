@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -36,8 +38,8 @@ func newConfigSetCommand() *cobra.Command {
 			key := args[0]
 			value := args[1]
 
-			if key != "openai_api_key" {
-				return fmt.Errorf("invalid key: %s", key)
+			if !slices.Contains(config.Keys, key) {
+				return fmt.Errorf("invalid key: %s, expected one of: %s", key, strings.Join(config.Keys, ", "))
 			}
 
 			// Start with the default configuration values
@@ -58,9 +60,6 @@ func newConfigSetCommand() *cobra.Command {
 				}
 			}
 
-			// Update the key-value pair
-			cfg.OpenaiApiKey = value
-
 			// Read the config file
 			err := viper.ReadInConfig()
 			if err != nil {
@@ -74,6 +73,9 @@ func newConfigSetCommand() *cobra.Command {
 				}
 			}
 
+			// Update the key-value pair
+			viper.Set(key, value)
+
 			filePath := filepath.Join(configPath, "config.toml")
 			if err := writeConfig(filePath, cfg); err != nil {
 				return fmt.Errorf("failed to create config file: %w", err)
@@ -94,8 +96,8 @@ func newConfigGetCommand() *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			key := args[0]
 
-			if key != "openai_api_key" {
-				return fmt.Errorf("invalid key: %s", key)
+			if !slices.Contains(config.Keys, key) {
+				return fmt.Errorf("invalid key: %s, expected one of: %s", key, strings.Join(config.Keys, ", "))
 			}
 
 			// Start with the default configuration values
@@ -135,22 +137,30 @@ func newConfigGetCommand() *cobra.Command {
 				return fmt.Errorf("failed to unmarshal server config: %w", err)
 			}
 
-			fmt.Printf("%s=%s\n", key, cfg.OpenaiApiKey)
+			fmt.Printf("%s=%s\n", key, viper.GetString(key))
 			return nil
 		},
 	}
 }
 
 func writeConfig(filePath string, cfg *config.Config) error {
-	// Use viper to write the default configuration to a file
-	viper.Set("openai_api_key", cfg.OpenaiApiKey)
+	// Only the configuration keys are written. The global flags are bound to viper too, and a flag
+	// of one run must not end up in the configuration file.
+	v := viper.New()
+	v.SetDefault("openai_api_key", cfg.OpenaiApiKey)
+	for _, key := range config.Keys {
+		if viper.IsSet(key) {
+			v.Set(key, viper.GetString(key))
+		}
+	}
+
 	file, err := os.Create(filePath)
 	if err != nil {
 		return err
 	}
 	defer file.Close()
 
-	if err := viper.WriteConfigAs(filePath); err != nil {
+	if err := v.WriteConfigAs(filePath); err != nil {
 		return fmt.Errorf("failed to write default config: %w", err)
 	}
 	return nil
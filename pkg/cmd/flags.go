@@ -11,3 +11,22 @@ func RegisterRootFlags(flags *pflag.FlagSet) {
 		panic(err)
 	}
 }
+
+func RegisterGlobalFlags(flags *pflag.FlagSet) {
+	var err error
+	if err = viper.BindPFlag("profile", flags.Lookup("profile")); err != nil {
+		panic(err)
+	}
+	if err = viper.BindPFlag("region", flags.Lookup("region")); err != nil {
+		panic(err)
+	}
+	if err = viper.BindPFlag("assume_role_arn", flags.Lookup("assume-role-arn")); err != nil {
+		panic(err)
+	}
+	if err = viper.BindPFlag("external_id", flags.Lookup("external-id")); err != nil {
+		panic(err)
+	}
+	if err = viper.BindPFlag("session_name", flags.Lookup("session-name")); err != nil {
+		panic(err)
+	}
+}
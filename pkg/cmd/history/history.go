@@ -0,0 +1,137 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+	"github.com/spf13/cobra"
+
+	"github.com/Permify/targe/internal/audit"
+)
+
+// NewHistoryCommand - returns a new cobra command that shows the audit log
+func NewHistoryCommand() *cobra.Command {
+	command := &cobra.Command{
+		Use:   "history",
+		Short: "Show the audit log of access changes",
+		Args:  cobra.NoArgs,
+		RunE:  history,
+	}
+
+	f := command.Flags()
+
+	f.String("principal", "", "only show entries for this user, group or role")
+	f.String("operation", "", "only show entries for this operation")
+	f.String("since", "", "only show entries at or after this date (YYYY-MM-DD or RFC3339)")
+	f.String("until", "", "only show entries before the end of this date (YYYY-MM-DD or RFC3339)")
+	f.String("output", "table", "output format (table or json)")
+
+	// SilenceUsage is set to true to suppress usage when an error occurs
+	command.SilenceUsage = true
+
+	return command
+}
+
+func history(cmd *cobra.Command, args []string) error {
+	f := cmd.Flags()
+	principal, _ := f.GetString("principal")
+	operation, _ := f.GetString("operation")
+	since, _ := f.GetString("since")
+	until, _ := f.GetString("until")
+	output, _ := f.GetString("output")
+
+	if output != "table" && output != "json" {
+		return fmt.Errorf("invalid output format '%s', expected 'table' or 'json'", output)
+	}
+
+	filter := audit.Filter{
+		Principal: principal,
+		Operation: operation,
+	}
+
+	var err error
+	if since != "" {
+		if filter.Since, _, err = parseDate(since); err != nil {
+			return err
+		}
+	}
+	if until != "" {
+		var dateOnly bool
+		if filter.Until, dateOnly, err = parseDate(until); err != nil {
+			return err
+		}
+		// A plain date includes the whole day
+		if dateOnly {
+			filter.Until = filter.Until.AddDate(0, 0, 1)
+		}
+	}
+
+	entries, err := audit.List(filter)
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+
+	if output == "json" {
+		if entries == nil {
+			entries = []audit.Entry{}
+		}
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(entries)
+	}
+
+	if len(entries) == 0 {
+		fmt.Fprintln(out, "No entries found.")
+		return nil
+	}
+
+	var rows [][]string
+	for _, entry := range entries {
+		target := entry.PolicyArn
+		if entry.Group != "" {
+			target = entry.Group
+		}
+		rows = append(rows, []string{
+			entry.Time.Local().Format("2006-01-02 15:04:05"),
+			entry.Caller,
+			entry.Flow,
+			entry.Operation,
+			entry.Principal,
+			target,
+			entry.Outcome,
+			entry.Error,
+		})
+	}
+
+	t := table.New().
+		Border(lipgloss.NormalBorder()).
+		BorderStyle(lipgloss.NewStyle().Foreground(lipgloss.Color("99"))).
+		StyleFunc(func(row, col int) lipgloss.Style {
+			if row == table.HeaderRow {
+				return lipgloss.NewStyle().Foreground(lipgloss.Color("205")).Bold(true).Padding(0, 1)
+			}
+			return lipgloss.NewStyle().Padding(0, 1)
+		}).
+		Headers("Time", "Caller", "Flow", "Operation", "Principal", "Policy / Group", "Outcome", "Error").
+		Rows(rows...)
+
+	fmt.Fprintln(out, t.Render())
+	return nil
+}
+
+// parseDate parses a date or an RFC3339 timestamp and reports whether only a date was given.
+func parseDate(value string) (time.Time, bool, error) {
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, true, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid date '%s', expected YYYY-MM-DD or RFC3339", value)
+	}
+	return t, false, nil
+}
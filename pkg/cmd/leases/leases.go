@@ -0,0 +1,194 @@
+package leases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Permify/targe/internal/audit"
+	internalaws "github.com/Permify/targe/internal/aws"
+	"github.com/Permify/targe/internal/lease"
+	"github.com/Permify/targe/pkg/cmd/common"
+)
+
+// ReapOperation is the operation recorded in the audit log when an expired lease is revoked.
+const ReapOperation = "reap_lease"
+
+// NewLeasesCommand - returns a new cobra command for temporary access grants
+func NewLeasesCommand() *cobra.Command {
+	command := &cobra.Command{
+		Use:   "leases",
+		Short: "Manage temporary access grants",
+	}
+
+	// Add subcommands
+	command.AddCommand(newLeasesListCommand())
+	command.AddCommand(newLeasesReapCommand())
+
+	return command
+}
+
+// newLeasesListCommand - returns a cobra command that lists leases
+func newLeasesListCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List temporary access grants",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			leases, err := lease.List()
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if len(leases) == 0 {
+				fmt.Fprintln(out, "No leases found.")
+				return nil
+			}
+
+			now := time.Now()
+			for _, l := range leases {
+				status := "active"
+				switch {
+				case l.ReapedAt != nil:
+					status = "revoked"
+				case l.Expired(now):
+					status = "expired"
+				}
+				fmt.Fprintf(out, "%4s  %-8s %-8s %-14s %-20s %s  %s\n", l.Id, status, l.Flow, l.Operation, l.Principal, l.ExpiresAt.Local().Format("2006-01-02 15:04"), l.Target)
+			}
+
+			return nil
+		},
+	}
+}
+
+// newLeasesReapCommand - returns a cobra command that revokes expired leases
+func newLeasesReapCommand() *cobra.Command {
+	command := &cobra.Command{
+		Use:   "reap",
+		Short: "Revoke temporary access grants that have expired",
+		Args:  cobra.NoArgs,
+		RunE:  reap,
+	}
+
+	command.Flags().Bool("dry-run", false, "list the expired grants without revoking them")
+
+	// SilenceUsage is set to true to suppress usage when an error occurs
+	command.SilenceUsage = true
+
+	return command
+}
+
+func reap(cmd *cobra.Command, args []string) error {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	leases, err := lease.List()
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	now := time.Now()
+
+	var expired []int
+	for i, l := range leases {
+		if l.Expired(now) {
+			expired = append(expired, i)
+		}
+	}
+
+	if len(expired) == 0 {
+		fmt.Fprintln(out, "No expired leases.")
+		return nil
+	}
+
+	if dryRun {
+		for _, i := range expired {
+			l := leases[i]
+			fmt.Fprintf(out, "Lease #%s: %s on %s %s expired at %s\n%s\n\n", l.Id, l.Operation, l.Flow, l.Principal, l.ExpiresAt.Local().Format("2006-01-02 15:04"), internalaws.FormatPlan(l.Revoke))
+		}
+		return nil
+	}
+
+	// Load the AWS configuration
+	awscfg, err := common.LoadAwsConfig(context.Background())
+	if err != nil {
+		return err
+	}
+
+	api := internalaws.NewApi(awscfg)
+	opts := common.AwsConfigOptions()
+
+	var failures []error
+	for _, i := range expired {
+		l := &leases[i]
+
+		// Grants made in another account are revoked through the same role
+		leaseApi := api
+		if l.RoleArn != "" {
+			leaseApi = internalaws.NewApi(internalaws.AssumeRole(awscfg, l.RoleArn, opts.ExternalId, opts.SessionName))
+		}
+
+		rerr := revoke(leaseApi, l.Revoke)
+		if rerr == nil {
+			// Only this lease is rewritten, leases added meanwhile by another run are kept
+			if err := lease.MarkReaped(l.Id); err != nil {
+				failures = append(failures, err)
+			}
+			fmt.Fprintf(out, "✔ Lease #%s revoked: %s on %s %s\n", l.Id, l.Operation, l.Flow, l.Principal)
+		} else {
+			failures = append(failures, fmt.Errorf("lease #%s: %w", l.Id, rerr))
+			fmt.Fprintf(out, "✖ Lease #%s could not be revoked: %s\n", l.Id, rerr)
+		}
+
+		if aerr := audit.Record(auditEntry(leaseApi, *l, rerr)); aerr != nil {
+			failures = append(failures, aerr)
+		}
+	}
+
+	return errors.Join(failures...)
+}
+
+// revoke performs the calls of a lease in order and stops at the first failure.
+func revoke(api *internalaws.Api, calls []internalaws.Call) error {
+	for _, call := range calls {
+		if err := api.Execute(context.Background(), call); err != nil {
+			return fmt.Errorf("%s failed: %w", call.Action, err)
+		}
+	}
+	return nil
+}
+
+func auditEntry(api *internalaws.Api, l lease.Lease, err error) audit.Entry {
+	entry := audit.Entry{
+		Caller:    "unknown",
+		Flow:      l.Flow,
+		Operation: ReapOperation,
+		Principal: l.Principal,
+		Calls:     l.Revoke,
+		Outcome:   audit.OutcomeSuccess,
+	}
+
+	for _, call := range l.Revoke {
+		if arn := call.Param("PolicyArn"); arn != "" {
+			entry.PolicyArn = arn
+		}
+		if group := call.Param("GroupName"); group != "" && call.Action == internalaws.RemoveUserFromGroupAction {
+			entry.Group = group
+		}
+	}
+
+	if arn, account, ierr := api.CallerIdentity(context.Background()); ierr == nil {
+		entry.Caller, entry.Account = arn, account
+	}
+	if err != nil {
+		entry.Outcome = audit.OutcomeFailure
+		entry.Error = err.Error()
+	}
+
+	return entry
+}
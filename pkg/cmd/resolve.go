@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+
+	"github.com/Permify/targe/internal/ai"
+)
+
+// resolveNames asks which user, group, role or policy of the account the names of the response
+// refer to, for the names that do not match one exactly and the policies of low confidence.
+func resolveNames(response *ai.GPTResponse, entities ai.Entities) error {
+	for _, resolution := range ai.Resolve(*response, entities) {
+		kind := strings.TrimSuffix(resolution.Kind, "s")
+		if resolution.Kind == "policies" {
+			kind = "policy"
+		}
+
+		if len(resolution.Candidates) == 0 {
+			return fmt.Errorf("no %s of the account matches '%s'", kind, resolution.Query)
+		}
+
+		options := make([]huh.Option[string], 0, len(resolution.Candidates))
+		for _, candidate := range resolution.Candidates {
+			options = append(options, huh.NewOption(fmt.Sprintf("%s (%d%%)", candidate.Label, int(candidate.Score*100)), candidate.Value))
+		}
+
+		description := fmt.Sprintf("No %s is named '%s'.", kind, resolution.Query)
+		if resolution.Field == ai.FieldPolicy && response.Confidence < ai.MinConfidence {
+			description = fmt.Sprintf("'%s' was suggested with low confidence (%d/10).", resolution.Query, response.Confidence)
+		}
+
+		value := resolution.Candidates[0].Value
+		err := huh.NewForm(
+			huh.NewGroup(
+				huh.NewSelect[string]().
+					Title(fmt.Sprintf("Which %s did you mean?", kind)).
+					Description(description).
+					Options(options...).
+					Value(&value),
+			),
+		).
+			WithWidth(60).
+			WithShowHelp(false).
+			Run()
+		if errors.Is(err, huh.ErrUserAborted) {
+			return errors.New("command aborted")
+		}
+		if err != nil {
+			return err
+		}
+
+		resolution.Apply(response, value)
+	}
+	return nil
+}
@@ -1,8 +1,10 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"slices"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -12,14 +14,22 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/Permify/targe/internal/ai"
+	internalaws "github.com/Permify/targe/internal/aws"
+	requirements "github.com/Permify/targe/internal/requirements/aws"
 	configc "github.com/Permify/targe/pkg/cmd/config"
 
 	"github.com/Permify/targe/internal/config"
 	"github.com/Permify/targe/pkg/cmd/aws"
+	"github.com/Permify/targe/pkg/cmd/common"
+	"github.com/Permify/targe/pkg/cmd/history"
+	"github.com/Permify/targe/pkg/cmd/leases"
+	"github.com/Permify/targe/pkg/cmd/undo"
 )
 
 type RootModel struct {
-	command  string
+	args []string
+	// note says what the command leaves out, see Request.Note.
+	note     string
 	choice   string
 	quitting bool
 }
@@ -33,9 +43,9 @@ func (m RootModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "y", "Y", "", tea.KeyEnter.String():
-			return RootModel{command: m.command, choice: "yes", quitting: true}, tea.Quit
+			return RootModel{args: m.args, note: m.note, choice: "yes", quitting: true}, tea.Quit
 		case "n", "N":
-			return RootModel{command: m.command, choice: "no", quitting: true}, tea.Quit
+			return RootModel{args: m.args, note: m.note, choice: "no", quitting: true}, tea.Quit
 		case tea.KeyCtrlC.String(), tea.KeyEsc.String():
 			return m, tea.Quit
 		}
@@ -68,6 +78,11 @@ func (m RootModel) View() string {
 		Italic(true).
 		PaddingLeft(2)
 
+	noteStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("8")).
+		Italic(true).
+		PaddingLeft(2)
+
 	promptStyle := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("3")).
 		PaddingTop(1)
@@ -77,8 +92,11 @@ func (m RootModel) View() string {
 	header := headerStyle.Render("Here’s your command:")
 
 	// Format the command
-	formattedCommand := formatCommand(m.command, 2)
+	formattedCommand := formatCommand(m.args, 2)
 	message := messageStyle.Render(fmt.Sprintf("➤ targe %s", formattedCommand))
+	if m.note != "" {
+		message += "\n" + noteStyle.Render(m.note)
+	}
 	prompt := promptStyle.Render("Would you like to use this command? (Y/n):")
 
 	// Combine output
@@ -105,6 +123,14 @@ func NewRootCommand() *cobra.Command {
 
 	f.String("m", "", "message")
 
+	pf := root.PersistentFlags()
+
+	pf.String("profile", "", "AWS shared config profile")
+	pf.String("region", "", "AWS region")
+	pf.String("assume-role-arn", "", "ARN of a role to assume before calling AWS")
+	pf.String("external-id", "", "external id for --assume-role-arn")
+	pf.String("session-name", "", "role session name for --assume-role-arn")
+
 	// SilenceUsage is set to true to suppress usage when an error occurs
 	root.SilenceUsage = true
 
@@ -112,10 +138,17 @@ func NewRootCommand() *cobra.Command {
 		RegisterRootFlags(f)
 	}
 
+	root.PersistentPreRun = func(cmd *cobra.Command, args []string) {
+		RegisterGlobalFlags(pf)
+	}
+
 	configCommand := configc.NewConfigCommand()
 	awsCommand := aws.NewAwsCommand(cfg)
+	undoCommand := undo.NewUndoCommand()
+	historyCommand := history.NewHistoryCommand()
+	leasesCommand := leases.NewLeasesCommand()
 
-	root.AddCommand(awsCommand, configCommand)
+	root.AddCommand(awsCommand, configCommand, undoCommand, historyCommand, leasesCommand)
 
 	return root
 }
@@ -124,15 +157,58 @@ func r(cfg *config.Config) func(cmd *cobra.Command, args []string) error {
 	return func(cmd *cobra.Command, args []string) error {
 		message := viper.GetString("m")
 
-		gptResponse, err := ai.UserPrompt(cfg.OpenaiApiKey, message)
+		provider, err := ai.NewProvider(cfg)
 		if err != nil {
 			return err
 		}
 
-		command := ai.GenerateCLICommand(gptResponse)
+		var entities ai.Entities
+		var gptResponse ai.GPTResponse
+		if provider == nil {
+			// Without a provider the request is parsed offline, names mentioned without their kind
+			// are looked up among every principal of the account
+			entities = accountEntities(aws.Kinds...)
+			gptResponse = ai.ParseIntent(message, entities)
+			if gptResponse.Error {
+				return fmt.Errorf("could not understand '%s', %s; configure an AI provider with targe config or start a flow with targe aws", message, gptResponse.Message)
+			}
+		} else {
+			gptResponse, err = ai.UserPrompt(provider, message)
+			if err != nil {
+				return err
+			}
+
+			// Only the principals of the kinds the response names are listed
+			kinds := []string{gptResponse.Principal["type"]}
+			if gptResponse.Group != "" {
+				kinds = append(kinds, aws.KindGroups)
+			}
+			entities = accountEntities(kinds...)
+		}
+
+		// The suggested names may not exist in the account as they are written
+		if err := resolveNames(&gptResponse, entities); err != nil {
+			return err
+		}
+
+		// Bucket ARNs are built in the partition of the caller
+		partition := ""
+		if gptResponse.RequestedResource != "" {
+			partition = callerPartition()
+		}
+
+		req := aws.RequestFromResponse(gptResponse, message, partition)
+		if !slices.Contains(aws.Kinds, req.Kind) {
+			return fmt.Errorf("could not tell whether '%s' is for a user, group or role", message)
+		}
+
+		// The shown command passes the suggested document as a file
+		if err := req.SavePolicyDocument(); err != nil {
+			return err
+		}
 
 		// Bubble Tea program setup
-		program := tea.NewProgram(&RootModel{command: command})
+		program := tea.NewProgram(&RootModel{args: req.Args(), note: req.Note()})
 		mod, err := program.Run()
 		if err != nil {
 			return fmt.Errorf("program encountered an error: %w", err)
@@ -140,24 +216,99 @@ func r(cfg *config.Config) func(cmd *cobra.Command, args []string) error {
 
 		// Check user choice
 		if result, ok := mod.(RootModel); ok && result.choice == "yes" {
-			var args []string
-			args = append(args, strings.Split(command, " ")...)
-			cmd.SetArgs(args)
-			return cmd.Root().Execute()
+			return aws.RunRequest(cfg, req)
 		}
 
 		return nil
 	}
 }
 
-// Helper function to format a command
-func formatCommand(command string, maxWordsPerLine int) string {
-	parts := strings.Fields(command) // Split command into words
+// callerPartition returns the partition of the credentials in use, or an empty string with a warning
+// when the caller identity cannot be read.
+func callerPartition() string {
+	awscfg, err := common.LoadAwsConfig(context.Background())
+	if err == nil {
+		var arn string
+		if arn, _, err = internalaws.NewApi(awscfg).CallerIdentity(context.Background()); err == nil {
+			return internalaws.ArnPartition(arn)
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "Warning: could not read the caller identity, the resource is searched by name: %v\n", err)
+	return ""
+}
+
+// accountEntities lists the managed policies and the principals of the kinds the names of a
+// request are matched against. Whatever cannot be listed is left out with a warning, its names are
+// then used as they are written.
+func accountEntities(kinds ...string) ai.Entities {
+	var entities ai.Entities
+
+	warn := func(what string, err error) {
+		fmt.Fprintf(os.Stderr, "Warning: could not list the %s, names are not matched against them: %v\n", what, err)
+	}
+
+	// The managed policies are known once the requirements are installed
+	if _, err := os.Stat(requirements.Folder); err == nil {
+		if policies, err := (requirements.ManagedPolicies{}).GetPolicies(); err == nil {
+			for _, policy := range policies {
+				entities.Policies = append(entities.Policies, policy.Arn)
+			}
+		} else {
+			warn("managed policies", err)
+		}
+	}
+
+	if !slices.ContainsFunc(kinds, func(kind string) bool { return slices.Contains(aws.Kinds, kind) }) {
+		return entities
+	}
+
+	awscfg, err := common.LoadAwsConfig(context.Background())
+	if err != nil {
+		warn("users, groups and roles of the account", err)
+		return entities
+	}
+	api := internalaws.NewApi(awscfg)
+
+	if slices.Contains(kinds, aws.KindUsers) {
+		if users, err := api.ListUsers(context.Background()); err == nil {
+			for _, user := range users {
+				entities.Users = append(entities.Users, *user.UserName)
+			}
+		} else {
+			warn("users of the account", err)
+		}
+	}
+	if slices.Contains(kinds, aws.KindGroups) {
+		if groups, err := api.ListGroups(context.Background()); err == nil {
+			for _, group := range groups {
+				entities.Groups = append(entities.Groups, *group.GroupName)
+			}
+		} else {
+			warn("groups of the account", err)
+		}
+	}
+	if slices.Contains(kinds, aws.KindRoles) {
+		if roles, err := api.ListRoles(context.Background()); err == nil {
+			for _, role := range roles {
+				entities.Roles = append(entities.Roles, *role.RoleName)
+			}
+		} else {
+			warn("roles of the account", err)
+		}
+	}
+
+	return entities
+}
+
+// formatCommand quotes the arguments for the shell and breaks the command into lines of
+// maxWordsPerLine arguments.
+func formatCommand(args []string, maxWordsPerLine int) string {
 	var result []string
 	var line []string
 
-	for _, part := range parts {
-		line = append(line, part)
+	for _, arg := range args {
+		line = append(line, aws.ShellQuote(arg))
 		if len(line) >= maxWordsPerLine {
 			result = append(result, strings.Join(line, " ")+" \\")
 			line = []string{}
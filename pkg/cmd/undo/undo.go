@@ -0,0 +1,180 @@
+package undo
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Permify/targe/internal/audit"
+	internalaws "github.com/Permify/targe/internal/aws"
+	"github.com/Permify/targe/internal/journal"
+	"github.com/Permify/targe/internal/lease"
+	"github.com/Permify/targe/pkg/cmd/common"
+)
+
+// UndoOperationPrefix prefixes the operation of a journal entry in the audit log when it is undone.
+const UndoOperationPrefix = "undo_"
+
+// NewUndoCommand - returns a new cobra command that reverts an applied operation
+func NewUndoCommand() *cobra.Command {
+	command := &cobra.Command{
+		Use:   "undo [id]",
+		Short: "Revert an operation recorded in the journal",
+		Long:  "Revert an operation recorded in the journal. Without an id the latest operation that has not been undone is reverted.",
+		Args:  cobra.MaximumNArgs(1),
+		RunE:  undo,
+	}
+
+	f := command.Flags()
+
+	f.Bool("list", false, "list the journal entries")
+	f.BoolP("yes", "y", false, "revert without asking for confirmation")
+
+	// SilenceUsage is set to true to suppress usage when an error occurs
+	command.SilenceUsage = true
+
+	return command
+}
+
+func undo(cmd *cobra.Command, args []string) error {
+	list, _ := cmd.Flags().GetBool("list")
+	yes, _ := cmd.Flags().GetBool("yes")
+
+	if list {
+		return listEntries(cmd)
+	}
+
+	var id string
+	if len(args) > 0 {
+		id = args[0]
+	}
+
+	entry, err := journal.Find(id)
+	if err != nil {
+		return err
+	}
+
+	if entry.UndoneAt != nil {
+		return fmt.Errorf("journal entry '%s' was already undone at %s", entry.Id, entry.UndoneAt.Local().Format("2006-01-02 15:04:05"))
+	}
+
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "Undo #%s: %s on %s %s (%s)\n\n", entry.Id, entry.Operation, entry.Flow, entry.Principal, entry.Time.Local().Format("2006-01-02 15:04:05"))
+	if entry.RoleArn != "" {
+		fmt.Fprintf(out, "Through role %s\n\n", entry.RoleArn)
+	}
+	fmt.Fprintln(out, internalaws.FormatPlan(entry.Inverse))
+
+	if !yes {
+		fmt.Fprint(out, "\nProceed? (y/N): ")
+		answer, _ := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+		if a := strings.ToLower(strings.TrimSpace(answer)); a != "y" && a != "yes" {
+			fmt.Fprintln(out, "✘ Undo aborted.")
+			return nil
+		}
+	}
+
+	// Load the AWS configuration
+	awscfg, err := common.LoadAwsConfig(context.Background())
+	if err != nil {
+		return err
+	}
+
+	// Operations applied in another account are reverted through the same role
+	if entry.RoleArn != "" {
+		opts := common.AwsConfigOptions()
+		awscfg = internalaws.AssumeRole(awscfg, entry.RoleArn, opts.ExternalId, opts.SessionName)
+	}
+
+	api := internalaws.NewApi(awscfg)
+
+	applied, err := revert(api, entry.Inverse)
+
+	// Undoing changes access too, so it is written to the audit log whatever its outcome
+	aerr := audit.Record(auditEntry(api, entry, applied, err))
+	if err != nil {
+		return errors.Join(err, aerr)
+	}
+
+	if err := journal.MarkUndone(entry.Id); err != nil {
+		return errors.Join(err, aerr)
+	}
+
+	// A temporary grant that has been undone has nothing left to revoke
+	if err := lease.MarkUndone(entry.Id); err != nil {
+		return errors.Join(err, aerr)
+	}
+
+	fmt.Fprintf(out, "✔ Operation #%s has been undone.\n", entry.Id)
+	return aerr
+}
+
+// revert performs the inverse calls in order and stops at the first failure. It returns the calls
+// that were performed.
+func revert(api *internalaws.Api, calls []internalaws.Call) ([]internalaws.Call, error) {
+	var applied []internalaws.Call
+	for i, call := range calls {
+		if err := api.Execute(context.Background(), call); err != nil {
+			return applied, fmt.Errorf("step %d (%s) failed: %w", i+1, call.Action, err)
+		}
+		applied = append(applied, call)
+	}
+	return applied, nil
+}
+
+func auditEntry(api *internalaws.Api, entry journal.Entry, applied []internalaws.Call, err error) audit.Entry {
+	record := audit.Entry{
+		Caller:    "unknown",
+		Flow:      entry.Flow,
+		Operation: UndoOperationPrefix + entry.Operation,
+		Principal: entry.Principal,
+		Calls:     applied,
+		Outcome:   audit.OutcomeSuccess,
+	}
+
+	for _, call := range entry.Inverse {
+		if arn := call.Param("PolicyArn"); arn != "" {
+			record.PolicyArn = arn
+		}
+		if group := call.Param("GroupName"); group != "" && entry.Flow == "users" {
+			record.Group = group
+		}
+	}
+
+	if arn, account, ierr := api.CallerIdentity(context.Background()); ierr == nil {
+		record.Caller, record.Account = arn, account
+	}
+	if err != nil {
+		record.Outcome = audit.OutcomeFailure
+		record.Error = err.Error()
+	}
+
+	return record
+}
+
+func listEntries(cmd *cobra.Command) error {
+	entries, err := journal.List()
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	if len(entries) == 0 {
+		fmt.Fprintln(out, "The journal is empty.")
+		return nil
+	}
+
+	for _, entry := range entries {
+		status := ""
+		if entry.UndoneAt != nil {
+			status = "(undone)"
+		}
+		fmt.Fprintf(out, "%4s  %s  %-8s %-22s %s %s\n", entry.Id, entry.Time.Local().Format("2006-01-02 15:04:05"), entry.Flow, entry.Operation, entry.Principal, status)
+	}
+
+	return nil
+}